@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"time"
@@ -18,9 +19,20 @@ func main() {
 	// Set global log level
 	utilslog.InitializeGlobal(cfg.Log)
 
+	// Probes report the consumer as not ready until schemas are preloaded
+	health := internal.NewHealth(cfg.Health)
+	health.Start()
+	defer health.Shutdown(context.Background())
+
 	// Create the kafka consumer
 	consumer := internal.NewConsumer(*cfg)
 
+	zlog.Info().Msg("preloading schemas...")
+	if err := consumer.Preload(context.Background()); err != nil {
+		zlog.Warn().Err(err).Msg("failed to preload schemas, they will be fetched on first use")
+	}
+	health.SetReady(true)
+
 	stopped := make(chan byte)
 	go func() {
 		defer close(stopped)
@@ -37,6 +49,7 @@ func main() {
 	select {
 	case <-sigs:
 		zlog.Info().Msg("received interrupt signal. Stopping polling...")
+		health.SetReady(false)
 		go func() {
 			defer close(done)
 			consumer.Close()
@@ -32,16 +32,40 @@ type Consumer struct {
 	MaxPollRecords              int             `json:"maxPollRecords" koanf:"maxPollRecords,required,gt=0"`
 	PartitionRecordsChannelSize int             `json:"partitionRecordsChannelSize" koanf:"partitionRecordsChannelSize,required,gte=5"`
 	CloseTimeout                time.Duration   `json:"closeTimeout" koanf:"closeTimeout,required"`
+	Health                      Health          `json:"health" koanf:"health"`
+	// Topics holds the schema policy of each topic, by topic name. Records of topics without a policy may reference any
+	// schema
+	Topics map[string]TopicPolicy `json:"topics" koanf:"topics"`
+}
+
+// TopicPolicy restricts the schemas records of a topic are validated against
+type TopicPolicy struct {
+	// Schemas lists the allowed schemas, as <name> for any version, or <name>@<constraint> with a semver constraint
+	// such as order@^2 or order@>=1.2 <3
+	Schemas []string `json:"schemas" koanf:"schemas"`
+	// DefaultSchema is the <name>@<version> of the schema used for records without a schemaURI header. Those records
+	// are rejected when empty
+	DefaultSchema string `json:"defaultSchema" koanf:"defaultSchema"`
+}
+
+// Health configures the liveness (/healthz) and readiness (/readyz) probes, also serving the metrics (/metrics)
+type Health struct {
+	// Port serves the probes and metrics, zero disables them
+	Port int `json:"port" koanf:"port"`
 }
 
 type Kafka struct {
 	Brokers string `json:"brokers" koanf:"brokers"`
 	Group   string `json:"group" koanf:"group"`
 	Topics  string `json:"topics" koanf:"topics"`
+	// DeadLetterTopic receives the records rejected by the topic policies or by validation. Rejected records are only
+	// logged when empty
+	DeadLetterTopic string `json:"deadLetterTopic" koanf:"deadLetterTopic"`
 }
 
 func (k Kafka) MarshalZerologObject(e *zerolog.Event) {
 	e.Str("brokers", k.Brokers).
 		Str("group", k.Group).
-		Str("topics", k.Topics)
+		Str("topics", k.Topics).
+		Str("deadLetterTopic", k.DeadLetterTopic)
 }
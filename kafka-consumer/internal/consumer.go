@@ -2,7 +2,6 @@ package internal
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -17,6 +16,13 @@ import (
 	sbmodels "github.com/mfelipe/go-feijoada/stream-buffer/models"
 )
 
+const (
+	// schemaURIHeader references the schema of a record
+	schemaURIHeader = "schemaURI"
+	// contentTypeHeader is the encoding of a record value, JSON when absent. See schemavalidator.SchemaValidator.Decode
+	contentTypeHeader = "contentType"
+)
+
 // This implementation is based on examples from the frans-go module, more specifically the one for consuming with a
 // go routine per partition and manual batch commiting:
 // https://github.com/twmb/franz-go/blob/master/examples/goroutine_per_partition_consuming/
@@ -24,9 +30,12 @@ import (
 type pconsumer struct {
 	stream    streambuffer.Stream
 	validator schemavalidator.SchemaValidator
+	policy    *topicPolicy
 	kcli      *kgo.Client
-	topic     string
-	partition int32
+	// deadLetterTopic receives the rejected records, which are only logged when empty
+	deadLetterTopic string
+	topic           string
+	partition       int32
 
 	quit    chan struct{}
 	done    chan struct{}
@@ -42,6 +51,7 @@ type Consumer struct {
 	cfg       config.Consumer
 	stream    streambuffer.Stream
 	validator schemavalidator.SchemaValidator
+	policies  map[string]*topicPolicy
 	kcli      *kgo.Client
 	consumers map[consumerKey]*pconsumer
 }
@@ -54,6 +64,12 @@ func NewConsumer(cfg config.Consumer) *Consumer {
 		consumers: make(map[consumerKey]*pconsumer),
 	}
 
+	policies, err := newTopicPolicies(cfg.Topics, cfg.SchemaValidator.DefaultBaseURI)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("failed to parse the topic policies")
+	}
+	c.policies = policies
+
 	zlog.Info().EmbedObject(cfg.Kafka).Msg("creating kafka client...")
 	client, err := kgo.NewClient(
 		kgo.SeedBrokers(strings.Split(cfg.Kafka.Brokers, ",")...),
@@ -88,14 +104,19 @@ func (pc *pconsumer) consume(_ context.Context) {
 			return
 		case recs := <-pc.records:
 			ctx := context.Background()
-			validMsgs := pc.validateRecords(ctx, recs)
+			validMsgs, rejected := pc.validateRecords(ctx, recs)
 			err := pc.addToStream(ctx, validMsgs)
 			if err != nil {
 				zlog.Error().Err(err).Str("topic", pc.topic).Int32("partition", pc.partition).Msg("error when adding messages to the stream")
 				continue
 			}
+			err = pc.deadLetter(ctx, rejected)
+			if err != nil {
+				zlog.Error().Err(err).Str("topic", pc.topic).Int32("partition", pc.partition).Msg("error when dead lettering rejected records")
+				continue
+			}
 
-			zlog.Debug().Str("topic", pc.topic).Int32("partition", pc.partition).Int("messages", len(validMsgs)).Msg("messages validated and added to the stream, about to commit")
+			zlog.Debug().Str("topic", pc.topic).Int32("partition", pc.partition).Int("messages", len(validMsgs)).Int("rejected", len(rejected)).Msg("messages validated and added to the stream, about to commit")
 			err = pc.kcli.CommitRecords(ctx, recs...)
 			if err != nil {
 				zlog.Error().Err(err).Str("topic", pc.topic).Int32("partition", pc.partition).Int64("offset", recs[len(recs)-1].Offset+1).Msg("error when committing offsets to kafka")
@@ -104,18 +125,19 @@ func (pc *pconsumer) consume(_ context.Context) {
 	}
 }
 
-// validateRecords perform schema validation against the pulled records and return the valid ones
-// TODO: DLQ for invalids and the ones with error during validation?
-func (pc *pconsumer) validateRecords(ctx context.Context, records []*kgo.Record) []*sbmodels.Message {
+// validateRecords perform schema validation against the pulled records and return the valid ones, along with the
+// rejected ones and why they were rejected
+func (pc *pconsumer) validateRecords(ctx context.Context, records []*kgo.Record) ([]*sbmodels.Message, []rejection) {
 	messages := make([]*sbmodels.Message, 0)
+	rejected := make([]rejection, 0)
 
 	for _, r := range records {
-		var schemaURI string
-		for _, h := range r.Headers {
-			if h.Key == "schemaURI" {
-				schemaURI = string(h.Value)
-				break
-			}
+		// Records must reference a schema allowed in the topic
+		schemaURI, err := pc.policy.resolve(headerValue(r, schemaURIHeader))
+		if err != nil {
+			zlog.Error().Err(err).Str("topic", r.Topic).Msgf("record (key %s) rejected by the topic policy. Will be ignored", r.Key)
+			rejected = append(rejected, rejection{record: r, reason: reasonPolicy, err: err})
+			continue
 		}
 
 		msg := sbmodels.Message{
@@ -125,18 +147,20 @@ func (pc *pconsumer) validateRecords(ctx context.Context, records []*kgo.Record)
 			Data:      r.Value,
 		}
 
-		// Try to validate the data against a json schema
-		if valid, err := pc.validateMessage(ctx, msg); err != nil {
+		// Validate the data against its schema, binary data being replaced by its canonical JSON
+		if report, err := pc.decodeMessage(ctx, &msg, headerValue(r, contentTypeHeader)); err != nil {
 			zlog.Error().Object("message", &msg).Err(err).Msgf("failed to validate data from record (topic %s - key %s). Will be ignored", r.Topic, r.Key)
-		} else if !valid {
-			zlog.Error().Object("message", &msg).Msgf("data from record (topic %s - key %s) is not a valid \"%s\" schema. Will be ignored", r.Topic, r.Key, msg.SchemaURI)
+			rejected = append(rejected, rejection{record: r, reason: reasonError, err: err})
+		} else if !report.Valid {
+			zlog.Error().Object("message", &msg).Interface("report", report).Msgf("data from record (topic %s - key %s) is not a valid \"%s\" schema. Will be ignored", r.Topic, r.Key, msg.SchemaURI)
+			rejected = append(rejected, rejection{record: r, reason: reasonInvalid, report: report})
 		} else {
 			zlog.Info().Object("message", &msg).Msgf("polled record with id %s from topic %s", r.Key, r.Topic)
 			messages = append(messages, &msg)
 		}
 	}
 
-	return messages
+	return messages, rejected
 }
 
 func (pc *pconsumer) addToStream(ctx context.Context, msgs []*sbmodels.Message) error {
@@ -164,23 +188,34 @@ func (pc *pconsumer) addToStream(ctx context.Context, msgs []*sbmodels.Message)
 	return err
 }
 
-func (pc *pconsumer) validateMessage(_ context.Context, msg sbmodels.Message) (bool, error) {
-	vResult, vErr := pc.validator.Validate(msg.SchemaURI, msg.Data)
-	if vErr != nil {
-		zlog.Error().Err(vErr).Msg("failed to validate json schema data")
-		return false, vErr
+// decodeMessage validates the message data, encoded as contentType, against its schema. Valid binary data is replaced
+// by its canonical JSON, so the stream only holds JSON whatever the encoding of the records
+func (pc *pconsumer) decodeMessage(_ context.Context, msg *sbmodels.Message, contentType string) (*schemavalidator.Report, error) {
+	data, report, err := pc.validator.Decode(msg.SchemaURI, contentType, msg.Data)
+	if err != nil {
+		zlog.Error().Err(err).Str("contentType", contentType).Msg("failed to validate data")
+		return nil, err
+	}
+	if report.Valid {
+		msg.Data = data
 	}
+	return report, nil
+}
 
-	if !vResult.IsValid() {
-		resultList := vResult.ToList()
-		var rErr error
-		for e := range resultList.Errors {
-			errors.Join(rErr, errors.New(e))
+// headerValue returns the value of the first record header with the given key, empty when absent
+func headerValue(r *kgo.Record, key string) string {
+	for _, h := range r.Headers {
+		if h.Key == key {
+			return string(h.Value)
 		}
-
-		zlog.Error().Err(rErr).Str("schemaURI", msg.SchemaURI).Msg("data is not a valid schema")
 	}
-	return vResult.IsValid(), nil
+	return ""
+}
+
+// Preload compiles the schemas selected by the schema validator configuration, so the first records polled don't wait
+// for schema-repository
+func (c *Consumer) Preload(ctx context.Context) error {
+	return c.validator.Preload(ctx)
 }
 
 func (c *Consumer) Close() {
@@ -226,11 +261,13 @@ func (c *Consumer) assigned(ctx context.Context, cl *kgo.Client, assigned map[st
 	for topic, partitions := range assigned {
 		for _, partition := range partitions {
 			pc := &pconsumer{
-				kcli:      cl,
-				stream:    c.stream,
-				validator: c.validator,
-				topic:     topic,
-				partition: partition,
+				kcli:            cl,
+				stream:          c.stream,
+				validator:       c.validator,
+				policy:          c.policies[topic],
+				deadLetterTopic: c.cfg.Kafka.DeadLetterTopic,
+				topic:           topic,
+				partition:       partition,
 
 				quit:    make(chan struct{}),
 				done:    make(chan struct{}),
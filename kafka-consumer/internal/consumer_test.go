@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	schemavalidator "github.com/mfelipe/go-feijoada/schema-validator"
+	svcfg "github.com/mfelipe/go-feijoada/schema-validator/config"
+)
+
+const avroUser = `{"type": "record", "name": "User", "fields": [{"name": "name", "type": "string"}]}`
+
+func TestPconsumer_ValidateRecords(t *testing.T) {
+	schemas := map[string]string{
+		"/schemas/user/1.0.0": `{"schema": {"type": "object", "required": ["name"]}}`,
+		"/schemas/user/2.0.0": `{"type": "avro", "schema": ` + avroUser + `}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		schema, ok := schemas[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(schema))
+	}))
+	defer server.Close()
+
+	codec, err := goavro.NewCodec(avroUser)
+	require.NoError(t, err)
+	binary, err := codec.BinaryFromNative(nil, map[string]any{"name": "Ana"})
+	require.NoError(t, err)
+
+	record := func(version, contentType string, value []byte) *kgo.Record {
+		headers := []kgo.RecordHeader{{Key: schemaURIHeader, Value: []byte(server.URL + "/schemas/user/" + version)}}
+		if contentType != "" {
+			headers = append(headers, kgo.RecordHeader{Key: contentTypeHeader, Value: []byte(contentType)})
+		}
+		return &kgo.Record{Topic: "users", Value: value, Headers: headers}
+	}
+
+	pc := &pconsumer{validator: schemavalidator.New(svcfg.Config{DefaultBaseURI: server.URL})}
+	messages, rejected := pc.validateRecords(context.Background(), []*kgo.Record{
+		record("1.0.0", "", []byte(`{"name": "Ana"}`)),
+		record("2.0.0", schemavalidator.ContentTypeAvro, binary),
+		record("1.0.0", schemavalidator.ContentTypeJSON, []byte(`{}`)),
+		record("2.0.0", schemavalidator.ContentTypeAvro, binary[:1]),
+		record("1.0.0", schemavalidator.ContentTypeAvro, binary),
+	})
+
+	// Valid records reach the stream as JSON, whatever their encoding
+	require.Len(t, messages, 2)
+	assert.JSONEq(t, `{"name": "Ana"}`, string(messages[0].Data))
+	assert.JSONEq(t, `{"name": "Ana"}`, string(messages[1].Data))
+
+	require.Len(t, rejected, 3)
+	assert.Equal(t, reasonInvalid, rejected[0].reason)
+	assert.Equal(t, reasonInvalid, rejected[1].reason)
+	// Rejected records keep their original value
+	assert.Equal(t, binary[:1], rejected[1].record.Value)
+	assert.Equal(t, reasonError, rejected[2].reason)
+	assert.ErrorIs(t, rejected[2].err, schemavalidator.ErrUnsupportedContentType)
+}
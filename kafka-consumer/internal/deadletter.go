@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	zlog "github.com/rs/zerolog/log"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	schemavalidator "github.com/mfelipe/go-feijoada/schema-validator"
+)
+
+// Reasons for rejecting a record, as reported in dead letters and metrics
+const (
+	reasonPolicy  = "policy"
+	reasonInvalid = "invalid"
+	reasonError   = "error"
+)
+
+// Headers added to dead letters, on top of the original record headers
+const (
+	headerReason    = "dlqReason"
+	headerError     = "dlqError"
+	headerTopic     = "dlqTopic"
+	headerPartition = "dlqPartition"
+	headerOffset    = "dlqOffset"
+	headerReport    = "validationReport"
+)
+
+// rejection is a record that is not added to the stream, with the validation report of invalid records
+type rejection struct {
+	record *kgo.Record
+	reason string
+	err    error
+	report *schemavalidator.Report
+}
+
+// deadLetter counts the rejected records, and produces them to the dead letter topic when configured
+func (pc *pconsumer) deadLetter(ctx context.Context, rejected []rejection) error {
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	records := make([]*kgo.Record, 0, len(rejected))
+	for _, rj := range rejected {
+		recordsRejected.WithLabelValues(rj.record.Topic, rj.reason).Inc()
+		if rj.report != nil {
+			for _, e := range rj.report.Errors {
+				validationErrors.WithLabelValues(rj.record.Topic, e.Keyword).Inc()
+			}
+		}
+
+		if pc.deadLetterTopic != "" {
+			records = append(records, rj.deadLetterRecord(pc.deadLetterTopic))
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := pc.kcli.ProduceSync(ctx, records...).FirstErr(); err != nil {
+		return err
+	}
+	zlog.Debug().Str("topic", pc.topic).Int32("partition", pc.partition).Int("records", len(records)).Msg("rejected records sent to the dead letter topic")
+	return nil
+}
+
+// deadLetterRecord copies the rejected record, adding headers about where it comes from and why it was rejected
+func (rj rejection) deadLetterRecord(topic string) *kgo.Record {
+	r := rj.record
+	headers := append(make([]kgo.RecordHeader, 0, len(r.Headers)+6), r.Headers...)
+	headers = append(headers,
+		kgo.RecordHeader{Key: headerReason, Value: []byte(rj.reason)},
+		kgo.RecordHeader{Key: headerTopic, Value: []byte(r.Topic)},
+		kgo.RecordHeader{Key: headerPartition, Value: []byte(strconv.Itoa(int(r.Partition)))},
+		kgo.RecordHeader{Key: headerOffset, Value: []byte(strconv.FormatInt(r.Offset, 10))},
+	)
+	if rj.err != nil {
+		headers = append(headers, kgo.RecordHeader{Key: headerError, Value: []byte(rj.err.Error())})
+	}
+	if rj.report != nil {
+		if report, err := json.Marshal(rj.report); err == nil {
+			headers = append(headers, kgo.RecordHeader{Key: headerReport, Value: report})
+		}
+	}
+
+	return &kgo.Record{
+		Topic:     topic,
+		Key:       r.Key,
+		Value:     r.Value,
+		Headers:   headers,
+		Timestamp: r.Timestamp,
+	}
+}
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	schemavalidator "github.com/mfelipe/go-feijoada/schema-validator"
+)
+
+func headerValues(r *kgo.Record) map[string]string {
+	headers := make(map[string]string)
+	for _, h := range r.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	return headers
+}
+
+func TestRejection_DeadLetterRecord(t *testing.T) {
+	record := &kgo.Record{
+		Topic:     "user-topic",
+		Partition: 2,
+		Offset:    42,
+		Key:       []byte("key"),
+		Value:     []byte(`{"name": 1}`),
+		Headers:   []kgo.RecordHeader{{Key: "schemaURI", Value: []byte("http://schema-repository:8080/schemas/user/1.0.0")}},
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		report := &schemavalidator.Report{Errors: []schemavalidator.ValidationError{
+			{InstanceLocation: "/name", Keyword: "type", Message: "Value is integer but should be string", Expected: "string", Actual: "integer"},
+		}}
+		dl := rejection{record: record, reason: reasonInvalid, report: report}.deadLetterRecord("dead-letters")
+
+		assert.Equal(t, "dead-letters", dl.Topic)
+		assert.Equal(t, record.Key, dl.Key)
+		assert.Equal(t, record.Value, dl.Value)
+
+		headers := headerValues(dl)
+		assert.Equal(t, "http://schema-repository:8080/schemas/user/1.0.0", headers["schemaURI"])
+		assert.Equal(t, reasonInvalid, headers[headerReason])
+		assert.Equal(t, "user-topic", headers[headerTopic])
+		assert.Equal(t, "2", headers[headerPartition])
+		assert.Equal(t, "42", headers[headerOffset])
+		assert.NotContains(t, headers, headerError)
+
+		var attached schemavalidator.Report
+		require.NoError(t, json.Unmarshal([]byte(headers[headerReport]), &attached))
+		assert.Equal(t, *report, attached)
+	})
+
+	t.Run("rejected by policy", func(t *testing.T) {
+		dl := rejection{record: record, reason: reasonPolicy, err: errSchemaNotAllowed}.deadLetterRecord("dead-letters")
+
+		headers := headerValues(dl)
+		assert.Equal(t, reasonPolicy, headers[headerReason])
+		assert.Equal(t, errSchemaNotAllowed.Error(), headers[headerError])
+		assert.NotContains(t, headers, headerReport)
+		// The original record is left untouched
+		assert.Len(t, record.Headers, 1)
+	})
+}
+
+func TestPconsumer_DeadLetterCounts(t *testing.T) {
+	pc := &pconsumer{topic: "count-topic"}
+	record := &kgo.Record{Topic: "count-topic"}
+	report := &schemavalidator.Report{Errors: []schemavalidator.ValidationError{{Keyword: "type"}, {Keyword: "type"}, {Keyword: "required"}}}
+
+	// Without a dead letter topic, rejected records are only counted
+	err := pc.deadLetter(context.Background(), []rejection{
+		{record: record, reason: reasonInvalid, report: report},
+		{record: record, reason: reasonError, err: errors.New("schema not found")},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(recordsRejected.WithLabelValues("count-topic", reasonInvalid)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(recordsRejected.WithLabelValues("count-topic", reasonError)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(validationErrors.WithLabelValues("count-topic", "type")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(validationErrors.WithLabelValues("count-topic", "required")))
+}
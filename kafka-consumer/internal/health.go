@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	zlog "github.com/rs/zerolog/log"
+
+	"github.com/mfelipe/go-feijoada/kafka-consumer/config"
+)
+
+// Health answers liveness and readiness probes, and serves the metrics. The consumer is live as soon as it starts, and only ready once the
+// schemas are preloaded and records are being polled.
+type Health struct {
+	ready  atomic.Bool
+	server *http.Server
+}
+
+func NewHealth(cfg config.Health) *Health {
+	h := &Health{}
+	if cfg.Port == 0 {
+		return h
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if !h.ready.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("GET /metrics", promhttp.Handler())
+	h.server = &http.Server{Addr: fmt.Sprintf(":%d", cfg.Port), Handler: mux}
+
+	return h
+}
+
+// Start serves the probes in the background, unless no port is configured
+func (h *Health) Start() {
+	if h.server == nil {
+		return
+	}
+
+	zlog.Info().Str("address", h.server.Addr).Msg("starting health probes server")
+	go func() {
+		if err := h.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zlog.Error().Err(err).Msg("health probes server stopped")
+		}
+	}()
+}
+
+func (h *Health) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+func (h *Health) Shutdown(ctx context.Context) {
+	if h.server == nil {
+		return
+	}
+	if err := h.server.Shutdown(ctx); err != nil {
+		zlog.Error().Err(err).Msg("failed to shutdown the health probes server")
+	}
+}
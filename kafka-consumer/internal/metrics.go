@@ -0,0 +1,18 @@
+package internal
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	recordsRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consumer_records_rejected_total",
+		Help: "Records not added to the stream, by topic and reason (policy, invalid or error)",
+	}, []string{"topic", "reason"})
+
+	validationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_consumer_validation_errors_total",
+		Help: "Failed schema assertions of invalid records, by topic and JSON schema keyword",
+	}, []string{"topic", "keyword"})
+)
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/mfelipe/go-feijoada/kafka-consumer/config"
+)
+
+var (
+	errMissingSchema      = errors.New("record has no schemaURI header")
+	errUnknownSchemaURI   = errors.New("schemaURI is not a schema-repository schema")
+	errSchemaNotAllowed   = errors.New("schema is not allowed in the topic")
+	errVersionNotAllowed  = errors.New("schema version is not allowed in the topic")
+	errInvalidTopicPolicy = errors.New("invalid topic policy")
+)
+
+// topicPolicy is the parsed form of config.TopicPolicy
+type topicPolicy struct {
+	baseURI string
+	// allowed holds the version constraints of each allowed schema name, a nil constraint allowing any version
+	allowed    map[string][]*semver.Constraints
+	defaultURI string
+}
+
+func newTopicPolicies(topics map[string]config.TopicPolicy, baseURI string) (map[string]*topicPolicy, error) {
+	policies := make(map[string]*topicPolicy, len(topics))
+	for topic, cfg := range topics {
+		p, err := newTopicPolicy(cfg, baseURI)
+		if err != nil {
+			return nil, fmt.Errorf("topic %s: %w", topic, err)
+		}
+		policies[topic] = p
+	}
+	return policies, nil
+}
+
+func newTopicPolicy(cfg config.TopicPolicy, baseURI string) (*topicPolicy, error) {
+	p := &topicPolicy{
+		baseURI: strings.TrimSuffix(baseURI, "/") + "/schemas/",
+		allowed: make(map[string][]*semver.Constraints),
+	}
+
+	for _, s := range cfg.Schemas {
+		name, constraint, ranged := strings.Cut(s, "@")
+		if name == "" {
+			return nil, fmt.Errorf("%w: empty schema name in %q", errInvalidTopicPolicy, s)
+		}
+		if !ranged {
+			p.allowed[name] = append(p.allowed[name], nil)
+			continue
+		}
+
+		c, err := semver.NewConstraint(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", errInvalidTopicPolicy, s, err)
+		}
+		p.allowed[name] = append(p.allowed[name], c)
+	}
+
+	if cfg.DefaultSchema != "" {
+		name, version, ok := strings.Cut(cfg.DefaultSchema, "@")
+		if !ok || name == "" || version == "" {
+			return nil, fmt.Errorf("%w: default schema %q is not <name>@<version>", errInvalidTopicPolicy, cfg.DefaultSchema)
+		}
+		p.defaultURI = p.baseURI + name + "/" + version
+		if _, err := p.resolve(p.defaultURI); err != nil {
+			return nil, fmt.Errorf("%w: default schema: %w", errInvalidTopicPolicy, err)
+		}
+	}
+
+	return p, nil
+}
+
+// resolve checks the schemaURI of a record against the policy, returning the schemaURI to validate the record with.
+// Records without schemaURI get the default schema, if any. A nil policy allows any schema.
+func (p *topicPolicy) resolve(schemaURI string) (string, error) {
+	if p == nil {
+		return schemaURI, nil
+	}
+	if schemaURI == "" {
+		if p.defaultURI == "" {
+			return "", errMissingSchema
+		}
+		return p.defaultURI, nil
+	}
+
+	// Only schemas served by schema-repository are allowed, so records can't point the validator somewhere else
+	path, ok := strings.CutPrefix(schemaURI, p.baseURI)
+	name, v, found := strings.Cut(path, "/")
+	if !ok || !found || name == "" || strings.Contains(v, "/") {
+		return "", fmt.Errorf("%w: %s", errUnknownSchemaURI, schemaURI)
+	}
+
+	constraints, ok := p.allowed[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", errSchemaNotAllowed, name)
+	}
+	version, err := semver.NewVersion(v)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s@%s: %w", errVersionNotAllowed, name, v, err)
+	}
+	for _, c := range constraints {
+		if c == nil || c.Check(version) {
+			return schemaURI, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s@%s", errVersionNotAllowed, name, v)
+}
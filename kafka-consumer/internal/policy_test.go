@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/kafka-consumer/config"
+)
+
+const baseURI = "http://schema-repository:8080"
+
+func TestTopicPolicy_Resolve(t *testing.T) {
+	policy, err := newTopicPolicy(config.TopicPolicy{
+		Schemas:       []string{"order@^2", "order@~1.4", "user"},
+		DefaultSchema: "order@2.1.0",
+	}, baseURI)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		schemaURI string
+		expected  string
+		err       error
+	}{
+		{name: "allowed range", schemaURI: baseURI + "/schemas/order/2.3.1", expected: baseURI + "/schemas/order/2.3.1"},
+		{name: "second range", schemaURI: baseURI + "/schemas/order/1.4.2", expected: baseURI + "/schemas/order/1.4.2"},
+		{name: "any version", schemaURI: baseURI + "/schemas/user/7.0.0", expected: baseURI + "/schemas/user/7.0.0"},
+		{name: "default schema", schemaURI: "", expected: baseURI + "/schemas/order/2.1.0"},
+		{name: "out of range", schemaURI: baseURI + "/schemas/order/3.0.0", err: errVersionNotAllowed},
+		{name: "invalid version", schemaURI: baseURI + "/schemas/order/latest", err: errVersionNotAllowed},
+		{name: "not allowed", schemaURI: baseURI + "/schemas/payment/1.0.0", err: errSchemaNotAllowed},
+		{name: "other host", schemaURI: "http://elsewhere/schemas/order/2.0.0", err: errUnknownSchemaURI},
+		{name: "nested path", schemaURI: baseURI + "/schemas/order/2.0.0/extra", err: errUnknownSchemaURI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri, err := policy.resolve(tt.schemaURI)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, uri)
+		})
+	}
+}
+
+func TestTopicPolicy_WithoutDefault(t *testing.T) {
+	policy, err := newTopicPolicy(config.TopicPolicy{Schemas: []string{"user"}}, baseURI)
+	require.NoError(t, err)
+
+	_, err = policy.resolve("")
+	assert.ErrorIs(t, err, errMissingSchema)
+
+	// Topics without a policy accept anything
+	var none *topicPolicy
+	uri, err := none.resolve("http://elsewhere/any")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://elsewhere/any", uri)
+}
+
+func TestNewTopicPolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy config.TopicPolicy
+	}{
+		{name: "invalid constraint", policy: config.TopicPolicy{Schemas: []string{"order@^two"}}},
+		{name: "empty name", policy: config.TopicPolicy{Schemas: []string{"@^2"}}},
+		{name: "default without version", policy: config.TopicPolicy{Schemas: []string{"order"}, DefaultSchema: "order"}},
+		{name: "default not allowed", policy: config.TopicPolicy{Schemas: []string{"order@^2"}, DefaultSchema: "order@1.0.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTopicPolicies(map[string]config.TopicPolicy{"order-topic": tt.policy}, baseURI)
+			assert.ErrorIs(t, err, errInvalidTopicPolicy)
+			assert.ErrorContains(t, err, "order-topic")
+		})
+	}
+}
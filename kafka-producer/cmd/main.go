@@ -2,20 +2,16 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"github.com/brianvoe/gofakeit/v6"
-	"github.com/mfelipe/go-feijoada/kafka-producer/config"
-	"github.com/mfelipe/go-feijoada/schemas/models/v1_0_0"
-	"github.com/mfelipe/go-feijoada/schemas/models/v2_0_0"
-	utilslog "github.com/mfelipe/go-feijoada/utils/log"
-	zlog "github.com/rs/zerolog/log"
-	"github.com/twmb/franz-go/pkg/kgo"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
-	"time"
+
+	zlog "github.com/rs/zerolog/log"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+	"github.com/mfelipe/go-feijoada/kafka-producer/internal"
+	utilslog "github.com/mfelipe/go-feijoada/utils/log"
 )
 
 func main() {
@@ -24,116 +20,46 @@ func main() {
 	// Set global log level
 	utilslog.InitializeGlobal(cfg.Log)
 
-	client, err := kgo.NewClient(
-		kgo.SeedBrokers(strings.Split(cfg.Kafka.Brokers, ",")...),
-	)
+	opts, err := internal.ClientOptions(cfg.Kafka)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("invalid kafka configuration")
+	}
+	client, err := kgo.NewClient(opts...)
 	if err != nil {
 		zlog.Fatal().Err(err).Msg("failed to create kafka client")
 	}
 	defer client.Close()
+	producer := internal.NewProducer(client, cfg.Kafka)
+	zlog.Info().EmbedObject(cfg.Kafka).Msg("Kafka client created")
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	zlog.Info().Msg("Starting kafka producer...")
-
-	for {
-		time.Sleep(100 * time.Millisecond)
-		select {
-		case <-ctx.Done():
-			zlog.Info().Msg("Shutting down kafka producer...")
-			return
-		default:
-			record, err := newRecord()
-			if err != nil {
-				zlog.Error().Err(err).Msg("failed to create new record")
-				continue
-			}
-
-			zlog.Info().Str("topic", record.Topic).Msg("Producing record")
-			client.Produce(ctx, record, func(r *kgo.Record, err error) {
-				if err != nil {
-					zlog.Error().Err(err).Msg("failed to produce record")
-				}
-			})
-		}
+	if cfg.Replay.File != "" {
+		replay(ctx, producer, cfg)
+		return
 	}
-}
-
-func newRecord() (*kgo.Record, error) {
-	model, name, version := generateModel()
 
-	modelBytes, err := json.Marshal(&model)
+	load, err := internal.NewLoad(producer, cfg)
 	if err != nil {
-		zlog.Error().Err(err).Msg("failed to marshal model")
-		return nil, err
+		zlog.Fatal().Err(err).Msg("invalid load profile")
 	}
 
-	return &kgo.Record{
-		Value: modelBytes,
-		Headers: []kgo.RecordHeader{
-			{Key: "schemaURI", Value: []byte(fmt.Sprintf("http://schema-repository:8080/schemas/%s/%s", name, version))},
-		},
-		//Key:   []byte(name + version),
-		Topic: fmt.Sprintf("%s-topic", name),
-	}, nil
+	zlog.Info().EmbedObject(cfg.Profile).Msg("Starting kafka producer...")
+	summary := load.Run(ctx)
+	zlog.Info().EmbedObject(summary).Msg("Shutting down kafka producer...")
 }
 
-var genIndex uint8
-
-func generateModel() (model any, name string, version string) {
-	genIndex++
-
-	switch genIndex {
-	case 1:
-		model = newFakeModel[v1_0_0.User]()
-		name = "user"
-		version = "1.0.0"
-	case 2:
-		model = newFakeModel[v1_0_0.Address]()
-		name = "address"
-		version = "1.0.0"
-	case 3:
-		model = newFakeModel[v1_0_0.Order]()
-		name = "order"
-		version = "1.0.0"
-	case 4:
-		model = newFakeModel[v1_0_0.Payment]()
-		name = "payment"
-		version = "1.0.0"
-	case 5:
-		model = newFakeModel[v1_0_0.Product]()
-		name = "product"
-		version = "1.0.0"
-	case 6:
-		model = newFakeModel[v2_0_0.Address]()
-		name = "address"
-		version = "2.0.0"
-	case 7:
-		model = newFakeModel[v2_0_0.Order]()
-		model = v2_0_0.Order{}
-		name = "order"
-		version = "2.0.0"
-	case 8:
-		model = newFakeModel[v2_0_0.Payment]()
-		model = v2_0_0.Payment{}
-		name = "payment"
-		version = "2.0.0"
-	default:
-		genIndex = 0
-		model = newFakeModel[v2_0_0.Product]()
-		model = v2_0_0.Product{}
-		name = "product"
-		version = "2.0.0"
+func replay(ctx context.Context, producer internal.Producer, cfg *config.Consumer) {
+	r, err := internal.NewReplay(producer, cfg)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("invalid replay")
 	}
-	return
-}
 
-func newFakeModel[T any]() any {
-	var model T
-	if err := gofakeit.Struct(&model); err != nil {
-		zlog.Error().Err(err).Msg("failed to create fake model")
-		return nil
+	zlog.Info().EmbedObject(cfg.Replay).Msg("Starting kafka producer replay...")
+	summary, err := r.Run(ctx)
+	if err != nil {
+		zlog.Error().Err(err).Msg("replay stopped")
 	}
-	return model
+	zlog.Info().EmbedObject(summary).Msg("Shutting down kafka producer...")
 }
@@ -2,8 +2,15 @@ package config
 
 import (
 	_ "embed"
+	"fmt"
+	"log"
+	"os"
+	"slices"
 	"time"
 
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
 	"github.com/rs/zerolog"
 
 	utilscfg "github.com/mfelipe/go-feijoada/utils/config"
@@ -17,20 +24,300 @@ const (
 //go:embed base.yaml
 var baseCfg []byte
 
+// Load loads the configuration, replacing its profile by the one of ProfileFile when set
 func Load() *Consumer {
-	return utilscfg.Load[Consumer](prefix, baseCfg)
+	cfg := utilscfg.Load[Consumer](prefix, baseCfg)
+	if cfg.ProfileFile != "" {
+		profile, err := LoadProfile(cfg.ProfileFile)
+		if err != nil {
+			log.Panicf("error loading load profile: %v", err)
+		}
+		cfg.Profile = *profile
+	}
+	return cfg
 }
 
 type Consumer struct {
 	Log          utilslog.Config `json:"log" koanf:"log"`
 	Kafka        Kafka           `json:"kafka" koanf:"kafka,required"`
 	CloseTimeout time.Duration   `json:"closeTimeout" koanf:"closeTimeout,required"`
+	// Profile describes the load to produce
+	Profile Profile `json:"profile" koanf:"profile"`
+	// ProfileFile is the path of a YAML file holding a profile, replacing Profile
+	ProfileFile string `json:"profileFile" koanf:"profileFile"`
+	// Schemas locates the schemas payloads are synthesized from
+	Schemas Schemas `json:"schemas" koanf:"schemas"`
+	// Replay publishes a recorded dataset instead of following Profile, when its file is set
+	Replay Replay `json:"replay" koanf:"replay"`
 }
 
+// Formats of replayed files
+const (
+	// FormatJSON files hold JSON objects, such as NDJSON files
+	FormatJSON = "json"
+	// FormatDynamoDB files hold DynamoDB JSON items, such as the lines of table exports ({"Item": {"id": {"S": ...}}})
+	// or the output of aws dynamodb scan ({"Items": [...]})
+	FormatDynamoDB = "dynamodb"
+)
+
+// Replay publishes the JSON objects of a file, mapping their fields to the parts of records. Fields are dotted paths,
+// such as request.id
+type Replay struct {
+	// File is the path of the replayed file
+	File   string `json:"file" koanf:"file"`
+	Format string `json:"format" koanf:"format"`
+	// Speed re-times records from their timestamp field: 1 keeps their original intervals, 2 replays twice as fast.
+	// Zero replays as fast as possible
+	Speed float64 `json:"speed" koanf:"speed"`
+	// KeepTimestamps sets the timestamp of records to their timestamp field, instead of the time they are produced
+	KeepTimestamps bool `json:"keepTimestamps" koanf:"keepTimestamps"`
+	// Value is the field holding the payload, the whole object when empty. A string field, such as the data of
+	// stream-consumer items, is produced as is
+	Value string `json:"value" koanf:"value"`
+	// Key is the field holding the record key, records having no key when empty
+	Key string `json:"key" koanf:"key"`
+	// Topic is the field holding the topic, DefaultTopic being used when empty or missing
+	Topic        string `json:"topic" koanf:"topic"`
+	DefaultTopic string `json:"defaultTopic" koanf:"defaultTopic"`
+	// Timestamp is the field holding the RFC 3339 time, or epoch milliseconds, of the record
+	Timestamp string `json:"timestamp" koanf:"timestamp"`
+	// Headers maps header keys to the fields holding their value
+	Headers map[string]string `json:"headers" koanf:"headers"`
+}
+
+// Validate checks the format and speed of the replay
+func (r Replay) Validate() error {
+	if r.Format != FormatJSON && r.Format != FormatDynamoDB {
+		return fmt.Errorf("unknown replay format %q", r.Format)
+	}
+	if r.Speed < 0 {
+		return fmt.Errorf("replay speed can't be negative, got %v", r.Speed)
+	}
+	if (r.Speed > 0 || r.KeepTimestamps) && r.Timestamp == "" {
+		return fmt.Errorf("replay timestamp field is required to re-time records or keep their timestamp")
+	}
+	if r.Topic == "" && r.DefaultTopic == "" {
+		return fmt.Errorf("replay topic field or default topic is required")
+	}
+	return nil
+}
+
+func (r Replay) MarshalZerologObject(e *zerolog.Event) {
+	e.Str("file", r.File).
+		Str("format", r.Format).
+		Float64("speed", r.Speed).
+		Bool("keepTimestamps", r.KeepTimestamps)
+}
+
+// Schemas locates the JSON schemas payloads are synthesized from
+type Schemas struct {
+	// RepositoryURI is where schema-repository serves schemas, also referenced by the schemaURI header of records
+	RepositoryURI string `json:"repositoryURI" koanf:"repositoryURI"`
+	// Dir holds <name>-<version>.json schemas, such as schemas/schemas, read instead of fetching them
+	Dir string `json:"dir" koanf:"dir"`
+	// Timeout of each fetch from schema-repository
+	Timeout time.Duration `json:"timeout" koanf:"timeout"`
+}
+
+// Partitioners of the records
+const (
+	// PartitionerMurmur2 hashes keys with murmur2 as Kafka does, sticking unkeyed records to a partition per batch
+	PartitionerMurmur2 = "murmur2"
+	// PartitionerSticky sticks every record to a partition per batch, whatever its key
+	PartitionerSticky = "sticky"
+	// PartitionerRoundRobin spreads records evenly over partitions, whatever their key
+	PartitionerRoundRobin = "round-robin"
+)
+
 type Kafka struct {
-	Brokers string `json:"brokers" koanf:"brokers"`
+	Brokers     string `json:"brokers" koanf:"brokers"`
+	Partitioner string `json:"partitioner" koanf:"partitioner"`
+	// Idempotent writes each record once per partition, even when retried
+	Idempotent bool `json:"idempotent" koanf:"idempotent"`
+	// Transaction produces records in transactions, when its ID is set
+	Transaction Transaction `json:"transaction" koanf:"transaction"`
+}
+
+// Transaction commits records by batches, which read_committed consumers only see once committed
+type Transaction struct {
+	// ID is the transactional ID, unique to each producer
+	ID string `json:"id" koanf:"id"`
+	// Records is the count of records of each transaction, the last one being committed on shutdown
+	Records int `json:"records" koanf:"records"`
+}
+
+// Validate checks the partitioner, and that transactions are idempotent
+func (k Kafka) Validate() error {
+	switch k.Partitioner {
+	case PartitionerMurmur2, PartitionerSticky, PartitionerRoundRobin:
+	default:
+		return fmt.Errorf("unknown partitioner %q", k.Partitioner)
+	}
+	if k.Transaction.ID != "" {
+		if !k.Idempotent {
+			return fmt.Errorf("transactions require an idempotent producer")
+		}
+		if k.Transaction.Records <= 0 {
+			return fmt.Errorf("transaction records must be positive, got %d", k.Transaction.Records)
+		}
+	}
+	return nil
 }
 
 func (k Kafka) MarshalZerologObject(e *zerolog.Event) {
-	e.Str("brokers", k.Brokers)
+	e.Str("brokers", k.Brokers).
+		Str("partitioner", k.Partitioner).
+		Bool("idempotent", k.Idempotent).
+		Str("transactionalID", k.Transaction.ID)
+}
+
+// Profile describes the load produced: its rate over time, the schemas of the records and when it ends
+type Profile struct {
+	// Rate is the target of records produced per second
+	Rate float64 `json:"rate" koanf:"rate"`
+	// RampUp increases the rate linearly from zero to Rate, zero starting at Rate
+	RampUp time.Duration `json:"rampUp" koanf:"rampUp"`
+	// Bursts raise the rate periodically, after the ramp-up
+	Bursts []Burst `json:"bursts" koanf:"bursts"`
+	// Duration stops producing after it elapses, zero never stopping
+	Duration time.Duration `json:"duration" koanf:"duration"`
+	// Count stops producing after as many records, zero never stopping
+	Count int `json:"count" koanf:"count"`
+	// Mix holds the schemas of the produced records, each picked in proportion to its weight
+	Mix []Schema `json:"mix" koanf:"mix"`
+	// ReportInterval logs the throughput and latencies of each interval, zero only logging them at the end
+	ReportInterval time.Duration `json:"reportInterval" koanf:"reportInterval"`
+	// Faults injects invalid records
+	Faults Faults `json:"faults" koanf:"faults"`
+}
+
+// Kinds of faults injected in invalid records
+const (
+	// FaultMissingRequired drops a required property
+	FaultMissingRequired = "missing-required"
+	// FaultWrongType gives a property a value of another type
+	FaultWrongType = "wrong-type"
+	// FaultOutOfEnum gives a property a value out of its enum
+	FaultOutOfEnum = "out-of-enum"
+	// FaultMalformedJSON truncates the JSON payload
+	FaultMalformedJSON = "malformed-json"
+	// FaultMissingHeader omits the schemaURI header
+	FaultMissingHeader = "missing-header"
+	// FaultCorruptHeader sends a schemaURI header that isn't a URI
+	FaultCorruptHeader = "corrupt-header"
+	// FaultUnknownVersion points the schemaURI header at a version schema-repository doesn't know
+	FaultUnknownVersion = "unknown-version"
+)
+
+// FaultKinds are the kinds of faults, in the order they are documented
+var FaultKinds = []string{FaultMissingRequired, FaultWrongType, FaultOutOfEnum, FaultMalformedJSON, FaultMissingHeader,
+	FaultCorruptHeader, FaultUnknownVersion}
+
+// Faults injects invalid records, to test the rejection path of consumers. Each one is tagged with a fault header
+// holding its kind
+type Faults struct {
+	// Ratio of the records injected with a fault, from 0 to 1
+	Ratio float64 `json:"ratio" koanf:"ratio"`
+	// Kinds of the injected faults, picked evenly among those applying to the schema of each record. Every kind when
+	// empty
+	Kinds []string `json:"kinds" koanf:"kinds"`
+}
+
+// Burst produces at Rate for Length, once every Every
+type Burst struct {
+	Every  time.Duration `json:"every" koanf:"every"`
+	Length time.Duration `json:"length" koanf:"length"`
+	Rate   float64       `json:"rate" koanf:"rate"`
+}
+
+// Sources of the payloads of a schema
+const (
+	// SourceModel fakes the Go model generated from the schema by the schemas module
+	SourceModel = "model"
+	// SourceSchema synthesizes payloads from the JSON schema itself
+	SourceSchema = "schema"
+)
+
+// Schema is a schema of the mix of a profile, and the topic its records are produced to
+type Schema struct {
+	Name    string `json:"name" koanf:"name"`
+	Version string `json:"version" koanf:"version"`
+	Weight  int    `json:"weight" koanf:"weight"`
+	// Topic defaults to <name>-topic
+	Topic string `json:"topic" koanf:"topic"`
+	// Key is the dotted path of the payload field holding the record key, such as orderId. Records have no key when
+	// empty, or when the field is missing
+	Key string `json:"key" koanf:"key"`
+	// Source of the payloads, SourceModel or SourceSchema. Defaults to SourceModel for schemas with a generated model,
+	// and to SourceSchema for any other
+	Source string `json:"source" koanf:"source"`
+}
+
+// LoadProfile loads a profile from a YAML file
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	k := koanf.New(".")
+	if err = k.Load(rawbytes.Provider(data), yaml.Parser()); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	profile := &Profile{}
+	if err = k.Unmarshal("", profile); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return profile, nil
+}
+
+// Validate checks the rate, bursts and mix of the profile
+func (p Profile) Validate() error {
+	if p.Rate <= 0 {
+		return fmt.Errorf("rate must be positive, got %v", p.Rate)
+	}
+	for i, b := range p.Bursts {
+		if b.Every <= 0 || b.Length <= 0 || b.Length >= b.Every || b.Rate <= 0 {
+			return fmt.Errorf("burst %d must have a positive rate and a length shorter than its period", i)
+		}
+	}
+	if p.Duration < 0 || p.Count < 0 || p.RampUp < 0 || p.ReportInterval < 0 {
+		return fmt.Errorf("durations and count can't be negative")
+	}
+	if p.Faults.Ratio < 0 || p.Faults.Ratio > 1 {
+		return fmt.Errorf("faults ratio must be between 0 and 1, got %v", p.Faults.Ratio)
+	}
+	for _, kind := range p.Faults.Kinds {
+		if !slices.Contains(FaultKinds, kind) {
+			return fmt.Errorf("unknown fault %q", kind)
+		}
+	}
+
+	total := 0
+	for i, s := range p.Mix {
+		if s.Name == "" || s.Version == "" {
+			return fmt.Errorf("schema %d of the mix must have a name and a version", i)
+		}
+		if s.Source != "" && s.Source != SourceModel && s.Source != SourceSchema {
+			return fmt.Errorf("schema %s@%s has an unknown source %q", s.Name, s.Version, s.Source)
+		}
+		if s.Weight < 0 {
+			return fmt.Errorf("schema %s@%s has a negative weight", s.Name, s.Version)
+		}
+		total += s.Weight
+	}
+	if total == 0 {
+		return fmt.Errorf("mix must hold a schema with a positive weight")
+	}
+	return nil
+}
+
+func (p Profile) MarshalZerologObject(e *zerolog.Event) {
+	e.Float64("rate", p.Rate).
+		Dur("rampUp", p.RampUp).
+		Int("bursts", len(p.Bursts)).
+		Dur("duration", p.Duration).
+		Int("count", p.Count).
+		Int("schemas", len(p.Mix)).
+		Float64("faultsRatio", p.Faults.Ratio)
 }
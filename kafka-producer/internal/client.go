@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+)
+
+// ClientOptions returns the options of the Kafka client producing records
+func ClientOptions(cfg config.Kafka) ([]kgo.Opt, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts := []kgo.Opt{kgo.SeedBrokers(strings.Split(cfg.Brokers, ",")...)}
+	switch cfg.Partitioner {
+	case config.PartitionerSticky:
+		opts = append(opts, kgo.RecordPartitioner(kgo.StickyPartitioner()))
+	case config.PartitionerRoundRobin:
+		opts = append(opts, kgo.RecordPartitioner(kgo.RoundRobinPartitioner()))
+	default:
+		opts = append(opts, kgo.RecordPartitioner(kgo.StickyKeyPartitioner(nil)))
+	}
+	if !cfg.Idempotent {
+		opts = append(opts, kgo.DisableIdempotentWrite())
+	}
+	if cfg.Transaction.ID != "" {
+		opts = append(opts, kgo.TransactionalID(cfg.Transaction.ID))
+	}
+	return opts, nil
+}
+
+// Transactor is a Producer producing in transactions, as kgo.Client does
+type Transactor interface {
+	Producer
+	BeginTransaction() error
+	EndTransaction(ctx context.Context, commit kgo.TransactionEndTry) error
+}
+
+// NewProducer returns the client, producing in transactions when configured
+func NewProducer(client Transactor, cfg config.Kafka) Producer {
+	if cfg.Transaction.ID == "" {
+		return client
+	}
+	return &transactional{client: client, size: cfg.Transaction.Records}
+}
+
+// outcome is the result of producing a record in a transaction, reported once the transaction ends
+type outcome struct {
+	record  *kgo.Record
+	err     error
+	promise func(*kgo.Record, error)
+}
+
+// transactional produces records in transactions of size records, committed once full and on Flush. The promises of
+// records are called once their transaction ends, failing every record of aborted transactions
+type transactional struct {
+	client Transactor
+	size   int
+	// records produced in the current transaction, only accessed by the producing goroutine
+	records int
+
+	mu       sync.Mutex
+	outcomes []outcome
+}
+
+func (t *transactional) Produce(ctx context.Context, r *kgo.Record, promise func(*kgo.Record, error)) {
+	if t.records == 0 {
+		if err := t.client.BeginTransaction(); err != nil {
+			promise(r, err)
+			return
+		}
+	}
+
+	t.client.Produce(ctx, r, func(r *kgo.Record, err error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.outcomes = append(t.outcomes, outcome{record: r, err: err, promise: promise})
+	})
+	t.records++
+	if t.records >= t.size {
+		_ = t.commit(ctx)
+	}
+}
+
+// Flush commits the current transaction
+func (t *transactional) Flush(ctx context.Context) error {
+	if t.records == 0 {
+		return t.client.Flush(ctx)
+	}
+	return t.commit(ctx)
+}
+
+// commit waits for the records of the transaction, committing it when all of them were written, aborting it otherwise
+func (t *transactional) commit(ctx context.Context) error {
+	t.records = 0
+	err := t.client.Flush(ctx)
+
+	t.mu.Lock()
+	outcomes := t.outcomes
+	t.outcomes = nil
+	t.mu.Unlock()
+
+	end := kgo.TryCommit
+	for _, o := range outcomes {
+		if o.err != nil && err == nil {
+			err = o.err
+		}
+	}
+	if err != nil {
+		end = kgo.TryAbort
+	}
+	if endErr := t.client.EndTransaction(context.WithoutCancel(ctx), end); endErr != nil && err == nil {
+		err = endErr
+	}
+
+	for _, o := range outcomes {
+		if o.err == nil && err != nil {
+			o.err = err
+		}
+		o.promise(o.record, o.err)
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+)
+
+func TestClientOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		kafka config.Kafka
+		opts  int
+		err   string
+	}{
+		{name: "murmur2", kafka: config.Kafka{Brokers: "a:9092,b:9092", Partitioner: config.PartitionerMurmur2, Idempotent: true}, opts: 2},
+		{name: "not idempotent", kafka: config.Kafka{Brokers: "a:9092", Partitioner: config.PartitionerSticky}, opts: 3},
+		{
+			name:  "transactional",
+			kafka: config.Kafka{Brokers: "a:9092", Partitioner: config.PartitionerRoundRobin, Idempotent: true, Transaction: config.Transaction{ID: "kp", Records: 10}},
+			opts:  3,
+		},
+		{name: "unknown partitioner", kafka: config.Kafka{Brokers: "a:9092", Partitioner: "random"}, err: `unknown partitioner "random"`},
+		{
+			name:  "transactional not idempotent",
+			kafka: config.Kafka{Brokers: "a:9092", Partitioner: config.PartitionerMurmur2, Transaction: config.Transaction{ID: "kp", Records: 10}},
+			err:   "transactions require an idempotent producer",
+		},
+		{
+			name:  "empty transactions",
+			kafka: config.Kafka{Brokers: "a:9092", Partitioner: config.PartitionerMurmur2, Idempotent: true, Transaction: config.Transaction{ID: "kp"}},
+			err:   "transaction records must be positive, got 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := ClientOptions(tt.kafka)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, opts, tt.opts)
+
+			client, err := kgo.NewClient(opts...)
+			require.NoError(t, err)
+			client.Close()
+		})
+	}
+}
+
+// fakeTransactor records the ends of transactions, failing the records whose value is "fail"
+type fakeTransactor struct {
+	fakeProducer
+	begun int
+	ends  []kgo.TransactionEndTry
+}
+
+func (f *fakeTransactor) Produce(ctx context.Context, r *kgo.Record, promise func(*kgo.Record, error)) {
+	f.fakeProducer.Produce(ctx, r, func(r *kgo.Record, err error) {
+		if string(r.Value) == "fail" {
+			err = errors.New("record too large")
+		}
+		promise(r, err)
+	})
+}
+
+func (f *fakeTransactor) BeginTransaction() error {
+	f.begun++
+	return nil
+}
+
+func (f *fakeTransactor) EndTransaction(_ context.Context, commit kgo.TransactionEndTry) error {
+	f.ends = append(f.ends, commit)
+	return nil
+}
+
+func TestNewProducer(t *testing.T) {
+	client := &fakeTransactor{}
+	assert.Same(t, client, NewProducer(client, config.Kafka{}))
+
+	p := NewProducer(client, config.Kafka{Transaction: config.Transaction{ID: "kp", Records: 2}})
+	var errs []error
+	promise := func(_ *kgo.Record, err error) { errs = append(errs, err) }
+	for _, value := range []string{"ok", "ok", "ok", "fail", "ok"} {
+		p.Produce(context.Background(), &kgo.Record{Value: []byte(value)}, promise)
+	}
+	require.NoError(t, p.Flush(context.Background()))
+
+	assert.Equal(t, 3, client.begun)
+	assert.Equal(t, []kgo.TransactionEndTry{kgo.TryCommit, kgo.TryAbort, kgo.TryCommit}, client.ends)
+	require.Len(t, errs, 5)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	// the record written in the aborted transaction fails along the failed one
+	assert.EqualError(t, errs[2], "record too large")
+	assert.EqualError(t, errs[3], "record too large")
+	assert.NoError(t, errs[4])
+}
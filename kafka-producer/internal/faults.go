@@ -0,0 +1,191 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+)
+
+const (
+	schemaURIHeader = "schemaURI"
+	// faultHeader tags injected records with the kind of their fault
+	faultHeader = "fault"
+	// unknownVersion is a version no schema is expected to reach
+	unknownVersion = "999.0.0"
+)
+
+// shape is what faults need to know of a schema: its required, typed and enumerated top-level properties
+type shape struct {
+	required []string
+	types    map[string][]string
+	enums    map[string][]any
+}
+
+func newShape(data []byte) (*shape, error) {
+	var schema struct {
+		Required   []string                   `json:"required"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+
+	s := &shape{required: schema.Required, types: make(map[string][]string), enums: make(map[string][]any)}
+	for name, raw := range schema.Properties {
+		var property struct {
+			Type any   `json:"type"`
+			Enum []any `json:"enum"`
+		}
+		if json.Unmarshal(raw, &property) != nil {
+			continue
+		}
+		switch t := property.Type.(type) {
+		case string:
+			s.types[name] = []string{t}
+		case []any:
+			for _, v := range t {
+				s.types[name] = append(s.types[name], fmt.Sprint(v))
+			}
+		}
+		if len(property.Enum) > 0 {
+			s.enums[name] = property.Enum
+		}
+	}
+	return s, nil
+}
+
+// faulter injects faults in the records of a schema
+type faulter struct {
+	// kinds are the configured faults applying to the schema
+	kinds      []string
+	shape      *shape
+	unknownURI string
+}
+
+// newFaulter keeps the configured kinds applying to a schema: faults on properties need a schema declaring them
+func newFaulter(cfg config.Faults, s *shape, unknownURI string) *faulter {
+	kinds := cfg.Kinds
+	if len(kinds) == 0 {
+		kinds = config.FaultKinds
+	}
+
+	f := &faulter{shape: s, unknownURI: unknownURI}
+	for _, kind := range kinds {
+		switch {
+		case kind == config.FaultMissingRequired && len(s.required) == 0,
+			kind == config.FaultWrongType && len(s.types) == 0,
+			kind == config.FaultOutOfEnum && len(s.enums) == 0:
+			continue
+		}
+		f.kinds = append(f.kinds, kind)
+	}
+	return f
+}
+
+// inject turns a valid record into an invalid one, with a fault picked among the kinds, tagging it with the fault
+// header. It returns the kind of the fault, empty when none applies
+func (f *faulter) inject(r *kgo.Record) (string, error) {
+	if len(f.kinds) == 0 {
+		return "", nil
+	}
+
+	kind := f.kinds[rand.IntN(len(f.kinds))]
+	var err error
+	switch kind {
+	case config.FaultMissingRequired:
+		err = f.editPayload(r, func(payload map[string]any) {
+			delete(payload, pickOne(f.shape.required))
+		})
+	case config.FaultWrongType:
+		err = f.editPayload(r, func(payload map[string]any) {
+			name := pickOne(slices.Sorted(maps.Keys(f.shape.types)))
+			payload[name] = wrongValue(f.shape.types[name])
+		})
+	case config.FaultOutOfEnum:
+		err = f.editPayload(r, func(payload map[string]any) {
+			name := pickOne(slices.Sorted(maps.Keys(f.shape.enums)))
+			payload[name] = outOfEnum(f.shape.enums[name])
+		})
+	case config.FaultMalformedJSON:
+		r.Value = r.Value[:len(r.Value)/2]
+	case config.FaultMissingHeader:
+		r.Headers = slices.DeleteFunc(r.Headers, func(h kgo.RecordHeader) bool { return h.Key == schemaURIHeader })
+	case config.FaultCorruptHeader:
+		setHeader(r, schemaURIHeader, "\x00:not a uri%")
+	case config.FaultUnknownVersion:
+		setHeader(r, schemaURIHeader, f.unknownURI)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	setHeader(r, faultHeader, kind)
+	return kind, nil
+}
+
+// editPayload edits the JSON object of a record
+func (f *faulter) editPayload(r *kgo.Record, edit func(map[string]any)) error {
+	d := json.NewDecoder(bytes.NewReader(r.Value))
+	d.UseNumber()
+	var payload map[string]any
+	if err := d.Decode(&payload); err != nil {
+		return fmt.Errorf("payload is not a JSON object: %w", err)
+	}
+	edit(payload)
+
+	value, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	r.Value = value
+	return nil
+}
+
+// wrongValue returns a value of a type none of types accepts
+func wrongValue(types []string) any {
+	candidates := []struct {
+		types []string
+		value any
+	}{
+		{types: []string{"string"}, value: "wrong-type"},
+		{types: []string{"integer", "number"}, value: 12345},
+		{types: []string{"boolean"}, value: true},
+		{types: []string{"object"}, value: map[string]any{}},
+	}
+	for _, c := range candidates {
+		if !slices.ContainsFunc(c.types, func(t string) bool { return slices.Contains(types, t) }) {
+			return c.value
+		}
+	}
+	return []any{}
+}
+
+// outOfEnum returns a value none of the enum values equals
+func outOfEnum(enum []any) any {
+	value := "not-in-enum"
+	for slices.Contains(enum, any(value)) {
+		value += "-" + fmt.Sprint(rand.IntN(1000))
+	}
+	return value
+}
+
+func setHeader(r *kgo.Record, key, value string) {
+	for i := range r.Headers {
+		if r.Headers[i].Key == key {
+			r.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	r.Headers = append(r.Headers, kgo.RecordHeader{Key: key, Value: []byte(value)})
+}
+
+func pickOne(values []string) string {
+	return values[rand.IntN(len(values))]
+}
@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+	schemavalidator "github.com/mfelipe/go-feijoada/schema-validator"
+	svcfg "github.com/mfelipe/go-feijoada/schema-validator/config"
+)
+
+func header(r *kgo.Record, key string) (string, bool) {
+	for _, h := range r.Headers {
+		if h.Key == key {
+			return string(h.Value), true
+		}
+	}
+	return "", false
+}
+
+func TestFaulter_Inject(t *testing.T) {
+	const uri = "http://schema-repository:8080/schemas/order/2.0.0"
+	data, err := os.ReadFile("../../schemas/schemas/order-2.0.0.json")
+	require.NoError(t, err)
+	s, err := newShape(data)
+	require.NoError(t, err)
+
+	v := schemavalidator.New(svcfg.Config{DefaultBaseURI: "http://schema-repository:8080", Offline: true})
+	require.NoError(t, v.AddSchema(uri, data))
+
+	for _, kind := range config.FaultKinds {
+		t.Run(kind, func(t *testing.T) {
+			f := newFaulter(config.Faults{Kinds: []string{kind}}, s, "http://schema-repository:8080/schemas/order/999.0.0")
+			record := &kgo.Record{
+				Value:   []byte(`{"orderId": 1, "userId": 2, "productIds": [3], "total": 4.5, "status": "pending"}`),
+				Headers: []kgo.RecordHeader{{Key: schemaURIHeader, Value: []byte(uri)}},
+			}
+
+			injected, err := f.inject(record)
+			require.NoError(t, err)
+			assert.Equal(t, kind, injected)
+			value, _ := header(record, faultHeader)
+			assert.Equal(t, kind, value)
+
+			schemaURI, hasSchemaURI := header(record, schemaURIHeader)
+			switch kind {
+			case config.FaultMalformedJSON:
+				assert.False(t, json.Valid(record.Value))
+			case config.FaultMissingHeader:
+				assert.False(t, hasSchemaURI)
+			case config.FaultCorruptHeader:
+				assert.NotEqual(t, uri, schemaURI)
+			case config.FaultUnknownVersion:
+				_, err = v.Validate(schemaURI, record.Value)
+				assert.ErrorIs(t, err, schemavalidator.ErrSchemaNotFound)
+			default:
+				report, err := v.Validate(uri, record.Value)
+				require.NoError(t, err)
+				assert.False(t, report.Valid, "%s", record.Value)
+			}
+		})
+	}
+}
+
+func TestNewFaulter(t *testing.T) {
+	s, err := newShape([]byte(`{"properties": {"name": {}}}`))
+	require.NoError(t, err)
+
+	f := newFaulter(config.Faults{}, s, "")
+	assert.Equal(t, []string{config.FaultMalformedJSON, config.FaultMissingHeader, config.FaultCorruptHeader,
+		config.FaultUnknownVersion}, f.kinds)
+
+	f = newFaulter(config.Faults{Kinds: []string{config.FaultOutOfEnum}}, s, "")
+	kind, err := f.inject(&kgo.Record{Value: []byte(`{}`)})
+	require.NoError(t, err)
+	assert.Empty(t, kind)
+}
+
+func TestWrongValue(t *testing.T) {
+	assert.Equal(t, 12345, wrongValue([]string{"string"}))
+	assert.Equal(t, "wrong-type", wrongValue([]string{"integer"}))
+	assert.Equal(t, map[string]any{}, wrongValue([]string{"string", "number", "boolean"}))
+}
+
+func TestLoad_Faults(t *testing.T) {
+	p := &fakeProducer{}
+	load, err := NewLoad(p, newConfig(config.Profile{
+		Rate:   1000,
+		Count:  100,
+		Mix:    []config.Schema{{Name: "payment", Version: "2.0.0", Weight: 1}},
+		Faults: config.Faults{Ratio: 1, Kinds: []string{config.FaultMissingRequired, config.FaultMissingHeader}},
+	}))
+	require.NoError(t, err)
+
+	summary := load.Run(context.Background())
+	assert.Equal(t, uint64(100), summary.Faults[config.FaultMissingRequired]+summary.Faults[config.FaultMissingHeader])
+	for _, r := range p.records {
+		kind, ok := header(r, faultHeader)
+		require.True(t, ok)
+		assert.Contains(t, []string{config.FaultMissingRequired, config.FaultMissingHeader}, kind)
+	}
+}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+)
+
+const (
+	// tick is how often records due by the rate are produced, so rates over 1/tick are reached without a timer per record
+	tick = 10 * time.Millisecond
+	// maxBacklog bounds the records due while the producer blocks, so a slow cluster isn't flooded once it recovers
+	maxBacklog = time.Second
+)
+
+// Load produces records following a profile
+type Load struct {
+	sender
+	profile config.Profile
+	loader  *schemaLoader
+	mix     *mix
+}
+
+// NewLoad validates the profile of the configuration, loading the schemas payloads are synthesized from
+func NewLoad(producer Producer, cfg *config.Consumer) (*Load, error) {
+	if err := cfg.Profile.Validate(); err != nil {
+		return nil, err
+	}
+	loader := newSchemaLoader(cfg.Schemas)
+	m, err := newMix(cfg.Profile.Mix, cfg.Profile.Faults, loader)
+	if err != nil {
+		return nil, err
+	}
+	return &Load{
+		sender:  sender{producer: producer, closeTimeout: cfg.CloseTimeout, reportInterval: cfg.Profile.ReportInterval},
+		profile: cfg.Profile,
+		loader:  loader,
+		mix:     m,
+	}, nil
+}
+
+// Run produces records until the duration or count of the profile is reached, or ctx is done. It then waits up to the
+// close timeout for the records to be acknowledged, and returns the summary of the whole load
+func (l *Load) Run(ctx context.Context) Summary {
+	start := l.start()
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	var (
+		sent int
+		due  float64
+		last = start
+	)
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case now := <-ticker.C:
+			elapsed := now.Sub(start)
+			if l.profile.Duration > 0 && elapsed >= l.profile.Duration {
+				break loop
+			}
+
+			rate := rateAt(l.profile, elapsed)
+			due = min(due+rate*now.Sub(last).Seconds(), rate*maxBacklog.Seconds()+1)
+			last = now
+			for ; due >= 1; due-- {
+				if l.profile.Count > 0 && sent >= l.profile.Count {
+					break loop
+				}
+				l.produce(ctx)
+				sent++
+			}
+
+			if report, ok := l.reportDue(now); ok {
+				zlog.Info().Float64("rate", rate).EmbedObject(report).Msg("Load report")
+			}
+		}
+	}
+
+	return l.close(ctx)
+}
+
+func (l *Load) produce(ctx context.Context) {
+	e := l.mix.pick()
+	record, err := l.newRecord(e)
+	if err != nil {
+		zlog.Error().Err(err).Str("schema", e.Name+"@"+e.Version).Msg("failed to create new record")
+		return
+	}
+
+	var fault string
+	if e.faulter != nil && rand.Float64() < l.profile.Faults.Ratio {
+		if fault, err = e.faulter.inject(record); err != nil {
+			zlog.Error().Err(err).Str("schema", e.Name+"@"+e.Version).Msg("failed to inject fault")
+			return
+		}
+	}
+
+	l.send(ctx, record, fault)
+}
+
+func (l *Load) newRecord(e *entry) (*kgo.Record, error) {
+	model, err := e.generate()
+	if err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &kgo.Record{
+		Value: value,
+		Headers: []kgo.RecordHeader{
+			{Key: schemaURIHeader, Value: []byte(l.loader.uri(e.Name, e.Version))},
+		},
+		Topic: e.Topic,
+	}
+	if e.Key != "" {
+		if record.Key, err = payloadKey(value, e.Key); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+// payloadKey returns the field at path of a JSON payload, nil when missing
+func payloadKey(value []byte, path string) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(value))
+	d.UseNumber()
+	var payload map[string]any
+	if err := d.Decode(&payload); err != nil {
+		return nil, err
+	}
+	v, ok := field(payload, path)
+	if !ok || v == nil {
+		return nil, nil
+	}
+	return fieldBytes(v)
+}
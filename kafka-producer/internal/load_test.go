@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+)
+
+// fakeProducer acknowledges records from another goroutine, as kgo.Client does
+type fakeProducer struct {
+	mu      sync.Mutex
+	records []*kgo.Record
+	wg      sync.WaitGroup
+}
+
+func (p *fakeProducer) Produce(_ context.Context, r *kgo.Record, promise func(*kgo.Record, error)) {
+	p.mu.Lock()
+	p.records = append(p.records, r)
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		time.Sleep(time.Millisecond)
+		promise(r, nil)
+	}()
+}
+
+func (p *fakeProducer) Flush(context.Context) error {
+	p.wg.Wait()
+	return nil
+}
+
+func newConfig(profile config.Profile) *config.Consumer {
+	return &config.Consumer{
+		CloseTimeout: time.Second,
+		Profile:      profile,
+		Schemas:      config.Schemas{RepositoryURI: "http://127.0.0.1:1", Dir: "../../schemas/schemas", Timeout: time.Second},
+	}
+}
+
+func TestLoad_Run(t *testing.T) {
+	t.Run("count", func(t *testing.T) {
+		p := &fakeProducer{}
+		load, err := NewLoad(p, newConfig(config.Profile{
+			Rate:  1000,
+			Count: 50,
+			Mix: []config.Schema{
+				{Name: "order", Version: "2.0.0", Weight: 1},
+				{Name: "payment", Version: "2.0.0", Weight: 1, Topic: "payments"},
+			},
+		}))
+		require.NoError(t, err)
+
+		summary := load.Run(context.Background())
+		assert.Equal(t, uint64(50), summary.Sent)
+		assert.Equal(t, uint64(50), summary.Acked)
+		assert.Zero(t, summary.Failed)
+		assert.GreaterOrEqual(t, summary.P50, time.Millisecond)
+		assert.Positive(t, summary.Throughput)
+
+		require.Len(t, p.records, 50)
+		for _, r := range p.records {
+			assert.Contains(t, []string{"order-topic", "payments"}, r.Topic)
+			var value map[string]any
+			require.NoError(t, json.Unmarshal(r.Value, &value))
+			assert.Contains(t, []any{"pending", "shipped", "delivered", "cancelled", "completed", "failed"}, value["status"])
+		}
+	})
+
+	t.Run("duration", func(t *testing.T) {
+		p := &fakeProducer{}
+		load, err := NewLoad(p, newConfig(config.Profile{
+			Rate:     200,
+			Duration: 200 * time.Millisecond,
+			Mix:      []config.Schema{{Name: "user", Version: "1.0.0", Weight: 1}},
+		}))
+		require.NoError(t, err)
+
+		summary := load.Run(context.Background())
+		assert.InDelta(t, 40, summary.Sent, 15)
+		assert.Equal(t, summary.Sent, summary.Acked)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		load, err := NewLoad(&fakeProducer{}, newConfig(config.Profile{
+			Rate: 10,
+			Mix:  []config.Schema{{Name: "user", Version: "1.0.0", Weight: 1}},
+		}))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		summary := load.Run(ctx)
+		assert.LessOrEqual(t, summary.Sent, uint64(2))
+	})
+
+	t.Run("keys", func(t *testing.T) {
+		p := &fakeProducer{}
+		load, err := NewLoad(p, newConfig(config.Profile{
+			Rate:  1000,
+			Count: 20,
+			Mix: []config.Schema{
+				{Name: "payment", Version: "2.0.0", Weight: 1, Key: "orderId"},
+				{Name: "user", Version: "1.0.0", Weight: 1, Key: "missing.field"},
+			},
+		}))
+		require.NoError(t, err)
+
+		load.Run(context.Background())
+		require.Len(t, p.records, 20)
+		for _, r := range p.records {
+			d := json.NewDecoder(bytes.NewReader(r.Value))
+			d.UseNumber()
+			var value map[string]any
+			require.NoError(t, d.Decode(&value))
+			if r.Topic == "user-topic" {
+				assert.Nil(t, r.Key)
+				continue
+			}
+			assert.Equal(t, value["orderId"].(json.Number).String(), string(r.Key))
+		}
+	})
+
+	t.Run("unknown schema", func(t *testing.T) {
+		_, err := NewLoad(&fakeProducer{}, newConfig(config.Profile{
+			Rate: 10,
+			Mix:  []config.Schema{{Name: "invoice", Version: "1.0.0", Weight: 1}},
+		}))
+		assert.ErrorContains(t, err, "loading schema invoice@1.0.0")
+	})
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/brianvoe/gofakeit/v6"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+	"github.com/mfelipe/go-feijoada/kafka-producer/internal/synth"
+	"github.com/mfelipe/go-feijoada/schemas"
+	"github.com/mfelipe/go-feijoada/schemas/models/v2_0_0"
+)
+
+// enums fake the models whose enums gofakeit would fill with random words, by schema ID
+var enums = map[string]func() (any, error){
+	v2_0_0.OrderSchemaID: func() (any, error) {
+		model, err := fake[v2_0_0.Order]()
+		if err == nil {
+			model.Status = oneOf(v2_0_0.OrderStatusPending, v2_0_0.OrderStatusShipped, v2_0_0.OrderStatusDelivered,
+				v2_0_0.OrderStatusCancelled)
+		}
+		return model, err
+	},
+	v2_0_0.PaymentSchemaID: func() (any, error) {
+		model, err := fake[v2_0_0.Payment]()
+		if err == nil {
+			model.Status = oneOf(v2_0_0.PaymentStatusPending, v2_0_0.PaymentStatusCompleted, v2_0_0.PaymentStatusFailed)
+		}
+		return model, err
+	},
+}
+
+// generators fake a model of each schema of the schemas registry, by <name>@<version>
+var generators = func() map[string]func() (any, error) {
+	g := make(map[string]func() (any, error), len(schemas.Types))
+	for id, t := range schemas.Types {
+		m := schemaPath.FindStringSubmatch(id)
+		if m == nil {
+			continue
+		}
+		generate, ok := enums[id]
+		if !ok {
+			generate = faker(t)
+		}
+		g[m[1]+"@"+m[2]] = generate
+	}
+	return g
+}()
+
+// generator returns the generator of the payloads of a schema: its model, or a synthesizer of the schema loaded by
+// loader
+func generator(s config.Schema, loader *schemaLoader) (func() (any, error), error) {
+	g, ok := generators[s.Name+"@"+s.Version]
+	switch {
+	case s.Source == config.SourceModel && !ok:
+		return nil, fmt.Errorf("no model known for schema %s@%s", s.Name, s.Version)
+	case s.Source == config.SourceModel || s.Source == "" && ok:
+		return g, nil
+	}
+
+	synthesizer, err := synth.New(loader.uri(s.Name, s.Version), loader.load)
+	if err != nil {
+		return nil, fmt.Errorf("loading schema %s@%s: %w", s.Name, s.Version, err)
+	}
+	return synthesizer.Generate, nil
+}
+
+// faker fakes models of type t
+func faker(t reflect.Type) func() (any, error) {
+	return func() (any, error) {
+		model := reflect.New(t)
+		err := gofakeit.Struct(model.Interface())
+		return model.Elem().Interface(), err
+	}
+}
+
+func fake[T any]() (T, error) {
+	var model T
+	err := gofakeit.Struct(&model)
+	return model, err
+}
+
+// oneOf picks one of the values of an enum, which gofakeit would fill with random words
+func oneOf[T ~string](values ...T) *T {
+	v := values[gofakeit.IntRange(0, len(values)-1)]
+	return &v
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+)
+
+// rateAt is the target rate of a profile, in records per second, once elapsed has passed since the start
+func rateAt(p config.Profile, elapsed time.Duration) float64 {
+	if elapsed < p.RampUp {
+		return p.Rate * float64(elapsed) / float64(p.RampUp)
+	}
+
+	rate := p.Rate
+	since := elapsed - p.RampUp
+	for _, b := range p.Bursts {
+		// Each burst ends its period, so the first one starts once the rate has been held for Every - Length
+		if since%b.Every >= b.Every-b.Length && b.Rate > rate {
+			rate = b.Rate
+		}
+	}
+	return rate
+}
+
+// entry is a schema of the mix, with the generator of its models
+type entry struct {
+	config.Schema
+	generate func() (any, error)
+	// faulter is nil without faults
+	faulter *faulter
+	// upTo is the cumulative weight of the mix up to this entry
+	upTo int
+}
+
+// mix picks the schemas of a profile in proportion to their weight
+type mix struct {
+	entries []entry
+	total   int
+}
+
+// newMix builds the generator of each schema of the mix, loading the schemas payloads are synthesized from, and the
+// schemas faults are injected in
+func newMix(schemas []config.Schema, faults config.Faults, loader *schemaLoader) (*mix, error) {
+	m := &mix{}
+	for _, s := range schemas {
+		if s.Weight == 0 {
+			continue
+		}
+		g, err := generator(s, loader)
+		if err != nil {
+			return nil, err
+		}
+		if s.Topic == "" {
+			s.Topic = s.Name + "-topic"
+		}
+		var f *faulter
+		if faults.Ratio > 0 {
+			data, err := loader.load(loader.uri(s.Name, s.Version))
+			if err != nil {
+				return nil, fmt.Errorf("loading schema %s@%s: %w", s.Name, s.Version, err)
+			}
+			sh, err := newShape(data)
+			if err != nil {
+				return nil, fmt.Errorf("schema %s@%s: %w", s.Name, s.Version, err)
+			}
+			f = newFaulter(faults, sh, loader.uri(s.Name, unknownVersion))
+		}
+		m.total += s.Weight
+		m.entries = append(m.entries, entry{Schema: s, generate: g, faulter: f, upTo: m.total})
+	}
+	return m, nil
+}
+
+func (m *mix) pick() *entry {
+	n := rand.IntN(m.total)
+	for i := range m.entries {
+		if n < m.entries[i].upTo {
+			return &m.entries[i]
+		}
+	}
+	return &m.entries[len(m.entries)-1]
+}
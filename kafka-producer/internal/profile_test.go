@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+)
+
+func TestRateAt(t *testing.T) {
+	p := config.Profile{
+		Rate:   100,
+		RampUp: 10 * time.Second,
+		Bursts: []config.Burst{{Every: time.Minute, Length: 5 * time.Second, Rate: 1000}},
+	}
+
+	tests := []struct {
+		elapsed time.Duration
+		rate    float64
+	}{
+		{elapsed: 0, rate: 0},
+		{elapsed: 5 * time.Second, rate: 50},
+		{elapsed: 10 * time.Second, rate: 100},
+		{elapsed: 64 * time.Second, rate: 100},
+		{elapsed: 65 * time.Second, rate: 1000},
+		{elapsed: 69 * time.Second, rate: 1000},
+		{elapsed: 70 * time.Second, rate: 100},
+		{elapsed: 125 * time.Second, rate: 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.elapsed.String(), func(t *testing.T) {
+			assert.InDelta(t, tt.rate, rateAt(p, tt.elapsed), 0.001)
+		})
+	}
+}
+
+func TestMix(t *testing.T) {
+	loader := newSchemaLoader(newConfig(config.Profile{}).Schemas)
+	m, err := newMix([]config.Schema{
+		{Name: "order", Version: "2.0.0", Weight: 3},
+		{Name: "user", Version: "1.0.0", Weight: 1, Topic: "people"},
+		{Name: "address", Version: "1.0.0", Weight: 0},
+	}, config.Faults{}, loader)
+	require.NoError(t, err)
+
+	picked := map[string]int{}
+	for range 10000 {
+		e := m.pick()
+		picked[e.Topic]++
+	}
+	assert.Len(t, picked, 2)
+	assert.InDelta(t, 7500, picked["order-topic"], 300)
+	assert.InDelta(t, 2500, picked["people"], 300)
+
+	_, err = newMix([]config.Schema{{Name: "order", Version: "9.0.0", Weight: 1, Source: config.SourceModel}}, config.Faults{}, loader)
+	assert.EqualError(t, err, "no model known for schema order@9.0.0")
+}
+
+func TestProfile_Validate(t *testing.T) {
+	mix := []config.Schema{{Name: "order", Version: "2.0.0", Weight: 1}}
+
+	tests := []struct {
+		name    string
+		profile config.Profile
+		err     string
+	}{
+		{name: "valid", profile: config.Profile{Rate: 1, Mix: mix}},
+		{name: "no rate", profile: config.Profile{Mix: mix}, err: "rate must be positive, got 0"},
+		{
+			name:    "burst longer than its period",
+			profile: config.Profile{Rate: 1, Mix: mix, Bursts: []config.Burst{{Every: time.Second, Length: time.Minute, Rate: 2}}},
+			err:     "burst 0 must have a positive rate and a length shorter than its period",
+		},
+		{
+			name:    "faults ratio over 1",
+			profile: config.Profile{Rate: 1, Mix: mix, Faults: config.Faults{Ratio: 1.5}},
+			err:     "faults ratio must be between 0 and 1, got 1.5",
+		},
+		{
+			name:    "unknown fault",
+			profile: config.Profile{Rate: 1, Mix: mix, Faults: config.Faults{Ratio: 0.1, Kinds: []string{"slow"}}},
+			err:     `unknown fault "slow"`,
+		},
+		{
+			name:    "no weight",
+			profile: config.Profile{Rate: 1, Mix: []config.Schema{{Name: "order", Version: "2.0.0"}}},
+			err:     "mix must hold a schema with a positive weight",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profile.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	p, err := config.LoadProfile("../profiles/benchmark.yaml")
+	require.NoError(t, err)
+	require.NoError(t, p.Validate())
+	assert.Equal(t, 500.0, p.Rate)
+	assert.Equal(t, 30*time.Second, p.RampUp)
+	assert.Equal(t, []config.Burst{{Every: time.Minute, Length: 5 * time.Second, Rate: 2000}}, p.Bursts)
+	assert.Equal(t, config.Schema{Name: "product", Version: "2.0.0", Weight: 1, Topic: "catalog-topic"}, p.Mix[2])
+
+	_, err = newMix(p.Mix, p.Faults, newSchemaLoader(newConfig(config.Profile{}).Schemas))
+	assert.NoError(t, err)
+}
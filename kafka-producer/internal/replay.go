@@ -0,0 +1,322 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+)
+
+// Replay publishes a recorded dataset, re-timed from the timestamps of its records
+type Replay struct {
+	sender
+	cfg config.Replay
+}
+
+// NewReplay validates the replay of the configuration, failing when its file can't be read
+func NewReplay(producer Producer, cfg *config.Consumer) (*Replay, error) {
+	if err := cfg.Replay.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(cfg.Replay.File); err != nil {
+		return nil, err
+	}
+	return &Replay{
+		sender: sender{producer: producer, closeTimeout: cfg.CloseTimeout, reportInterval: cfg.Profile.ReportInterval},
+		cfg:    cfg.Replay,
+	}, nil
+}
+
+// Run produces a record of each object of the file, until its end or until ctx is done. It then waits up to the close
+// timeout for the records to be acknowledged, and returns the summary of the whole replay. Objects failing to map to a
+// record are skipped, while a file that isn't JSON stops the replay
+func (r *Replay) Run(ctx context.Context) (Summary, error) {
+	f, err := os.Open(r.cfg.File)
+	if err != nil {
+		return Summary{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	start := r.start()
+	var (
+		first  time.Time
+		i      int
+		runErr error
+	)
+	for object, err := range r.objects(f) {
+		if err != nil {
+			runErr = fmt.Errorf("%s: %w", r.cfg.File, err)
+			break
+		}
+		i++
+
+		record, err := r.newRecord(object)
+		if err != nil {
+			zlog.Error().Err(err).Int("object", i).Msg("failed to map replayed object")
+			continue
+		}
+
+		// Records are due at the interval of their timestamp to the first one, divided by the speed
+		if r.cfg.Speed > 0 && !record.Timestamp.IsZero() {
+			if first.IsZero() {
+				first = record.Timestamp
+			}
+			if !r.wait(ctx, start.Add(time.Duration(float64(record.Timestamp.Sub(first))/r.cfg.Speed))) {
+				break
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if !r.cfg.KeepTimestamps {
+			record.Timestamp = time.Time{}
+		}
+		r.send(ctx, record, "")
+		r.logReport(time.Now())
+	}
+	return r.close(ctx), runErr
+}
+
+// objects iterates over the objects of a file, ending with the error of a value that isn't a JSON object. DynamoDB JSON
+// items are converted to plain objects
+func (r *Replay) objects(f io.Reader) iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		d := json.NewDecoder(f)
+		d.UseNumber()
+		for {
+			var v any
+			err := d.Decode(&v)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			object, ok := v.(map[string]any)
+			if err == nil && !ok {
+				err = fmt.Errorf("offset %d: %T is not a JSON object", d.InputOffset(), v)
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			items := []map[string]any{object}
+			if r.cfg.Format == config.FormatDynamoDB {
+				items = dynamoDBItems(object)
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// wait sleeps until a record is due, reporting meanwhile, and returns false once ctx is done
+func (r *Replay) wait(ctx context.Context, due time.Time) bool {
+	for {
+		d := time.Until(due)
+		if d <= 0 {
+			return true
+		}
+		if r.reportInterval > 0 {
+			d = min(d, time.Until(r.nextReport))
+		}
+
+		timer := time.NewTimer(max(d, 0))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case now := <-timer.C:
+			r.logReport(now)
+		}
+	}
+}
+
+func (r *Replay) logReport(now time.Time) {
+	if report, ok := r.reportDue(now); ok {
+		zlog.Info().EmbedObject(report).Msg("Replay report")
+	}
+}
+
+// newRecord maps the fields of an object to a record
+func (r *Replay) newRecord(object map[string]any) (*kgo.Record, error) {
+	record := &kgo.Record{Topic: r.cfg.DefaultTopic}
+
+	value := any(object)
+	if r.cfg.Value != "" {
+		var ok bool
+		if value, ok = field(object, r.cfg.Value); !ok {
+			return nil, fmt.Errorf("missing value field %s", r.cfg.Value)
+		}
+	}
+	var err error
+	if record.Value, err = fieldBytes(value); err != nil {
+		return nil, err
+	}
+
+	if r.cfg.Key != "" {
+		if key, ok := field(object, r.cfg.Key); ok && key != nil {
+			if record.Key, err = fieldBytes(key); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if r.cfg.Topic != "" {
+		if topic, ok := field(object, r.cfg.Topic); ok && topic != nil && fmt.Sprint(topic) != "" {
+			record.Topic = fmt.Sprint(topic)
+		}
+	}
+	if record.Topic == "" {
+		return nil, fmt.Errorf("missing topic field %s", r.cfg.Topic)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(r.cfg.Headers)) {
+		v, ok := field(object, r.cfg.Headers[key])
+		if !ok || v == nil {
+			continue
+		}
+		b, err := fieldBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		record.Headers = append(record.Headers, kgo.RecordHeader{Key: key, Value: b})
+	}
+
+	if r.cfg.Timestamp != "" {
+		if v, ok := field(object, r.cfg.Timestamp); ok {
+			if record.Timestamp, err = parseTimestamp(v); err != nil {
+				return nil, fmt.Errorf("timestamp field %s: %w", r.cfg.Timestamp, err)
+			}
+		}
+	}
+	return record, nil
+}
+
+// field returns the value at a dotted path of an object
+func field(object map[string]any, path string) (any, bool) {
+	var current any = object
+	for _, name := range strings.Split(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[name]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// fieldBytes returns strings as is, and the JSON of any other value
+func fieldBytes(v any) ([]byte, error) {
+	if s, ok := v.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(v)
+}
+
+// parseTimestamp parses RFC 3339 times and epoch milliseconds
+func parseTimestamp(v any) (time.Time, error) {
+	switch t := v.(type) {
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, t); err == nil {
+			return ts, nil
+		}
+		ms, err := json.Number(t).Int64()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 time nor epoch milliseconds", t)
+		}
+		return time.UnixMilli(ms), nil
+	case json.Number:
+		ms, err := t.Int64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.UnixMilli(ms), nil
+	default:
+		return time.Time{}, fmt.Errorf("unexpected %T", v)
+	}
+}
+
+// dynamoDBItems converts the DynamoDB JSON items of an object to plain objects: the items of a scan output, the item of
+// a table export line, or the object itself
+func dynamoDBItems(object map[string]any) []map[string]any {
+	if items, ok := object["Items"].([]any); ok {
+		var converted []map[string]any
+		for _, item := range items {
+			if m, ok := item.(map[string]any); ok {
+				converted = append(converted, fromDynamoDB(m))
+			}
+		}
+		return converted
+	}
+	if item, ok := object["Item"].(map[string]any); ok {
+		return []map[string]any{fromDynamoDB(item)}
+	}
+	return []map[string]any{fromDynamoDB(object)}
+}
+
+func fromDynamoDB(item map[string]any) map[string]any {
+	object := make(map[string]any, len(item))
+	for k, v := range item {
+		object[k] = attributeValue(v)
+	}
+	return object
+}
+
+// attributeValue converts a DynamoDB JSON attribute value, such as {"S": "text"} or {"N": "1"}. Binary values are kept
+// base64 encoded
+func attributeValue(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) != 1 {
+		return v
+	}
+	for typ, value := range m {
+		switch typ {
+		case "S", "B":
+			return value
+		case "N":
+			return json.Number(fmt.Sprint(value))
+		case "BOOL":
+			return value
+		case "NULL":
+			return nil
+		case "M":
+			if item, ok := value.(map[string]any); ok {
+				return fromDynamoDB(item)
+			}
+		case "L":
+			if list, ok := value.([]any); ok {
+				converted := make([]any, len(list))
+				for i, e := range list {
+					converted[i] = attributeValue(e)
+				}
+				return converted
+			}
+		case "SS", "BS":
+			return value
+		case "NS":
+			if list, ok := value.([]any); ok {
+				converted := make([]any, len(list))
+				for i, e := range list {
+					converted[i] = json.Number(fmt.Sprint(e))
+				}
+				return converted
+			}
+		}
+	}
+	return v
+}
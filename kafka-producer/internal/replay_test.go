@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+)
+
+func newReplay(t *testing.T, p Producer, content string, cfg config.Replay) *Replay {
+	cfg.File = filepath.Join(t.TempDir(), "replay.ndjson")
+	require.NoError(t, os.WriteFile(cfg.File, []byte(content), 0o600))
+	if cfg.Format == "" {
+		cfg.Format = config.FormatJSON
+	}
+
+	r, err := NewReplay(p, &config.Consumer{CloseTimeout: time.Second, Replay: cfg})
+	require.NoError(t, err)
+	return r
+}
+
+func TestReplay_Run(t *testing.T) {
+	t.Run("ndjson", func(t *testing.T) {
+		const content = `{"request_id": "user-001", "title": "First", "body": "one"}
+{"request_id": "user-002", "title": "Second", "meta": {"topic": "other"}}
+
+{"request_id": 3, "title": "Third"}
+`
+		p := &fakeProducer{}
+		r := newReplay(t, p, content, config.Replay{
+			Key:          "request_id",
+			Topic:        "meta.topic",
+			DefaultTopic: "requests",
+			Headers:      map[string]string{"title": "title", "body": "body"},
+		})
+
+		summary, err := r.Run(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), summary.Acked)
+
+		require.Len(t, p.records, 3)
+		assert.JSONEq(t, `{"request_id": "user-001", "title": "First", "body": "one"}`, string(p.records[0].Value))
+		assert.Equal(t, "user-001", string(p.records[0].Key))
+		assert.Equal(t, "requests", p.records[0].Topic)
+		assert.Equal(t, []kgo.RecordHeader{{Key: "body", Value: []byte("one")}, {Key: "title", Value: []byte("First")}},
+			p.records[0].Headers)
+		assert.Equal(t, "other", p.records[1].Topic)
+		assert.Equal(t, "3", string(p.records[2].Key))
+		assert.True(t, p.records[2].Timestamp.IsZero())
+	})
+
+	t.Run("dynamodb export", func(t *testing.T) {
+		const content = `{"Item": {"id": {"S": "1-0"}, "origin": {"S": "order-topic"}, "schemaURI": {"S": "http://schema-repository:8080/schemas/order/1.0.0"}, "timestamp": {"S": "2025-01-01T10:00:00Z"}, "data": {"S": "{\"orderId\": 1}"}}}
+{"Item": {"id": {"S": "2-0"}, "origin": {"S": "order-topic"}, "schemaURI": {"S": "http://schema-repository:8080/schemas/order/1.0.0"}, "timestamp": {"S": "2025-01-01T10:00:00.2Z"}, "data": {"S": "{\"orderId\": 2}"}}}
+`
+		p := &fakeProducer{}
+		r := newReplay(t, p, content, config.Replay{
+			Format:         config.FormatDynamoDB,
+			Speed:          2,
+			KeepTimestamps: true,
+			Value:          "data",
+			Key:            "id",
+			Topic:          "origin",
+			Timestamp:      "timestamp",
+			Headers:        map[string]string{"schemaURI": "schemaURI"},
+		})
+
+		start := time.Now()
+		_, err := r.Run(context.Background())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+		require.Len(t, p.records, 2)
+		assert.Equal(t, `{"orderId": 2}`, string(p.records[1].Value))
+		assert.Equal(t, "order-topic", p.records[1].Topic)
+		assert.Equal(t, time.Date(2025, 1, 1, 10, 0, 0, 200_000_000, time.UTC), p.records[1].Timestamp)
+		assert.Equal(t, "http://schema-repository:8080/schemas/order/1.0.0", string(p.records[1].Headers[0].Value))
+	})
+
+	t.Run("dynamodb scan", func(t *testing.T) {
+		const content = `{
+	"Items": [
+		{"id": {"S": "1"}, "total": {"N": "10.5"}, "tags": {"L": [{"S": "a"}, {"BOOL": true}]}, "meta": {"M": {"none": {"NULL": true}}}},
+		{"id": {"S": "2"}, "total": {"N": "3"}, "tags": {"SS": ["b"]}, "meta": {"M": {}}}
+	],
+	"Count": 2
+}`
+		p := &fakeProducer{}
+		r := newReplay(t, p, content, config.Replay{Format: config.FormatDynamoDB, DefaultTopic: "orders"})
+
+		_, err := r.Run(context.Background())
+		require.NoError(t, err)
+		require.Len(t, p.records, 2)
+		assert.JSONEq(t, `{"id": "1", "total": 10.5, "tags": ["a", true], "meta": {"none": null}}`, string(p.records[0].Value))
+		assert.JSONEq(t, `{"id": "2", "total": 3, "tags": ["b"], "meta": {}}`, string(p.records[1].Value))
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		p := &fakeProducer{}
+		r := newReplay(t, p, "{\"a\": 1}\n{\"b\": \n", config.Replay{DefaultTopic: "t"})
+
+		summary, err := r.Run(context.Background())
+		assert.ErrorContains(t, err, "unexpected EOF")
+		assert.Equal(t, uint64(1), summary.Acked)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		const content = `{"ts": 0}
+{"ts": 60000}
+`
+		p := &fakeProducer{}
+		r := newReplay(t, p, content, config.Replay{DefaultTopic: "t", Speed: 1, Timestamp: "ts"})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		summary, err := r.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), summary.Sent)
+		assert.True(t, p.records[0].Timestamp.IsZero())
+	})
+}
+
+func TestReplay_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Replay
+		err  string
+	}{
+		{name: "format", cfg: config.Replay{Format: "csv", DefaultTopic: "t"}, err: `unknown replay format "csv"`},
+		{name: "speed", cfg: config.Replay{Format: "json", DefaultTopic: "t", Speed: -1}, err: "replay speed can't be negative, got -1"},
+		{
+			name: "timestamp",
+			cfg:  config.Replay{Format: "json", DefaultTopic: "t", KeepTimestamps: true},
+			err:  "replay timestamp field is required to re-time records or keep their timestamp",
+		},
+		{name: "topic", cfg: config.Replay{Format: "json"}, err: "replay topic field or default topic is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, tt.cfg.Validate(), tt.err)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/mfelipe/go-feijoada/kafka-producer/config"
+	utilshttp "github.com/mfelipe/go-feijoada/utils/http"
+	"github.com/mfelipe/go-feijoada/utils/schemabody"
+)
+
+// schemaPath matches the path schema-repository serves a schema version at
+var schemaPath = regexp.MustCompile(`/schemas/([^/]+)/([^/]+)$`)
+
+// schemaLoader loads the schemas payloads are synthesized from, reading them from the schemas directory when there,
+// otherwise fetching them
+type schemaLoader struct {
+	cfg    config.Schemas
+	client *http.Client
+}
+
+func newSchemaLoader(cfg config.Schemas) *schemaLoader {
+	return &schemaLoader{
+		cfg:    cfg,
+		client: &http.Client{Transport: utilshttp.CustomPooledTransport(), Timeout: cfg.Timeout},
+	}
+}
+
+// uri is where schema-repository serves a schema version
+func (l *schemaLoader) uri(name, version string) string {
+	return fmt.Sprintf("%s/schemas/%s/%s", l.cfg.RepositoryURI, name, version)
+}
+
+// load returns the schema identified by uri. Any /schemas/<name>/<version> URI is looked up in the schemas directory
+// first, so references keep working whatever the host of their $id
+func (l *schemaLoader) load(uri string) ([]byte, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if m := schemaPath.FindStringSubmatch(u.Path); m != nil && l.cfg.Dir != "" {
+		data, err := os.ReadFile(filepath.Join(l.cfg.Dir, m[1]+"-"+m[2]+".json"))
+		if !errors.Is(err, fs.ErrNotExist) {
+			return data, err
+		}
+	}
+	if u.Scheme == "file" {
+		return os.ReadFile(u.Path)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: status %d", uri, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return unwrap(uri, body)
+}
+
+// unwrap extracts the schema of a schema-repository response body, which must be a JSON Schema
+func unwrap(uri string, body []byte) ([]byte, error) {
+	schemaType, schema := schemabody.Unwrap(body)
+	if schemaType != schemabody.TypeJSONSchema {
+		return nil, fmt.Errorf("%s is a %s schema, only JSON Schemas are synthesized", uri, schemaType)
+	}
+	return schema, nil
+}
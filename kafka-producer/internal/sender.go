@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Producer produces records asynchronously, calling promise once each one is acknowledged, as kgo.Client does
+type Producer interface {
+	Produce(ctx context.Context, r *kgo.Record, promise func(*kgo.Record, error))
+	Flush(ctx context.Context) error
+}
+
+// sender produces records, accumulating their stats for the reports and the summary
+type sender struct {
+	producer     Producer
+	closeTimeout time.Duration
+	// reportInterval is the period of the reports, zero disabling them
+	reportInterval time.Duration
+	nextReport     time.Time
+	stats          *stats
+}
+
+// start resets the stats, returning the start time
+func (s *sender) start() time.Time {
+	now := time.Now()
+	s.stats = newStats(now)
+	s.nextReport = now.Add(s.reportInterval)
+	return now
+}
+
+// reportDue returns the summary of the current report interval once it has elapsed
+func (s *sender) reportDue(now time.Time) (Summary, bool) {
+	if s.reportInterval <= 0 || now.Before(s.nextReport) {
+		return Summary{}, false
+	}
+	s.nextReport = s.nextReport.Add(s.reportInterval)
+	return s.stats.report(now), true
+}
+
+// send produces a record, injected with a fault unless empty
+func (s *sender) send(ctx context.Context, record *kgo.Record, fault string) {
+	zlog.Debug().Str("topic", record.Topic).Str("fault", fault).Msg("Producing record")
+	s.stats.sent(fault)
+	start := time.Now()
+	s.producer.Produce(ctx, record, func(r *kgo.Record, err error) {
+		s.stats.acked(time.Since(start), err)
+		if err != nil {
+			zlog.Error().Err(err).Str("topic", r.Topic).Msg("failed to produce record")
+		}
+	})
+}
+
+// close waits up to the close timeout for the records to be acknowledged, returning the summary of every record
+func (s *sender) close(ctx context.Context) Summary {
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.closeTimeout)
+	defer cancel()
+	if err := s.producer.Flush(flushCtx); err != nil {
+		zlog.Warn().Err(err).Msg("records not acknowledged before the close timeout")
+	}
+	return s.stats.summary(time.Now())
+}
@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+const (
+	// histogramBuckets of histogramGrowth keep latencies from 1µs to over a minute within 2% of their value
+	histogramBuckets = 1024
+	histogramGrowth  = 1.02
+)
+
+// histogram counts latencies in buckets growing exponentially, so percentiles take a fixed memory whatever the load
+type histogram struct {
+	counts [histogramBuckets]uint64
+	count  uint64
+	max    time.Duration
+}
+
+func bucketOf(d time.Duration) int {
+	if d <= time.Microsecond {
+		return 0
+	}
+	i := int(math.Ceil(math.Log(float64(d)/float64(time.Microsecond)) / math.Log(histogramGrowth)))
+	return min(i, histogramBuckets-1)
+}
+
+// bucketUpper is the greatest latency of a bucket
+func bucketUpper(i int) time.Duration {
+	return time.Duration(float64(time.Microsecond) * math.Pow(histogramGrowth, float64(i)))
+}
+
+func (h *histogram) record(d time.Duration) {
+	h.counts[bucketOf(d)]++
+	h.count++
+	h.max = max(h.max, d)
+}
+
+// percentile returns the latency q (0 to 1) of the recorded latencies are lower or equal to
+func (h *histogram) percentile(q float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(h.count)))
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			return min(bucketUpper(i), h.max)
+		}
+	}
+	return h.max
+}
+
+// Summary is the throughput and produce latencies of a period of a load
+type Summary struct {
+	Elapsed time.Duration `json:"elapsed"`
+	// Sent records were handed to the producer, Acked ones were written by the brokers and Failed ones were not
+	Sent   uint64 `json:"sent"`
+	Acked  uint64 `json:"acked"`
+	Failed uint64 `json:"failed"`
+	// Faults counts the records injected with a fault, by kind
+	Faults map[string]uint64 `json:"faults,omitempty"`
+	// Errors counts the Failed records by error, Kafka ones by their name
+	Errors map[string]uint64 `json:"errors,omitempty"`
+	// Throughput is the count of acked records per second
+	Throughput float64 `json:"throughput"`
+	// Latencies are from handing a record to the producer to its acknowledgement
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+func (s Summary) MarshalZerologObject(e *zerolog.Event) {
+	e.Dur("elapsed", s.Elapsed).
+		Uint64("sent", s.Sent).
+		Uint64("acked", s.Acked).
+		Uint64("failed", s.Failed).
+		Dict("faults", countsDict(s.Faults)).
+		Dict("errors", countsDict(s.Errors)).
+		Float64("throughput", math.Round(s.Throughput*100)/100).
+		Dur("p50", s.P50).
+		Dur("p90", s.P90).
+		Dur("p99", s.P99).
+		Dur("max", s.Max)
+}
+
+func countsDict(counts map[string]uint64) *zerolog.Event {
+	d := zerolog.Dict()
+	for _, key := range slices.Sorted(maps.Keys(counts)) {
+		d.Uint64(key, counts[key])
+	}
+	return d
+}
+
+// errorKind names an error of the producer, Kafka errors by their name so their counts don't depend on the partition
+func errorKind(err error) string {
+	var kErr *kerr.Error
+	if errors.As(err, &kErr) {
+		return kErr.Message
+	}
+	return err.Error()
+}
+
+// period accumulates the records of a period
+type period struct {
+	start               time.Time
+	sent, acked, failed uint64
+	faults, errors      map[string]uint64
+	latencies           histogram
+}
+
+func (p *period) summary(now time.Time) Summary {
+	s := Summary{
+		Elapsed: now.Sub(p.start),
+		Sent:    p.sent,
+		Acked:   p.acked,
+		Failed:  p.failed,
+		Faults:  maps.Clone(p.faults),
+		Errors:  maps.Clone(p.errors),
+		P50:     p.latencies.percentile(0.50),
+		P90:     p.latencies.percentile(0.90),
+		P99:     p.latencies.percentile(0.99),
+		Max:     p.latencies.max,
+	}
+	if s.Elapsed > 0 {
+		s.Throughput = float64(s.Acked) / s.Elapsed.Seconds()
+	}
+	return s
+}
+
+// stats accumulates the records of the whole load and of the current report interval. Acknowledgements are recorded
+// from the goroutines of the producer
+type stats struct {
+	mu       sync.Mutex
+	total    period
+	interval period
+}
+
+func newStats(now time.Time) *stats {
+	return &stats{total: period{start: now}, interval: period{start: now}}
+}
+
+// sent counts a record, injected with a fault unless empty
+func (s *stats) sent(fault string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range []*period{&s.total, &s.interval} {
+		p.sent++
+		if fault == "" {
+			continue
+		}
+		if p.faults == nil {
+			p.faults = make(map[string]uint64)
+		}
+		p.faults[fault]++
+	}
+}
+
+// acked records the outcome of a record, failed unless err is nil
+func (s *stats) acked(latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range []*period{&s.total, &s.interval} {
+		if err != nil {
+			p.failed++
+			if p.errors == nil {
+				p.errors = make(map[string]uint64)
+			}
+			p.errors[errorKind(err)]++
+			continue
+		}
+		p.acked++
+		p.latencies.record(latency)
+	}
+}
+
+// report returns the summary of the current interval, starting a new one
+func (s *stats) report(now time.Time) Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	summary := s.interval.summary(now)
+	s.interval = period{start: now}
+	return summary
+}
+
+func (s *stats) summary(now time.Time) Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total.summary(now)
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+func TestHistogram_Percentile(t *testing.T) {
+	h := histogram{}
+	assert.Zero(t, h.percentile(0.5))
+
+	for i := 1; i <= 1000; i++ {
+		h.record(time.Duration(i) * time.Millisecond)
+	}
+
+	for q, want := range map[float64]time.Duration{0.5: 500 * time.Millisecond, 0.9: 900 * time.Millisecond, 0.99: 990 * time.Millisecond} {
+		assert.InEpsilon(t, float64(want), float64(h.percentile(q)), histogramGrowth-1)
+	}
+	assert.Equal(t, time.Second, h.percentile(1))
+	assert.Equal(t, time.Second, h.max)
+}
+
+func TestStats(t *testing.T) {
+	start := time.Now()
+	s := newStats(start)
+	for range 3 {
+		s.sent("")
+	}
+	s.acked(time.Millisecond, nil)
+	s.acked(3*time.Millisecond, nil)
+	s.acked(0, errors.New("failed"))
+	s.acked(0, fmt.Errorf("producing: %w", kerr.NotLeaderForPartition))
+
+	report := s.report(start.Add(time.Second))
+	assert.Equal(t, uint64(3), report.Sent)
+	assert.Equal(t, uint64(2), report.Acked)
+	assert.Equal(t, uint64(2), report.Failed)
+	assert.Equal(t, map[string]uint64{"failed": 1, "NOT_LEADER_FOR_PARTITION": 1}, report.Errors)
+	assert.InDelta(t, 2, report.Throughput, 0.001)
+	assert.Equal(t, 3*time.Millisecond, report.Max)
+
+	s.sent("")
+	s.acked(time.Millisecond, nil)
+	report = s.report(start.Add(2 * time.Second))
+	assert.Equal(t, uint64(1), report.Sent)
+	assert.InDelta(t, 1, report.Throughput, 0.001)
+
+	summary := s.summary(start.Add(2 * time.Second))
+	assert.Equal(t, uint64(4), summary.Sent)
+	assert.Equal(t, uint64(3), summary.Acked)
+	assert.InDelta(t, 1.5, summary.Throughput, 0.001)
+}
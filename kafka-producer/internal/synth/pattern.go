@@ -0,0 +1,97 @@
+package synth
+
+import (
+	"math/rand/v2"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// maxRepeat bounds the repetitions of unbounded quantifiers, such as * or {2,}
+const maxRepeat = 8
+
+// pattern generates strings matching a regular expression. Anchors and word boundaries are ignored, as if every
+// pattern was anchored, so generated strings also match unanchored patterns
+type pattern struct {
+	re *syntax.Regexp
+}
+
+func compilePattern(expr string) (*pattern, error) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	return &pattern{re: re.Simplify()}, nil
+}
+
+func (p *pattern) generate() string {
+	var sb strings.Builder
+	writeRegexp(&sb, p.re)
+	return sb.String()
+}
+
+func writeRegexp(sb *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && rand.IntN(2) == 0 {
+				r = unicode.SimpleFold(r)
+			}
+			sb.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		sb.WriteRune(classRune(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		sb.WriteRune(rune('a' + rand.IntN(26)))
+	case syntax.OpCapture:
+		writeRegexp(sb, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeRegexp(sb, sub)
+		}
+	case syntax.OpAlternate:
+		writeRegexp(sb, re.Sub[rand.IntN(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := repeatBounds(re)
+		for range lo + rand.IntN(hi-lo+1) {
+			writeRegexp(sb, re.Sub[0])
+		}
+	}
+}
+
+func repeatBounds(re *syntax.Regexp) (lo, hi int) {
+	switch re.Op {
+	case syntax.OpStar:
+		return 0, maxRepeat
+	case syntax.OpPlus:
+		return 1, maxRepeat
+	case syntax.OpQuest:
+		return 0, 1
+	}
+	lo, hi = re.Min, re.Max
+	if hi < 0 {
+		hi = lo + maxRepeat
+	}
+	return lo, hi
+}
+
+// classRune picks a rune of a class, given as pairs of inclusive ranges. Printable ASCII runes are preferred, so
+// negated classes such as [^,] don't produce control or unassigned runes
+func classRune(ranges []rune) rune {
+	var printable [][2]rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := max(ranges[i], ' '), min(ranges[i+1], '~')
+		if lo <= hi {
+			printable = append(printable, [2]rune{lo, hi})
+		}
+	}
+	if len(printable) > 0 {
+		r := printable[rand.IntN(len(printable))]
+		return r[0] + rand.N(r[1]-r[0]+1)
+	}
+	if len(ranges) < 2 {
+		return 'a'
+	}
+	i := rand.IntN(len(ranges)/2) * 2
+	return ranges[i] + rand.N(min(ranges[i+1]-ranges[i]+1, 256))
+}
@@ -0,0 +1,590 @@
+// Package synth synthesizes random payloads conforming to a JSON Schema: its types, enums, required properties,
+// bounds, patterns and formats, following references to the other schemas served by schema-repository
+package synth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/brianvoe/gofakeit/v6"
+
+	"github.com/mfelipe/go-feijoada/utils/vocabulary"
+)
+
+const (
+	// maxDepth stops generating optional properties and items of nested schemas, so recursive schemas end
+	maxDepth = 8
+	// maxTries bounds the candidates of values that must satisfy several constraints, such as unique items
+	maxTries = 20
+)
+
+// ErrUnsatisfiable is returned for schemas no value was generated for, such as false
+var ErrUnsatisfiable = errors.New("schema can't be satisfied")
+
+// Loader returns the content of the schema identified by an absolute URI, without fragment
+type Loader func(uri string) ([]byte, error)
+
+// Synthesizer generates payloads of a schema. It is safe for concurrent use
+type Synthesizer struct {
+	uri  string
+	docs map[string]any
+}
+
+// New loads the schema identified by uri and every schema it references
+func New(uri string, load Loader) (*Synthesizer, error) {
+	s := &Synthesizer{uri: stripFragment(uri), docs: make(map[string]any)}
+	if err := s.load(s.uri, load); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// FromSchema builds a synthesizer of a schema, which may only reference its own subschemas
+func FromSchema(schema []byte) (*Synthesizer, error) {
+	const uri = "urn:synth:schema"
+	return New(uri, func(string) ([]byte, error) { return schema, nil })
+}
+
+func (s *Synthesizer) load(uri string, load Loader) error {
+	if _, ok := s.docs[uri]; ok {
+		return nil
+	}
+	data, err := load(uri)
+	if err != nil {
+		return err
+	}
+	doc, err := decode(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", uri, err)
+	}
+	s.docs[uri] = doc
+
+	var refs []string
+	collectRefs(uri, doc, &refs)
+	for _, ref := range refs {
+		if err = s.load(ref, load); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectRefs appends the documents referenced by a schema and its subschemas
+func collectRefs(base string, schema any, refs *[]string) {
+	switch v := schema.(type) {
+	case map[string]any:
+		if id, ok := v["$id"].(string); ok {
+			base = resolve(base, id)
+		}
+		if ref, ok := v["$ref"].(string); ok {
+			*refs = append(*refs, stripFragment(resolve(base, ref)))
+		}
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			if k != "enum" && k != "const" && k != "examples" && k != "default" {
+				collectRefs(base, v[k], refs)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			collectRefs(base, item, refs)
+		}
+	}
+}
+
+// Generate returns a random payload conforming to the schema
+func (s *Synthesizer) Generate() (any, error) {
+	return s.generate(s.uri, s.docs[s.uri], 0)
+}
+
+func (s *Synthesizer) generate(base string, schema any, depth int) (any, error) {
+	switch v := schema.(type) {
+	case bool:
+		if !v {
+			return nil, ErrUnsatisfiable
+		}
+		return gofakeit.Word(), nil
+	case map[string]any:
+		if id, ok := v["$id"].(string); ok {
+			base = resolve(base, id)
+		}
+		return s.generateSchema(base, v, depth)
+	default:
+		return nil, fmt.Errorf("invalid schema %T", schema)
+	}
+}
+
+func (s *Synthesizer) generateSchema(base string, schema map[string]any, depth int) (any, error) {
+	if ref, ok := schema["$ref"].(string); ok {
+		refBase, target, err := s.resolveRef(base, ref)
+		if err != nil {
+			return nil, err
+		}
+		return s.generate(refBase, target, depth)
+	}
+	if c, ok := schema["const"]; ok {
+		return c, nil
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[rand.IntN(len(enum))], nil
+	}
+	if all, ok := schema["allOf"].([]any); ok {
+		merged, err := s.merge(base, schema, all)
+		if err != nil {
+			return nil, err
+		}
+		return s.generateSchema(base, merged, depth)
+	}
+	for _, k := range []string{"oneOf", "anyOf"} {
+		if branches, ok := schema[k].([]any); ok && len(branches) > 0 {
+			return s.generate(base, branches[rand.IntN(len(branches))], depth)
+		}
+	}
+
+	switch t := schemaType(schema); t {
+	case "object":
+		return s.generateObject(base, schema, depth)
+	case "array":
+		return s.generateArray(base, schema, depth)
+	case "string":
+		return generateString(schema)
+	case "integer":
+		return generateInteger(schema)
+	case "number":
+		return generateNumber(schema), nil
+	case "boolean":
+		return rand.IntN(2) == 0, nil
+	case "null":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrUnsatisfiable, t)
+	}
+}
+
+// resolveRef returns the subschema a reference points to, and its base URI
+func (s *Synthesizer) resolveRef(base, ref string) (string, any, error) {
+	target := resolve(base, ref)
+	docURI, fragment, _ := strings.Cut(target, "#")
+	doc, ok := s.docs[docURI]
+	if !ok {
+		return "", nil, fmt.Errorf("unresolved reference %s", target)
+	}
+	if fragment == "" {
+		return docURI, doc, nil
+	}
+
+	fragment, err := url.PathUnescape(fragment)
+	if err != nil {
+		return "", nil, err
+	}
+	current := doc
+	for _, token := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := current.(type) {
+		case map[string]any:
+			current, ok = v[token]
+		case []any:
+			i, err := strconv.Atoi(token)
+			ok = err == nil && i >= 0 && i < len(v)
+			if ok {
+				current = v[i]
+			}
+		default:
+			ok = false
+		}
+		if !ok {
+			return "", nil, fmt.Errorf("unresolved reference %s", target)
+		}
+	}
+	return docURI, current, nil
+}
+
+// merge combines the subschemas of allOf into a schema, resolving their references. Properties and required
+// properties are joined, and the tightest bounds are kept
+func (s *Synthesizer) merge(base string, schema map[string]any, all []any) (map[string]any, error) {
+	merged := maps.Clone(schema)
+	delete(merged, "allOf")
+	for _, sub := range all {
+		subBase := base
+		for {
+			m, ok := sub.(map[string]any)
+			if !ok {
+				break
+			}
+			ref, ok := m["$ref"].(string)
+			if !ok {
+				break
+			}
+			var err error
+			if subBase, sub, err = s.resolveRef(subBase, ref); err != nil {
+				return nil, err
+			}
+		}
+		m, ok := sub.(map[string]any)
+		if !ok {
+			continue
+		}
+		if nested, ok := m["allOf"].([]any); ok {
+			var err error
+			if m, err = s.merge(subBase, m, nested); err != nil {
+				return nil, err
+			}
+		}
+		for k, v := range m {
+			switch k {
+			case "properties":
+				props, _ := merged[k].(map[string]any)
+				props = maps.Clone(props)
+				if props == nil {
+					props = make(map[string]any)
+				}
+				maps.Copy(props, v.(map[string]any))
+				merged[k] = props
+			case "required":
+				required, _ := merged[k].([]any)
+				merged[k] = append(slices.Clone(required), v.([]any)...)
+			case "minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties":
+				if current, ok := number(merged[k]); !ok || current < mustNumber(v) {
+					merged[k] = v
+				}
+			case "maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties":
+				if current, ok := number(merged[k]); !ok || current > mustNumber(v) {
+					merged[k] = v
+				}
+			default:
+				if _, ok := merged[k]; !ok {
+					merged[k] = v
+				}
+			}
+		}
+	}
+	return merged, nil
+}
+
+func (s *Synthesizer) generateObject(base string, schema map[string]any, depth int) (any, error) {
+	properties, _ := schema["properties"].(map[string]any)
+	required := map[string]bool{}
+	if r, ok := schema["required"].([]any); ok {
+		for _, name := range r {
+			required[fmt.Sprint(name)] = true
+		}
+	}
+	minProperties, _ := integer(schema["minProperties"])
+
+	object := make(map[string]any)
+	names := slices.Sorted(maps.Keys(properties))
+	rand.Shuffle(len(names), func(i, j int) { names[i], names[j] = names[j], names[i] })
+	for _, name := range names {
+		if !required[name] && (depth >= maxDepth || rand.IntN(2) == 0) {
+			continue
+		}
+		value, err := s.generate(base, properties[name], depth+1)
+		if errors.Is(err, ErrUnsatisfiable) && !required[name] {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		object[name] = value
+	}
+
+	// Required properties without a schema, and properties missing to reach minProperties, are additional properties
+	additional, ok := schema["additionalProperties"]
+	if !ok {
+		additional = map[string]any{"type": "string"}
+	}
+	missing := slices.Sorted(maps.Keys(required))
+	for i := 0; len(object) < minProperties && i < maxTries; i++ {
+		missing = append(missing, gofakeit.Word())
+	}
+	for _, name := range missing {
+		if _, ok := object[name]; ok {
+			continue
+		}
+		sub, ok := properties[name]
+		if !ok {
+			sub = additional
+		}
+		value, err := s.generate(base, sub, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		object[name] = value
+	}
+	return object, nil
+}
+
+func (s *Synthesizer) generateArray(base string, schema map[string]any, depth int) (any, error) {
+	prefixItems, _ := schema["prefixItems"].([]any)
+	items, hasItems := schema["items"]
+	if !hasItems {
+		items = true
+	}
+	minItems, _ := integer(schema["minItems"])
+	maxItems, ok := integer(schema["maxItems"])
+	if !ok {
+		maxItems = max(minItems, len(prefixItems)) + 3
+	}
+	if items == false {
+		maxItems = min(maxItems, len(prefixItems))
+	}
+	if depth >= maxDepth {
+		maxItems = minItems
+	}
+	unique, _ := schema["uniqueItems"].(bool)
+
+	size := minItems + rand.IntN(max(maxItems-minItems, 0)+1)
+	array := make([]any, 0, size)
+	seen := make(map[string]bool)
+	for i, tries := 0, 0; len(array) < size; tries++ {
+		sub := items
+		if i < len(prefixItems) {
+			sub = prefixItems[i]
+		}
+		value, err := s.generate(base, sub, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", i, err)
+		}
+		if unique {
+			key, _ := json.Marshal(value)
+			if seen[string(key)] {
+				if tries >= maxTries*size {
+					return nil, fmt.Errorf("%w: not enough unique items", ErrUnsatisfiable)
+				}
+				continue
+			}
+			seen[string(key)] = true
+		}
+		array = append(array, value)
+		i++
+	}
+	return array, nil
+}
+
+func generateString(schema map[string]any) (any, error) {
+	minLength, _ := integer(schema["minLength"])
+	maxLength, ok := integer(schema["maxLength"])
+	if !ok {
+		maxLength = math.MaxInt
+	}
+
+	generate := func() string {
+		// Strings without a bound are kept short, as words
+		n := minLength + rand.IntN(max(min(maxLength, minLength+12)-minLength, 0)+1)
+		return gofakeit.LetterN(uint(n))
+	}
+	if expr, ok := schema["pattern"].(string); ok {
+		p, err := compilePattern(expr)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", expr, err)
+		}
+		generate = p.generate
+	} else if format, ok := schema["format"].(string); ok {
+		if f, ok := formats[format]; ok {
+			generate = f
+		}
+	}
+
+	for range maxTries {
+		if s := generate(); fitsLength(s, minLength, maxLength) {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no string of %d to %d characters generated", ErrUnsatisfiable, minLength, maxLength)
+}
+
+func fitsLength(s string, minLength, maxLength int) bool {
+	n := len([]rune(s))
+	return n >= minLength && n <= maxLength
+}
+
+// formats generate values of the well known formats, and of the built-in formats of the vocabulary
+var formats = map[string]func() string{
+	"email":         gofakeit.Email,
+	"idn-email":     gofakeit.Email,
+	"date-time":     func() string { return gofakeit.Date().UTC().Format("2006-01-02T15:04:05Z07:00") },
+	"date":          func() string { return gofakeit.Date().Format("2006-01-02") },
+	"time":          func() string { return gofakeit.Date().UTC().Format("15:04:05Z") },
+	"duration":      func() string { return fmt.Sprintf("PT%dM", gofakeit.IntRange(1, 600)) },
+	"uuid":          gofakeit.UUID,
+	"uri":           gofakeit.URL,
+	"iri":           gofakeit.URL,
+	"uri-reference": gofakeit.URL,
+	"hostname":      gofakeit.DomainName,
+	"idn-hostname":  gofakeit.DomainName,
+	"ipv4":          gofakeit.IPv4Address,
+	"ipv6":          gofakeit.IPv6Address,
+	vocabulary.FormatCurrency: func() string {
+		return gofakeit.RandomString([]string{"BRL", "USD", "EUR", "GBP", "JPY", "ARS", "CAD", "CHF", "MXN", "AUD"})
+	},
+	vocabulary.FormatPhone: func() string { return "+" + strconv.Itoa(gofakeit.IntRange(1, 9)) + gofakeit.DigitN(10) },
+}
+
+func generateInteger(schema map[string]any) (any, error) {
+	lo, hi := bounds(schema, 0, 10000)
+	lo, hi = math.Ceil(lo), math.Floor(hi)
+	if exclusive, ok := number(schema["exclusiveMinimum"]); ok && lo <= exclusive {
+		lo = math.Floor(exclusive) + 1
+	}
+	if exclusive, ok := number(schema["exclusiveMaximum"]); ok && hi >= exclusive {
+		hi = math.Ceil(exclusive) - 1
+	}
+
+	step := 1.0
+	if m, ok := number(schema["multipleOf"]); ok && m > 0 {
+		step = m
+	}
+	first, last := math.Ceil(lo/step), math.Floor(hi/step)
+	if first > last {
+		return nil, fmt.Errorf("%w: no integer between %v and %v", ErrUnsatisfiable, lo, hi)
+	}
+	return int64((first + float64(rand.Int64N(int64(last-first)+1))) * step), nil
+}
+
+func generateNumber(schema map[string]any) any {
+	lo, hi := bounds(schema, 0, 10000)
+	decimals := 2
+	if d, ok := integer(schema[vocabulary.KeywordMaxDecimals]); ok {
+		decimals = d
+	}
+	scale := math.Pow(10, float64(decimals))
+
+	if m, ok := number(schema["multipleOf"]); ok && m > 0 {
+		first, last := math.Ceil(lo/m), math.Floor(hi/m)
+		if _, exclusive := number(schema["exclusiveMinimum"]); exclusive && first*m <= lo {
+			first++
+		}
+		if _, exclusive := number(schema["exclusiveMaximum"]); exclusive && last*m >= hi {
+			last--
+		}
+		return (first + float64(rand.Int64N(max(int64(last-first), 0)+1))) * m
+	}
+
+	for range maxTries {
+		v := math.Round((lo+rand.Float64()*(hi-lo))*scale) / scale
+		if inBounds(schema, v) {
+			return v
+		}
+	}
+	return math.Round((lo+hi)/2*scale) / scale
+}
+
+// bounds returns the inclusive or exclusive bounds of a number, defaulting to a range of width span
+func bounds(schema map[string]any, lo, span float64) (float64, float64) {
+	minimum, hasMin := number(schema["minimum"])
+	if exclusive, ok := number(schema["exclusiveMinimum"]); ok && (!hasMin || exclusive > minimum) {
+		minimum, hasMin = exclusive, true
+	}
+	maximum, hasMax := number(schema["maximum"])
+	if exclusive, ok := number(schema["exclusiveMaximum"]); ok && (!hasMax || exclusive < maximum) {
+		maximum, hasMax = exclusive, true
+	}
+	switch {
+	case hasMin && hasMax:
+		return minimum, maximum
+	case hasMin:
+		return minimum, minimum + span
+	case hasMax:
+		return min(lo, maximum-span), maximum
+	default:
+		return lo, lo + span
+	}
+}
+
+func inBounds(schema map[string]any, v float64) bool {
+	if m, ok := number(schema["minimum"]); ok && v < m {
+		return false
+	}
+	if m, ok := number(schema["maximum"]); ok && v > m {
+		return false
+	}
+	if m, ok := number(schema["exclusiveMinimum"]); ok && v <= m {
+		return false
+	}
+	if m, ok := number(schema["exclusiveMaximum"]); ok && v >= m {
+		return false
+	}
+	return true
+}
+
+// schemaType picks one of the types of a schema, inferring it from its keywords when absent
+func schemaType(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		if len(t) > 0 {
+			return fmt.Sprint(t[rand.IntN(len(t))])
+		}
+	}
+	for _, k := range []string{"properties", "required", "additionalProperties", "minProperties"} {
+		if _, ok := schema[k]; ok {
+			return "object"
+		}
+	}
+	for _, k := range []string{"items", "prefixItems", "minItems", "maxItems", "uniqueItems"} {
+		if _, ok := schema[k]; ok {
+			return "array"
+		}
+	}
+	for _, k := range []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"} {
+		if _, ok := schema[k]; ok {
+			return "number"
+		}
+	}
+	return "string"
+}
+
+func decode(data []byte) (any, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func number(v any) (float64, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+func mustNumber(v any) float64 {
+	f, _ := number(v)
+	return f
+}
+
+func integer(v any) (int, bool) {
+	f, ok := number(v)
+	return int(f), ok
+}
+
+func resolve(base, ref string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
+
+func stripFragment(uri string) string {
+	u, _, _ := strings.Cut(uri, "#")
+	return u
+}
@@ -0,0 +1,182 @@
+package synth
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	schemavalidator "github.com/mfelipe/go-feijoada/schema-validator"
+	svcfg "github.com/mfelipe/go-feijoada/schema-validator/config"
+	"github.com/mfelipe/go-feijoada/utils/vocabulary"
+)
+
+const (
+	baseURI    = "http://schema-repository:8080"
+	schemasDir = "../../../schemas/schemas"
+	samples    = 200
+)
+
+func newValidator(t *testing.T) schemavalidator.SchemaValidator {
+	v := schemavalidator.New(svcfg.Config{
+		DefaultBaseURI: baseURI,
+		Offline:        true,
+		Preload:        &svcfg.Preload{Dir: schemasDir},
+		Vocabulary:     &vocabulary.Config{AssertFormat: true},
+	})
+	require.NoError(t, v.Preload(context.Background()))
+	return v
+}
+
+// assertValid validates samples payloads of a synthesizer against the schema identified by uri
+func assertValid(t *testing.T, v schemavalidator.SchemaValidator, s *Synthesizer, uri string) {
+	for range samples {
+		payload, err := s.Generate()
+		require.NoError(t, err)
+		data, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		report, err := v.Validate(uri, data)
+		require.NoError(t, err)
+		require.True(t, report.Valid, "%s: %s", data, report.Errors)
+	}
+}
+
+func TestSynthesizer_Repository(t *testing.T) {
+	v := newValidator(t)
+	load := func(uri string) ([]byte, error) {
+		m := regexp.MustCompile(`/schemas/([^/]+)/([^/]+)$`).FindStringSubmatch(uri)
+		return os.ReadFile(filepath.Join(schemasDir, m[1]+"-"+m[2]+".json"))
+	}
+
+	files, err := filepath.Glob(filepath.Join(schemasDir, "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		name, version, _ := strings.Cut(strings.TrimSuffix(filepath.Base(file), ".json"), "-")
+		t.Run(name+"@"+version, func(t *testing.T) {
+			uri := baseURI + "/schemas/" + name + "/" + version
+			s, err := New(uri, load)
+			require.NoError(t, err)
+			assertValid(t, v, s, uri)
+		})
+	}
+}
+
+func TestSynthesizer_Keywords(t *testing.T) {
+	const schema = `{
+		"$id": "http://schema-repository:8080/schemas/invoice/1.0.0",
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"number": {"type": "string", "pattern": "^INV-[0-9]{4}-[A-Z]{2,3}$"},
+			"code": {"type": "string", "minLength": 3, "maxLength": 5},
+			"email": {"type": "string", "format": "email"},
+			"issuedAt": {"type": "string", "format": "date-time"},
+			"currency": {"type": "string", "format": "iso4217"},
+			"phone": {"type": "string", "format": "e164"},
+			"status": {"enum": ["open", "paid", 3]},
+			"kind": {"const": "invoice"},
+			"quantity": {"type": "integer", "minimum": 1, "exclusiveMaximum": 4},
+			"discount": {"type": "number", "exclusiveMinimum": 0, "maximum": 0.5, "maxDecimals": 2},
+			"step": {"type": "integer", "multipleOf": 5, "minimum": 12, "maximum": 40},
+			"note": {"type": ["string", "null"]},
+			"lines": {
+				"type": "array",
+				"minItems": 1,
+				"maxItems": 4,
+				"uniqueItems": true,
+				"items": {"$ref": "#/$defs/line"}
+			},
+			"pair": {"type": "array", "prefixItems": [{"type": "integer"}, {"type": "boolean"}], "items": false},
+			"address": {"$ref": "http://schema-repository:8080/schemas/address/2.0.0"},
+			"payer": {"allOf": [{"$ref": "#/$defs/party"}, {"required": ["taxId"]}]},
+			"payment": {"oneOf": [{"type": "integer"}, {"type": "string", "maxLength": 3}]},
+			"tags": {"type": "object", "minProperties": 2, "additionalProperties": {"type": "integer"}}
+		},
+		"required": ["id", "number", "code", "email", "status", "quantity", "discount", "step", "lines", "pair",
+			"address", "payer", "payment", "tags", "extra"],
+		"additionalProperties": {"type": "boolean"},
+		"$defs": {
+			"line": {
+				"type": "object",
+				"properties": {
+					"sku": {"type": "string", "pattern": "^[a-z]{3}_[0-9]+$"},
+					"price": {"type": "number", "minimum": 10, "maxDecimals": 1}
+				},
+				"required": ["sku", "price"],
+				"additionalProperties": false
+			},
+			"party": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "taxId": {"type": "string", "pattern": "^[0-9]{11}$"}},
+				"required": ["name"]
+			}
+		}
+	}`
+
+	v := newValidator(t)
+	uri := baseURI + "/schemas/invoice/1.0.0"
+	require.NoError(t, v.AddSchema(uri, json.RawMessage(schema)))
+
+	s, err := New(uri, func(u string) ([]byte, error) {
+		if u == uri {
+			return []byte(schema), nil
+		}
+		return os.ReadFile(filepath.Join(schemasDir, "address-2.0.0.json"))
+	})
+	require.NoError(t, err)
+	assertValid(t, v, s, uri)
+}
+
+func TestFromSchema(t *testing.T) {
+	s, err := FromSchema([]byte(`{"type": "array", "items": {"$ref": "#/$defs/n"}, "minItems": 2, "$defs": {"n": {"type": "integer", "maximum": -5}}}`))
+	require.NoError(t, err)
+
+	payload, err := s.Generate()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(payload.([]any)), 2)
+	for _, n := range payload.([]any) {
+		assert.LessOrEqual(t, n, int64(-5))
+	}
+}
+
+func TestSynthesizer_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		err    string
+	}{
+		{name: "false", schema: `false`, err: "schema can't be satisfied"},
+		{name: "empty range", schema: `{"type": "integer", "minimum": 3, "maximum": 2}`, err: "no integer between 3 and 2"},
+		{name: "required false", schema: `{"properties": {"a": false}, "required": ["a"]}`, err: "a: schema can't be satisfied"},
+		{name: "bad pattern", schema: `{"type": "string", "pattern": "("}`, err: "missing closing )"},
+		{name: "unresolved", schema: `{"$ref": "#/$defs/missing"}`, err: "unresolved reference urn:synth:schema#/$defs/missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := FromSchema([]byte(tt.schema))
+			require.NoError(t, err)
+			_, err = s.Generate()
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestPattern(t *testing.T) {
+	for _, expr := range []string{`^[A-Z]{3}-\d{2,4}$`, `(?i)^abc|def$`, `^[^,;]+@x\.(com|org)$`, `^\w+\s?\S*$`} {
+		p, err := compilePattern(expr)
+		require.NoError(t, err)
+		re := regexp.MustCompile(expr)
+		for range samples {
+			s := p.generate()
+			assert.Regexp(t, re, s)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Package producer publishes the models generated by the schemas module into the pipeline, from any Go service. Each
+// record is stamped with the schemaURI header its consumers validate it against, its schema being resolved from the
+// type of the model. Models can be validated locally before being sent, and their schemas registered in
+// schema-repository when missing.
+package producer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	schemarepository "github.com/mfelipe/go-feijoada/schema-repository/client"
+	schemavalidator "github.com/mfelipe/go-feijoada/schema-validator"
+	"github.com/mfelipe/go-feijoada/schemas"
+)
+
+const (
+	// SchemaURIHeader is the header holding the URI of the schema of a record
+	SchemaURIHeader = "schemaURI"
+	// DefaultBaseURI is where schema-repository serves schemas in the docker-compose stack
+	DefaultBaseURI = "http://schema-repository:8080"
+)
+
+var (
+	// ErrUnknownModel is returned when sending a value that isn't a model generated by the schemas module
+	ErrUnknownModel = errors.New("not a model of the schemas module")
+	// ErrMissingSchema is returned when registering a schema missing from both schema-repository and the local schemas
+	ErrMissingSchema = errors.New("schema missing from schema-repository and the local schemas")
+)
+
+// ValidationError is returned when a model fails the local validation against its schema
+type ValidationError struct {
+	SchemaURI string
+	Report    *schemavalidator.Report
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Report.Errors))
+	for i, err := range e.Report.Errors {
+		messages[i] = fmt.Sprintf("%s: %s", err.InstanceLocation, err.Message)
+	}
+	return fmt.Sprintf("model is invalid against %s: %s", e.SchemaURI, strings.Join(messages, ", "))
+}
+
+// Schema identifies the schema of a model, as the name and version of its schema-repository path
+type Schema struct {
+	Name    string
+	Version string
+}
+
+func (s Schema) String() string {
+	return s.Name + "@" + s.Version
+}
+
+// SchemaOf returns the schema of a model generated by the schemas module, or of a pointer to one, from the registry
+// of the schemas module
+func SchemaOf(model any) (Schema, error) {
+	id, err := schemas.SchemaID(model)
+	if err != nil {
+		return Schema{}, fmt.Errorf("%w: %T", ErrUnknownModel, model)
+	}
+	segments := strings.Split(id, "/")
+	return Schema{Name: segments[len(segments)-2], Version: segments[len(segments)-1]}, nil
+}
+
+// Client produces records, as kgo.Client does
+type Client interface {
+	ProduceSync(ctx context.Context, rs ...*kgo.Record) kgo.ProduceResults
+}
+
+// Producer sends models to Kafka topics. It is safe for concurrent use
+type Producer struct {
+	client    Client
+	baseURI   string
+	validator schemavalidator.SchemaValidator
+	registry  *schemarepository.Client
+	schemas   fs.FS
+
+	mu sync.RWMutex
+	// ready holds the schemas registered and added to the validator, as needed
+	ready map[Schema]bool
+}
+
+type Option func(*Producer)
+
+// WithBaseURI replaces DefaultBaseURI as the base of the schemaURI headers, which must match the $id of the schemas
+func WithBaseURI(uri string) Option {
+	return func(p *Producer) {
+		p.baseURI = strings.TrimSuffix(uri, "/")
+	}
+}
+
+// WithValidator validates models before sending them, failing invalid ones with a ValidationError
+func WithValidator(v schemavalidator.SchemaValidator) Option {
+	return func(p *Producer) {
+		p.validator = v
+	}
+}
+
+// WithSchemas reads the <name>-<version>.json schemas of a directory, such as the schemas/schemas directory of the
+// schemas module. They are added to the validator, so offline validators need no preloading, and registered in
+// schema-repository when missing, when WithRegistry is set
+func WithSchemas(fsys fs.FS) Option {
+	return func(p *Producer) {
+		p.schemas = fsys
+	}
+}
+
+// WithRegistry registers the schemas of the models sent in schema-repository, from WithSchemas, the first time a model
+// of each schema is sent
+func WithRegistry(c *schemarepository.Client) Option {
+	return func(p *Producer) {
+		p.registry = c
+	}
+}
+
+// New creates a Producer sending records with client, such as a kgo.Client
+func New(client Client, opts ...Option) *Producer {
+	p := &Producer{
+		client:  client,
+		baseURI: DefaultBaseURI,
+		ready:   make(map[Schema]bool),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// SchemaURI returns the URI of the schema, stamped in the schemaURI header of its records
+func (p *Producer) SchemaURI(s Schema) string {
+	return fmt.Sprintf("%s/schemas/%s/%s", p.baseURI, s.Name, s.Version)
+}
+
+// Send produces a model to topic as JSON, waiting for its acknowledgement. The schema of the model is registered the
+// first time, and the model validated, when configured
+func (p *Producer) Send(ctx context.Context, topic string, model any) error {
+	s, err := SchemaOf(model)
+	if err != nil {
+		return err
+	}
+	if err = p.prepare(ctx, s); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+
+	uri := p.SchemaURI(s)
+	if p.validator != nil {
+		report, err := p.validator.Validate(uri, value)
+		if err != nil {
+			return fmt.Errorf("validating %s: %w", s, err)
+		}
+		if !report.Valid {
+			return &ValidationError{SchemaURI: uri, Report: report}
+		}
+	}
+
+	return p.client.ProduceSync(ctx, &kgo.Record{
+		Topic:   topic,
+		Value:   value,
+		Headers: []kgo.RecordHeader{{Key: SchemaURIHeader, Value: []byte(uri)}},
+	}).FirstErr()
+}
+
+// prepare registers the schema and adds it to the validator, once
+func (p *Producer) prepare(ctx context.Context, s Schema) error {
+	if p.schemas == nil && p.registry == nil {
+		return nil
+	}
+
+	p.mu.RLock()
+	ready := p.ready[s]
+	p.mu.RUnlock()
+	if ready {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ready[s] {
+		return nil
+	}
+
+	var schema []byte
+	if p.schemas != nil {
+		var err error
+		if schema, err = fs.ReadFile(p.schemas, s.Name+"-"+s.Version+".json"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("reading schema %s: %w", s, err)
+		}
+	}
+
+	if p.registry != nil {
+		if err := p.register(ctx, s, schema); err != nil {
+			return fmt.Errorf("registering schema %s: %w", s, err)
+		}
+	}
+	if p.validator != nil && schema != nil {
+		if err := p.validator.AddSchema(p.SchemaURI(s), schema); err != nil {
+			return fmt.Errorf("adding schema %s to the validator: %w", s, err)
+		}
+	}
+
+	p.ready[s] = true
+	return nil
+}
+
+// register creates the schema in schema-repository unless it exists
+func (p *Producer) register(ctx context.Context, s Schema, schema json.RawMessage) error {
+	_, err := p.registry.GetSchema(ctx, s.Name, s.Version)
+	if !errors.Is(err, schemarepository.ErrNotFound) {
+		return err
+	}
+	if schema == nil {
+		return ErrMissingSchema
+	}
+	return p.registry.CreateSchema(ctx, s.Name, s.Version, schema)
+}
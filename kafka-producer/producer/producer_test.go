@@ -0,0 +1,145 @@
+package producer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	schemarepository "github.com/mfelipe/go-feijoada/schema-repository/client"
+	schemavalidator "github.com/mfelipe/go-feijoada/schema-validator"
+	validatorcfg "github.com/mfelipe/go-feijoada/schema-validator/config"
+	v1 "github.com/mfelipe/go-feijoada/schemas/models/v1_0_0"
+	v2 "github.com/mfelipe/go-feijoada/schemas/models/v2_0_0"
+)
+
+// fakeClient acknowledges records synchronously, failing them with err
+type fakeClient struct {
+	mu      sync.Mutex
+	records []*kgo.Record
+	err     error
+}
+
+func (c *fakeClient) ProduceSync(_ context.Context, rs ...*kgo.Record) kgo.ProduceResults {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	results := make(kgo.ProduceResults, len(rs))
+	for i, r := range rs {
+		c.records = append(c.records, r)
+		results[i] = kgo.ProduceResult{Record: r, Err: c.err}
+	}
+	return results
+}
+
+func TestSchemaOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		model  any
+		schema Schema
+		err    string
+	}{
+		{name: "model", model: v2.Order{}, schema: Schema{Name: "order", Version: "2.0.0"}},
+		{name: "pointer", model: &v1.User{}, schema: Schema{Name: "user", Version: "1.0.0"}},
+		{name: "enum", model: v2.PaymentStatusFailed, err: "not a model of the schemas module: v2_0_0.PaymentStatus"},
+		{name: "other struct", model: struct{ ID int }{}, err: "not a model of the schemas module: struct { ID int }"},
+		{name: "nil", model: nil, err: "not a model of the schemas module: <nil>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := SchemaOf(tt.model)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				assert.ErrorIs(t, err, ErrUnknownModel)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.schema, s)
+		})
+	}
+}
+
+func TestProducer_Send(t *testing.T) {
+	refunded := v2.PaymentStatus("refunded")
+	completed := v2.PaymentStatusCompleted
+
+	t.Run("stamps the schema URI", func(t *testing.T) {
+		c := &fakeClient{}
+		p := New(c)
+
+		require.NoError(t, p.Send(context.Background(), "orders", v2.Order{OrderId: 1, ProductIds: []int{2}}))
+		require.Len(t, c.records, 1)
+		assert.Equal(t, "orders", c.records[0].Topic)
+		assert.Equal(t, []kgo.RecordHeader{{Key: SchemaURIHeader, Value: []byte("http://schema-repository:8080/schemas/order/2.0.0")}}, c.records[0].Headers)
+		assert.JSONEq(t, `{"orderId": 1, "productIds": [2], "total": 0, "userId": 0}`, string(c.records[0].Value))
+	})
+
+	t.Run("validates locally", func(t *testing.T) {
+		c := &fakeClient{}
+		v := schemavalidator.New(validatorcfg.Config{DefaultBaseURI: DefaultBaseURI, Offline: true})
+		p := New(c, WithValidator(v), WithSchemas(os.DirFS("../../schemas/schemas")))
+
+		require.NoError(t, p.Send(context.Background(), "payments", v2.Payment{PaymentId: 1, Method: "pix", Status: &completed}))
+
+		err := p.Send(context.Background(), "payments", v2.Payment{PaymentId: 2, Method: "pix", Status: &refunded})
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "http://schema-repository:8080/schemas/payment/2.0.0", validationErr.SchemaURI)
+		assert.Len(t, c.records, 1)
+	})
+
+	t.Run("registers missing schemas", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			created []string
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/schemas/user/1.0.0":
+				_, _ = w.Write([]byte(`{"schema": {}}`))
+			case r.Method == http.MethodGet:
+				w.WriteHeader(http.StatusNotFound)
+			case r.Method == http.MethodPost:
+				var body struct {
+					Schema json.RawMessage `json:"schema"`
+				}
+				b, _ := io.ReadAll(r.Body)
+				require.NoError(t, json.Unmarshal(b, &body))
+				assert.Contains(t, string(body.Schema), r.URL.Path)
+				created = append(created, r.URL.Path)
+				w.WriteHeader(http.StatusCreated)
+			}
+		}))
+		defer server.Close()
+
+		c := &fakeClient{}
+		p := New(c, WithRegistry(schemarepository.New(server.URL)), WithSchemas(os.DirFS("../../schemas/schemas")))
+		for range 2 {
+			require.NoError(t, p.Send(context.Background(), "orders", v2.Order{}))
+			require.NoError(t, p.Send(context.Background(), "users", v1.User{}))
+		}
+		assert.Equal(t, []string{"/schemas/order/2.0.0"}, created)
+		assert.Len(t, c.records, 4)
+
+		p = New(c, WithRegistry(schemarepository.New(server.URL)))
+		assert.ErrorIs(t, p.Send(context.Background(), "addresses", v2.Address{}), ErrMissingSchema)
+		assert.Len(t, c.records, 4)
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		c := &fakeClient{err: errors.New("broker down")}
+		p := New(c)
+		assert.EqualError(t, p.Send(context.Background(), "orders", v2.Order{}), "broker down")
+		assert.ErrorIs(t, p.Send(context.Background(), "orders", "order"), ErrUnknownModel)
+	})
+}
@@ -0,0 +1,293 @@
+// Package api holds the OpenAPI document of the schema-repository HTTP API, and a validator that checks requests and
+// responses against it, so the document and the implementation can't drift apart.
+package api
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const (
+	documentURL = "openapi.json"
+
+	jsonContentType   = "application/json"
+	ndjsonContentType = "application/x-ndjson"
+	jsonSuffix        = "+json"
+)
+
+// Spec is the OpenAPI 3.1 document describing the API.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// Validator checks HTTP requests and responses against the OpenAPI document.
+type Validator struct {
+	doc      map[string]any
+	compiler *jsonschema.Compiler
+	routes   []route
+}
+
+type route struct {
+	template string
+	segments []string
+}
+
+// node is a value of the OpenAPI document along with its JSON pointer, needed to compile the schemas it holds
+type node struct {
+	ptr string
+	v   map[string]any
+}
+
+// NewValidator parses the embedded OpenAPI document and prepares it for validation.
+func NewValidator() (*Validator, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(Spec, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	if err := compiler.AddResource(documentURL, bytes.NewReader(Spec)); err != nil {
+		return nil, err
+	}
+
+	paths, _ := doc["paths"].(map[string]any)
+	routes := make([]route, 0, len(paths))
+	for template := range paths {
+		routes = append(routes, route{template: template, segments: strings.Split(template, "/")})
+	}
+	// Static segments take precedence over parameters, the same way the router resolves them
+	slices.SortFunc(routes, func(a, b route) int {
+		return strings.Count(a.template, "{") - strings.Count(b.template, "{")
+	})
+
+	return &Validator{doc: doc, compiler: compiler, routes: routes}, nil
+}
+
+// ValidateRequest checks the path parameters, required headers and body of a request.
+func (v *Validator) ValidateRequest(method, path string, header http.Header, body []byte) error {
+	item, op, params, err := v.operation(method, path)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range v.parameters(item, op) {
+		name, _ := p.v["name"].(string)
+		required, _ := p.v["required"].(bool)
+
+		var value string
+		var present bool
+		switch p.v["in"] {
+		case "path":
+			value, present = params[name]
+		case "header":
+			value = header.Get(name)
+			present = value != ""
+		default:
+			continue
+		}
+
+		if !present {
+			if required {
+				return fmt.Errorf("missing required %s parameter %q", p.v["in"], name)
+			}
+			continue
+		}
+		if err = v.validateValue(p.ptr+"/schema", value); err != nil {
+			return fmt.Errorf("invalid %s parameter %q: %w", p.v["in"], name, err)
+		}
+	}
+
+	reqBody, ok := child(op, "requestBody")
+	if !ok {
+		return nil
+	}
+	reqBody = v.resolve(reqBody)
+
+	if len(body) == 0 {
+		if required, _ := reqBody.v["required"].(bool); required {
+			return errors.New("missing required request body")
+		}
+		return nil
+	}
+
+	return v.validateContent(reqBody, header.Get("Content-Type"), body)
+}
+
+// ValidateResponse checks the status code and body of the response given to a request.
+func (v *Validator) ValidateResponse(method, path string, status int, header http.Header, body []byte) error {
+	_, op, _, err := v.operation(method, path)
+	if err != nil {
+		return err
+	}
+
+	responses, _ := child(op, "responses")
+	resp, ok := child(responses, strconv.Itoa(status))
+	if !ok {
+		if resp, ok = child(responses, "default"); !ok {
+			return fmt.Errorf("undocumented status %d for %s %s", status, method, path)
+		}
+	}
+	resp = v.resolve(resp)
+
+	if _, hasContent := resp.v["content"]; !hasContent {
+		if len(body) > 0 {
+			return fmt.Errorf("unexpected body for status %d of %s %s", status, method, path)
+		}
+		return nil
+	}
+
+	return v.validateContent(resp, header.Get("Content-Type"), body)
+}
+
+// operation finds the path item and operation matching the request, along with the path parameter values
+func (v *Validator) operation(method, path string) (node, node, map[string]string, error) {
+	paths, _ := child(node{ptr: "#", v: v.doc}, "paths")
+	for _, r := range v.routes {
+		params, ok := r.match(path)
+		if !ok {
+			continue
+		}
+		item, _ := child(paths, r.template)
+		op, ok := child(item, strings.ToLower(method))
+		if !ok {
+			return node{}, node{}, nil, fmt.Errorf("undocumented operation %s %s", method, r.template)
+		}
+		return item, op, params, nil
+	}
+	return node{}, node{}, nil, fmt.Errorf("undocumented path %s", path)
+}
+
+// parameters merges the path item and operation parameters, with the latter taking precedence
+func (v *Validator) parameters(item, op node) []node {
+	params := make(map[string]node)
+	for _, n := range []node{item, op} {
+		list, _ := n.v["parameters"].([]any)
+		for i := range list {
+			p, ok := child(n, "parameters", strconv.Itoa(i))
+			if !ok {
+				continue
+			}
+			p = v.resolve(p)
+			params[fmt.Sprintf("%s:%s", p.v["in"], p.v["name"])] = p
+		}
+	}
+	return slices.Collect(maps.Values(params))
+}
+
+func (v *Validator) validateContent(n node, contentType string, body []byte) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type %q: %w", contentType, err)
+	}
+
+	media, ok := child(n, "content", mediaType)
+	if !ok {
+		return fmt.Errorf("undocumented content type %q", mediaType)
+	}
+	if _, hasSchema := media.v["schema"]; !hasSchema {
+		return nil
+	}
+
+	switch {
+	case mediaType == jsonContentType || strings.HasSuffix(mediaType, jsonSuffix):
+		return v.validateJSON(media.ptr+"/schema", body)
+	case mediaType == ndjsonContentType:
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			if err = v.validateJSON(media.ptr+"/schema", scanner.Bytes()); err != nil {
+				return err
+			}
+		}
+		return scanner.Err()
+	default:
+		return nil
+	}
+}
+
+func (v *Validator) validateJSON(ptr string, data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return v.validateValue(ptr, value)
+}
+
+func (v *Validator) validateValue(ptr string, value any) error {
+	schema, err := v.compiler.Compile(documentURL + ptr)
+	if err != nil {
+		return err
+	}
+	return schema.Validate(value)
+}
+
+// resolve follows a $ref pointing somewhere else in the document
+func (v *Validator) resolve(n node) node {
+	ref, ok := n.v["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/") {
+		return n
+	}
+
+	current := node{ptr: "#", v: v.doc}
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		if current, ok = child(current, token); !ok {
+			return n
+		}
+	}
+	return v.resolve(current)
+}
+
+// child navigates into nested objects or arrays of a node
+func child(n node, tokens ...string) (node, bool) {
+	var value any = n.v
+	ptr := n.ptr
+	for _, token := range tokens {
+		switch typed := value.(type) {
+		case map[string]any:
+			value = typed[token]
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i >= len(typed) {
+				return node{}, false
+			}
+			value = typed[i]
+		default:
+			return node{}, false
+		}
+		ptr += "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+	}
+
+	m, ok := value.(map[string]any)
+	return node{ptr: ptr, v: m}, ok
+}
+
+// match tells if a request path matches the route template, returning the path parameters
+func (r route) match(path string) (map[string]string, bool) {
+	segments := strings.Split(path, "/")
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, s := range r.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			params[strings.Trim(s, "{}")] = segments[i]
+		} else if s != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
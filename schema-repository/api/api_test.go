@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator_ValidateRequest(t *testing.T) {
+	v, err := NewValidator()
+	require.NoError(t, err)
+
+	jsonHeader := http.Header{"Content-Type": []string{"application/json"}}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		expectError bool
+	}{
+		{name: "create schema", method: http.MethodPost, path: "/schemas/user/1.0.0", body: `{"schema": {"type": "object"}}`},
+		{name: "create schema with short version", method: http.MethodPost, path: "/schemas/user/1", body: `{"schema": true}`},
+		{name: "create schema without body", method: http.MethodPost, path: "/schemas/user/1.0.0", expectError: true},
+		{name: "create schema without schema", method: http.MethodPost, path: "/schemas/user/1.0.0", body: `{}`, expectError: true},
+		{name: "create schema with invalid version", method: http.MethodPost, path: "/schemas/user/invalid", body: `{"schema": true}`, expectError: true},
+		{name: "get history", method: http.MethodGet, path: "/schemas/user/history"},
+		{name: "undocumented operation", method: http.MethodPut, path: "/schemas/user/1.0.0", expectError: true},
+		{name: "undocumented path", method: http.MethodGet, path: "/schemas/user", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateRequest(tt.method, tt.path, jsonHeader, []byte(tt.body))
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateResponse(t *testing.T) {
+	v, err := NewValidator()
+	require.NoError(t, err)
+
+	jsonHeader := http.Header{"Content-Type": []string{"application/json; charset=utf-8"}}
+	ndjsonHeader := http.Header{"Content-Type": []string{"application/x-ndjson"}}
+	problemHeader := http.Header{"Content-Type": []string{"application/problem+json"}}
+	event := `{"id":"1-0","actor":"anonymous","action":"create","name":"user","version":"1.0.0","hash":"sha256:00","timestamp":"2025-01-01T00:00:00Z"}`
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		status      int
+		header      http.Header
+		body        string
+		expectError bool
+	}{
+		{name: "get schema", method: http.MethodGet, path: "/schemas/user/1.0.0", status: http.StatusOK, header: jsonHeader, body: `{"schema": {"type": "object"}}`},
+		{name: "get schema not modified", method: http.MethodGet, path: "/schemas/user/1.0.0", status: http.StatusNotModified, header: http.Header{}},
+		{name: "get schema not found", method: http.MethodGet, path: "/schemas/user/1.0.0", status: http.StatusNotFound, header: problemHeader, body: `{"type": "urn:feijoada:schema-repository:problem:schema_not_found", "title": "The schema doesn't exist", "status": 404, "code": "schema_not_found"}`},
+		{name: "get schema with unknown error code", method: http.MethodGet, path: "/schemas/user/1.0.0", status: http.StatusNotFound, header: problemHeader, body: `{"type": "about:blank", "title": "Not Found", "status": 404, "code": "nope"}`, expectError: true},
+		{name: "create schema conflict", method: http.MethodPost, path: "/schemas/user/1.0.0", status: http.StatusConflict, header: problemHeader, body: `{"type": "urn:feijoada:schema-repository:problem:schema_conflict", "title": "Conflict", "status": 409, "code": "schema_conflict"}`},
+		{name: "get schema with wrong body", method: http.MethodGet, path: "/schemas/user/1.0.0", status: http.StatusOK, header: jsonHeader, body: `{"schema": 42}`, expectError: true},
+		{name: "get avro schema", method: http.MethodGet, path: "/schemas/user/1.0.0", status: http.StatusOK, header: jsonHeader, body: `{"type": "avro", "schema": "string"}`},
+		{name: "get schema with unknown type", method: http.MethodGet, path: "/schemas/user/1.0.0", status: http.StatusOK, header: jsonHeader, body: `{"type": "xml", "schema": {}}`, expectError: true},
+		{name: "create schema", method: http.MethodPost, path: "/schemas/user/1.0.0", status: http.StatusCreated, header: http.Header{}},
+		{name: "create schema with undocumented status", method: http.MethodPost, path: "/schemas/user/1.0.0", status: http.StatusTeapot, header: http.Header{}, expectError: true},
+		{name: "history", method: http.MethodGet, path: "/schemas/user/history", status: http.StatusOK, header: jsonHeader, body: `{"events": [` + event + `]}`},
+		{name: "history with invalid event", method: http.MethodGet, path: "/schemas/user/history", status: http.StatusOK, header: jsonHeader, body: `{"events": [{"id": "1-0"}]}`, expectError: true},
+		{name: "export", method: http.MethodGet, path: "/audit/export", status: http.StatusOK, header: ndjsonHeader, body: event + "\n" + event + "\n"},
+		{name: "export with undocumented content type", method: http.MethodGet, path: "/audit/export", status: http.StatusOK, header: jsonHeader, body: event, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateResponse(tt.method, tt.path, tt.status, tt.header, []byte(tt.body))
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Transport checks every request and response exchanged through it against the OpenAPI document. Requests the
+// document rejects must be rejected by the server as well, and responses must always conform.
+type Transport struct {
+	Validator *Validator
+	// Next sends the requests, http.DefaultTransport when nil
+	Next http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	reqErr := t.Validator.ValidateRequest(req.Method, req.URL.Path, req.Header, reqBody)
+
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if reqErr != nil && (resp.StatusCode < 400 || resp.StatusCode >= 500) {
+		return nil, fmt.Errorf("server accepted a request not conforming to the OpenAPI document (status %d): %w", resp.StatusCode, reqErr)
+	}
+	if reqErr == nil && resp.StatusCode == http.StatusBadRequest {
+		return nil, fmt.Errorf("server rejected a request conforming to the OpenAPI document: %s %s", req.Method, req.URL.Path)
+	}
+	if err = t.Validator.ValidateResponse(req.Method, req.URL.Path, resp.StatusCode, resp.Header, respBody); err != nil {
+		return nil, fmt.Errorf("response not conforming to the OpenAPI document: %w", err)
+	}
+
+	return resp, nil
+}
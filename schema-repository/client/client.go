@@ -0,0 +1,345 @@
+// Package client is a typed Go client for the schema-repository HTTP API, as described by its OpenAPI document
+// (api/openapi.json). It only depends on the standard library, so other modules can use it without pulling the
+// service dependencies.
+//
+// The client is hand-maintained, not generated from the document. TestClient_Spec (spec_test.go) keeps them in sync,
+// running every call against the service and checking the requests and responses exchanged against the document.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	actorHeader = "X-Actor"
+)
+
+// Sentinel errors matching the API error codes. Use errors.Is against errors returned by the Client.
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrInvalidSchema  = errors.New("invalid schema")
+	ErrNotFound       = errors.New("schema not found")
+	ErrConflict       = errors.New("schema version already exists with a different content")
+	ErrLocked         = errors.New("schema version is locked")
+	ErrReadOnly       = errors.New("schema-repository is read-only")
+	ErrUnavailable    = errors.New("schema-repository backend unavailable")
+)
+
+var codeErrors = map[string]error{
+	"invalid_request":     ErrInvalidRequest,
+	"invalid_schema":      ErrInvalidSchema,
+	"schema_not_found":    ErrNotFound,
+	"schema_conflict":     ErrConflict,
+	"schema_locked":       ErrLocked,
+	"read_only":           ErrReadOnly,
+	"backend_unavailable": ErrUnavailable,
+}
+
+var statusErrors = map[int]error{
+	http.StatusBadRequest:          ErrInvalidRequest,
+	http.StatusUnprocessableEntity: ErrInvalidSchema,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusForbidden:           ErrReadOnly,
+	http.StatusConflict:            ErrConflict,
+	http.StatusServiceUnavailable:  ErrUnavailable,
+}
+
+// Error is returned when the API answers with an unexpected status code, holding its RFC 9457 problem details.
+type Error struct {
+	StatusCode int
+	Code       string
+	Title      string
+	Detail     string
+}
+
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("schema-repository responded with status %d: %s", e.StatusCode, e.Title)
+	}
+	return fmt.Sprintf("schema-repository responded with status %d: %s: %s", e.StatusCode, e.Title, e.Detail)
+}
+
+// Is matches the sentinel error of the error code, or of the status code for responses without problem details.
+func (e *Error) Is(target error) bool {
+	if err, ok := codeErrors[e.Code]; ok {
+		return err == target
+	}
+	return statusErrors[e.StatusCode] == target
+}
+
+// AuditEvent is a single entry of the schema change history.
+type AuditEvent struct {
+	ID        string    `json:"id"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Name      string    `json:"name"`
+	Version   string    `json:"version"`
+	Hash      string    `json:"hash"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SchemaVersion identifies a registered schema version.
+type SchemaVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// SyncChange is a single difference found between git and the registry.
+type SyncChange struct {
+	Action       string `json:"action"`
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Path         string `json:"path,omitempty"`
+	GitHash      string `json:"gitHash,omitempty"`
+	RegistryHash string `json:"registryHash,omitempty"`
+	Detail       string `json:"detail,omitempty"`
+}
+
+// SyncReport is the reconciliation of the registry against a git commit.
+type SyncReport struct {
+	Commit    string       `json:"commit"`
+	DryRun    bool         `json:"dryRun"`
+	Changes   []SyncChange `json:"changes"`
+	Unchanged int          `json:"unchanged"`
+}
+
+// Schema types, selecting how records are validated against a schema.
+const (
+	SchemaTypeJSONSchema = "json-schema"
+	SchemaTypeAvro       = "avro"
+	SchemaTypeProtobuf   = "protobuf"
+)
+
+type schemaBody struct {
+	Type   string          `json:"type,omitempty"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+type schemaListResponseBody struct {
+	Schemas []SchemaVersion `json:"schemas"`
+}
+
+type historyResponseBody struct {
+	Events []AuditEvent `json:"events"`
+}
+
+type problem struct {
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+	Code   string `json:"code"`
+}
+
+// Client calls the schema-repository API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	actor      string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces the default HTTP client.
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.httpClient = c
+	}
+}
+
+// WithActor identifies who is performing schema mutations, for the audit log.
+func WithActor(actor string) Option {
+	return func(cl *Client) {
+		cl.actor = actor
+	}
+}
+
+// New creates a Client for the API served at baseURL, for example http://schema-repository:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// SchemaURL returns the URL a schema version is served from, which is also the $id expected for it.
+func (c *Client) SchemaURL(name, version string) string {
+	return fmt.Sprintf("%s/schemas/%s/%s", c.baseURL, url.PathEscape(name), url.PathEscape(version))
+}
+
+// GetSchema retrieves a schema version. Fails with ErrNotFound if it doesn't exist.
+func (c *Client) GetSchema(ctx context.Context, name, version string) (json.RawMessage, error) {
+	var body schemaBody
+	if err := c.do(ctx, http.MethodGet, c.SchemaURL(name, version), nil, http.StatusOK, &body); err != nil {
+		return nil, err
+	}
+	return body.Schema, nil
+}
+
+// GetTypedSchema retrieves a schema version along with its type. Fails with ErrNotFound if it doesn't exist.
+func (c *Client) GetTypedSchema(ctx context.Context, name, version string) (json.RawMessage, string, error) {
+	var body schemaBody
+	if err := c.do(ctx, http.MethodGet, c.SchemaURL(name, version), nil, http.StatusOK, &body); err != nil {
+		return nil, "", err
+	}
+	if body.Type == "" {
+		body.Type = SchemaTypeJSONSchema
+	}
+	return body.Schema, body.Type, nil
+}
+
+// ListSchemas retrieves every registered schema version, sorted by name and version.
+func (c *Client) ListSchemas(ctx context.Context) ([]SchemaVersion, error) {
+	var body schemaListResponseBody
+	if err := c.do(ctx, http.MethodGet, c.baseURL+"/schemas", nil, http.StatusOK, &body); err != nil {
+		return nil, err
+	}
+	return body.Schemas, nil
+}
+
+// CreateSchema stores a schema version. Versions are immutable, so it fails with ErrConflict if the version already
+// exists with a different content.
+func (c *Client) CreateSchema(ctx context.Context, name, version string, schema json.RawMessage) error {
+	return c.do(ctx, http.MethodPost, c.SchemaURL(name, version), schemaBody{Schema: schema}, http.StatusCreated, nil)
+}
+
+// CreateTypedSchema stores a schema version of the given type, such as SchemaTypeAvro. Fails with ErrInvalidSchema if
+// the schema is not valid for its type.
+func (c *Client) CreateTypedSchema(ctx context.Context, name, version, schemaType string, schema json.RawMessage) error {
+	body := schemaBody{Type: schemaType, Schema: schema}
+	return c.do(ctx, http.MethodPost, c.SchemaURL(name, version), body, http.StatusCreated, nil)
+}
+
+// DeleteSchema removes a schema version. Fails with ErrNotFound if it doesn't exist, and with ErrLocked once the
+// version is locked.
+func (c *Client) DeleteSchema(ctx context.Context, name, version string) error {
+	return c.do(ctx, http.MethodDelete, c.SchemaURL(name, version), nil, http.StatusOK, nil)
+}
+
+// History retrieves the change history of all versions of a schema, oldest first.
+func (c *Client) History(ctx context.Context, name string) ([]AuditEvent, error) {
+	var body historyResponseBody
+	u := fmt.Sprintf("%s/schemas/%s/history", c.baseURL, url.PathEscape(name))
+	if err := c.do(ctx, http.MethodGet, u, nil, http.StatusOK, &body); err != nil {
+		return nil, err
+	}
+	return body.Events, nil
+}
+
+// ExportAudit retrieves every audit event recorded, oldest first.
+func (c *Client) ExportAudit(ctx context.Context) ([]AuditEvent, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, c.baseURL+"/audit/export", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	events := make([]AuditEvent, 0)
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var e AuditEvent
+		if err = dec.Decode(&e); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// PlanSync reports what a sync from git would change, without changing anything. Fails with ErrNotFound when the
+// service has no git repository configured.
+func (c *Client) PlanSync(ctx context.Context) (*SyncReport, error) {
+	var report SyncReport
+	if err := c.do(ctx, http.MethodGet, c.baseURL+"/sync/plan", nil, http.StatusOK, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Sync syncs the registry from git right away, reporting what was changed.
+func (c *Client) Sync(ctx context.Context) (*SyncReport, error) {
+	var report SyncReport
+	if err := c.do(ctx, http.MethodPost, c.baseURL+"/sync", nil, http.StatusOK, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (c *Client) do(ctx context.Context, method, u string, in any, expectedStatus int, out any) error {
+	req, err := c.newRequest(ctx, method, u, in)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != expectedStatus {
+		return responseError(resp)
+	}
+
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, u string, in any) (*http.Request, error) {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = strings.NewReader(string(b))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.actor != "" {
+		req.Header.Set(actorHeader, c.actor)
+	}
+	return req, nil
+}
+
+func responseError(resp *http.Response) error {
+	var body problem
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+
+	title := body.Title
+	if title == "" {
+		title = http.StatusText(resp.StatusCode)
+	}
+	return &Error{StatusCode: resp.StatusCode, Code: body.Code, Title: title, Detail: body.Detail}
+}
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/api"
+)
+
+// newTestServer answers every request with the given status and body, after checking the request conforms to the
+// OpenAPI document
+func newTestServer(t *testing.T, status int, contentType, body string) *httptest.Server {
+	validator, err := api.NewValidator()
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqBody, _ := io.ReadAll(r.Body)
+		assert.NoError(t, validator.ValidateRequest(r.Method, r.URL.Path, r.Header, reqBody))
+		assert.Equal(t, "tester", r.Header.Get(actorHeader))
+
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestClient_GetSchema(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("found", func(t *testing.T) {
+		server := newTestServer(t, http.StatusOK, "application/json", `{"schema": {"type": "object"}}`)
+		schema, err := New(server.URL, WithActor("tester")).GetSchema(ctx, "user", "1.0.0")
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"type": "object"}`, string(schema))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server := newTestServer(t, http.StatusNotFound, "application/problem+json", `{"title": "The schema doesn't exist", "status": 404, "code": "schema_not_found"}`)
+		_, err := New(server.URL, WithActor("tester")).GetSchema(ctx, "user", "1.0.0")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NotErrorIs(t, err, ErrUnavailable)
+	})
+
+	t.Run("not found without problem details", func(t *testing.T) {
+		server := newTestServer(t, http.StatusNotFound, "text/plain", `404 page not found`)
+		_, err := New(server.URL, WithActor("tester")).GetSchema(ctx, "user", "1.0.0")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("backend unavailable", func(t *testing.T) {
+		server := newTestServer(t, http.StatusServiceUnavailable, "application/problem+json", `{"title": "The repository backend is unavailable", "status": 503, "detail": "Try again later", "code": "backend_unavailable"}`)
+		_, err := New(server.URL, WithActor("tester")).GetSchema(ctx, "user", "1.0.0")
+		assert.ErrorIs(t, err, ErrUnavailable)
+		var apiErr *Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+		assert.Equal(t, "backend_unavailable", apiErr.Code)
+		assert.Equal(t, "Try again later", apiErr.Detail)
+	})
+}
+
+func TestClient_ListSchemas(t *testing.T) {
+	server := newTestServer(t, http.StatusOK, "application/json", `{"schemas": [{"name": "user", "version": "1.0.0"}, {"name": "order", "version": "2.1.0"}]}`)
+	schemas, err := New(server.URL, WithActor("tester")).ListSchemas(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaVersion{{Name: "user", Version: "1.0.0"}, {Name: "order", Version: "2.1.0"}}, schemas)
+}
+
+func TestClient_CreateSchema(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		server := newTestServer(t, http.StatusCreated, "", "")
+		err := New(server.URL, WithActor("tester")).CreateSchema(context.Background(), "user", "1.0.0", json.RawMessage(`{"type": "object"}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		server := newTestServer(t, http.StatusConflict, "application/problem+json", `{"title": "Conflict", "status": 409, "code": "schema_conflict"}`)
+		err := New(server.URL, WithActor("tester")).CreateSchema(context.Background(), "user", "1.0.0", json.RawMessage(`{"type": "object"}`))
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		server := newTestServer(t, http.StatusForbidden, "application/problem+json", `{"title": "The repository is read-only", "status": 403, "code": "read_only"}`)
+		err := New(server.URL, WithActor("tester")).CreateSchema(context.Background(), "user", "1.0.0", json.RawMessage(`{"type": "object"}`))
+		assert.ErrorIs(t, err, ErrReadOnly)
+	})
+}
+
+func TestClient_TypedSchemas(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("get", func(t *testing.T) {
+		server := newTestServer(t, http.StatusOK, "application/json", `{"type": "avro", "schema": "string"}`)
+		schema, schemaType, err := New(server.URL, WithActor("tester")).GetTypedSchema(ctx, "user", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, SchemaTypeAvro, schemaType)
+		assert.JSONEq(t, `"string"`, string(schema))
+	})
+
+	t.Run("get without type", func(t *testing.T) {
+		server := newTestServer(t, http.StatusOK, "application/json", `{"schema": {"type": "object"}}`)
+		_, schemaType, err := New(server.URL, WithActor("tester")).GetTypedSchema(ctx, "user", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, SchemaTypeJSONSchema, schemaType)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		server := newTestServer(t, http.StatusCreated, "", "")
+		err := New(server.URL, WithActor("tester")).CreateTypedSchema(ctx, "user", "1.0.0", SchemaTypeAvro, json.RawMessage(`{"type": "record", "name": "User", "fields": []}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		server := newTestServer(t, http.StatusUnprocessableEntity, "application/problem+json", `{"title": "The schema is not valid for its type", "status": 422, "code": "invalid_schema"}`)
+		err := New(server.URL, WithActor("tester")).CreateTypedSchema(ctx, "user", "1.0.0", SchemaTypeAvro, json.RawMessage(`"text"`))
+		assert.ErrorIs(t, err, ErrInvalidSchema)
+	})
+}
+
+func TestClient_DeleteSchema(t *testing.T) {
+	server := newTestServer(t, http.StatusOK, "", "")
+	err := New(server.URL, WithActor("tester")).DeleteSchema(context.Background(), "user", "1.0.0")
+	assert.NoError(t, err)
+}
+
+func TestClient_History(t *testing.T) {
+	event := `{"id":"1-0","actor":"tester","action":"create","name":"user","version":"1.0.0","hash":"sha256:00","timestamp":"2025-01-01T00:00:00Z"}`
+
+	t.Run("history", func(t *testing.T) {
+		server := newTestServer(t, http.StatusOK, "application/json", `{"events": [`+event+`]}`)
+		events, err := New(server.URL, WithActor("tester")).History(context.Background(), "user")
+		assert.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "create", events[0].Action)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		server := newTestServer(t, http.StatusOK, "application/x-ndjson", event+"\n"+event+"\n")
+		events, err := New(server.URL, WithActor("tester")).ExportAudit(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, events, 2)
+	})
+}
+
+func TestClient_Sync(t *testing.T) {
+	report := `{"commit": "0123456789abcdef", "dryRun": true, "changes": [{"action": "create", "name": "user", "version": "1.0.0", "path": "schemas/user-1.0.0.json", "gitHash": "sha256:00"}], "unchanged": 2}`
+
+	t.Run("plan", func(t *testing.T) {
+		server := newTestServer(t, http.StatusOK, "application/json", report)
+		r, err := New(server.URL, WithActor("tester")).PlanSync(context.Background())
+		require.NoError(t, err)
+		assert.True(t, r.DryRun)
+		assert.Equal(t, 2, r.Unchanged)
+		require.Len(t, r.Changes, 1)
+		assert.Equal(t, "create", r.Changes[0].Action)
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		server := newTestServer(t, http.StatusOK, "application/json", strings.Replace(report, `"dryRun": true`, `"dryRun": false`, 1))
+		r, err := New(server.URL, WithActor("tester")).Sync(context.Background())
+		require.NoError(t, err)
+		assert.False(t, r.DryRun)
+	})
+}
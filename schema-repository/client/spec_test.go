@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/api"
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/gitsync"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/handlers"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/repository"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/service"
+)
+
+// newAPIServer serves the API over a filesystem repository, syncing from a git repository where an address schema is
+// committed
+func newAPIServer(t *testing.T) *httptest.Server {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	gitDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "address-1.0.0.json"), []byte(`{"type": "object"}`), 0o644))
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "--all"},
+		{"-c", "user.name=tester", "-c", "user.email=tester@example.com", "commit", "--quiet", "--message", "add address"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", gitDir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	data := config.RepoData{KeyPrefix: "schema-repository", KeySeparator: ":"}
+	repo := repository.NewRepository(config.Repository{Filesystem: &config.RepoFilesystem{Path: t.TempDir()}, Data: data})
+	svc := service.NewSchemaService(data, repo, config.Lifecycle{})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handlers.RegisterCustomValidators()
+	handlers.RegisterRoutes(router, handlers.NewHandler(svc, config.Cache{}),
+		handlers.NewSyncHandler(gitsync.NewSyncer(config.Git{Path: gitDir}, svc)))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestClient_Spec runs every call of the client against the service, checking the requests and responses exchanged
+// against the OpenAPI document
+func TestClient_Spec(t *testing.T) {
+	ctx := context.Background()
+	server := newAPIServer(t)
+	validator, err := api.NewValidator()
+	require.NoError(t, err)
+	c := New(server.URL, WithActor("tester"), WithHTTPClient(&http.Client{Transport: &api.Transport{Validator: validator}}))
+
+	report, err := c.PlanSync(ctx)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	require.Len(t, report.Changes, 1)
+	assert.Equal(t, SyncChange{Action: "create", Name: "address", Version: "1.0.0", Path: "address-1.0.0.json",
+		GitHash: report.Changes[0].GitHash}, report.Changes[0])
+	report, err = c.Sync(ctx)
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+
+	require.NoError(t, c.CreateSchema(ctx, "user", "1.0.0", json.RawMessage(`{"type": "object"}`)))
+	require.NoError(t, c.CreateSchema(ctx, "user", "1.0.0", json.RawMessage(`{"type":"object"}`)))
+	assert.ErrorIs(t, c.CreateSchema(ctx, "user", "1.0.0", json.RawMessage(`{"type": "string"}`)), ErrConflict)
+	assert.ErrorIs(t, c.CreateSchema(ctx, "user", "2.0.0", json.RawMessage(`{"type": 42}`)), ErrInvalidSchema)
+	require.NoError(t, c.CreateTypedSchema(ctx, "event", "1.0.0", SchemaTypeAvro, json.RawMessage(`{"type": "record", "name": "Event", "fields": []}`)))
+	assert.ErrorIs(t, c.CreateTypedSchema(ctx, "event", "2.0.0", SchemaTypeAvro, json.RawMessage(`"text"`)), ErrInvalidSchema)
+
+	schema, err := c.GetSchema(ctx, "user", "1.0.0")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "object"}`, string(schema))
+	schema, schemaType, err := c.GetTypedSchema(ctx, "event", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, SchemaTypeAvro, schemaType)
+	assert.JSONEq(t, `{"type": "record", "name": "Event", "fields": []}`, string(schema))
+
+	versions, err := c.ListSchemas(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaVersion{{Name: "address", Version: "1.0.0"}, {Name: "event", Version: "1.0.0"}, {Name: "user", Version: "1.0.0"}}, versions)
+
+	history, err := c.History(ctx, "user")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "tester", history[0].Actor)
+	events, err := c.ExportAudit(ctx)
+	require.NoError(t, err)
+	assert.Len(t, events, 3)
+
+	require.NoError(t, c.DeleteSchema(ctx, "user", "1.0.0"))
+	assert.ErrorIs(t, c.DeleteSchema(ctx, "user", "1.0.0"), ErrNotFound)
+	_, err = c.GetSchema(ctx, "user", "1.0.0")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
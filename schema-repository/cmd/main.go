@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
@@ -11,10 +12,12 @@ import (
 	hcconfig "github.com/tavsec/gin-healthcheck/config"
 
 	"github.com/mfelipe/go-feijoada/schema-repository/config"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/gitsync"
 	"github.com/mfelipe/go-feijoada/schema-repository/internal/handlers"
 	"github.com/mfelipe/go-feijoada/schema-repository/internal/repository"
 	"github.com/mfelipe/go-feijoada/schema-repository/internal/service"
 	utilslog "github.com/mfelipe/go-feijoada/utils/log"
+	"github.com/mfelipe/go-feijoada/utils/vocabulary"
 )
 
 func main() {
@@ -38,24 +41,36 @@ func startServer() chan error {
 		panic(err)
 	}
 
+	// Compile JSON Schemas with the formats and keywords schema-validator knows
+	vocab, err := vocabulary.New(cfg.Vocabulary)
+	if err != nil {
+		panic(err)
+	}
+	service.UseVocabulary(vocab)
+
 	// Create the repository client based on the configuration
 	repo := repository.NewRepository(cfg.Repository)
 
 	// Create the SchemaService
-	schemaSvc := service.NewSchemaService(cfg.Repository.Data, repo)
+	schemaSvc := service.NewSchemaService(cfg.Repository.Data, repo, cfg.Lifecycle)
 
 	// Create the handler instance
-	// The NewHandler function now accepts the service.
-	apiHandler := handlers.NewHandler(schemaSvc)
+	// The NewHandler function now accepts the service and the HTTP cache settings.
+	apiHandler := handlers.NewHandler(schemaSvc, cfg.Cache)
 
 	// Registers custom validation functions for using during request binding
 	handlers.RegisterCustomValidators()
 
+	// Register schemas committed to git, when configured
+	var syncHandler *handlers.SyncHandler
+	if cfg.Git != nil {
+		syncer := gitsync.NewSyncer(*cfg.Git, schemaSvc)
+		syncer.Start(context.Background(), cfg.Git.Interval)
+		syncHandler = handlers.NewSyncHandler(syncer)
+	}
+
 	// Register routes
-	router.Group("/schemas/:name/:version").
-		GET("", apiHandler.GetSchemaHandler).
-		POST("", apiHandler.CreateSchemaHandler).
-		DELETE("", apiHandler.DeleteSchemaHandler)
+	handlers.RegisterRoutes(router, apiHandler, syncHandler)
 
 	// Start the server
 	serverAddr := fmt.Sprintf(":%d", cfg.Port)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/redis"
 	"github.com/testcontainers/testcontainers-go/modules/valkey"
 
+	"github.com/mfelipe/go-feijoada/schema-repository/api"
 	"github.com/mfelipe/go-feijoada/utils/testcontainers"
 )
 
@@ -102,10 +104,17 @@ var (
 	validSchemaV1            = json.RawMessage(`{"type": "object", "properties": {"name": {"type": "string"}}}`)
 	validCompatibleSchemaV12 = json.RawMessage(`{"type": "object", "properties": {"name": {"type": "string"}, "age": {"type": "integer"}}}`)
 	invalidJSONSchema        = json.RawMessage(`{"type": "object", "properties": {"name": {"type": "string"`) // Missing closing brace
+	unprocessableJSONSchema  = json.RawMessage(`{"type": 42}`)                                                // Valid JSON, invalid schema
 )
 
 func TestMain(m *testing.M) {
 	var err error
+	validator, err := api.NewValidator()
+	if err != nil {
+		panic(err)
+	}
+	http.DefaultClient.Transport = &api.Transport{Validator: validator, Next: http.DefaultTransport}
+
 	defer func() {
 		if tc != nil {
 			tc.shutdown()
@@ -151,6 +160,30 @@ func Test_AddSchema(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectError:    true,
 		},
+		{
+			name:           "Unprocessable JSON schema",
+			schemaName:     "user",
+			schemaVersion:  "2.0.0",
+			schemaJSON:     unprocessableJSONSchema,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectError:    true,
+		},
+		{
+			name:           "Same schema v1 again",
+			schemaName:     "user",
+			schemaVersion:  "1.0.0",
+			schemaJSON:     validSchemaV1,
+			expectedStatus: http.StatusCreated,
+			expectError:    false,
+		},
+		{
+			name:           "Different schema for existing v1",
+			schemaName:     "user",
+			schemaVersion:  "1.0.0",
+			schemaJSON:     validCompatibleSchemaV12,
+			expectedStatus: http.StatusConflict,
+			expectError:    true,
+		},
 		{
 			name:           "Invalid schemaVersion format",
 			schemaName:     "user",
@@ -183,7 +216,7 @@ func Test_AddSchema(t *testing.T) {
 					t.Fatalf("Failed to decode response: %v", err)
 				}
 
-				if _, hasError := response["error"]; !hasError {
+				if _, hasError := response["code"]; !hasError {
 					t.Error("Expected error in response, but got none")
 				}
 			} else {
@@ -259,7 +292,7 @@ func Test_GetSchema(t *testing.T) {
 			}
 
 			if tt.expectError {
-				if _, hasError := response["error"]; !hasError {
+				if _, hasError := response["code"]; !hasError {
 					t.Error("Expected error in response, but got none")
 				}
 			} else {
@@ -271,6 +304,68 @@ func Test_GetSchema(t *testing.T) {
 	}
 }
 
+func Test_GetSchemaConditional(t *testing.T) {
+	url := fmt.Sprintf("%s/schemas/%s/%s", baseUrl, "cached", "1.0.0")
+	func() {
+		jsonBody, _ := json.Marshal(map[string]json.RawMessage{"schema": validSchemaV1})
+		resp, err := http.Post(url, "application/json", strings.NewReader(string(jsonBody)))
+		if err != nil || resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Failed to create test schema: %v", err)
+		}
+		closeBody(resp)
+	}()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	closeBody(resp)
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected an ETag header, but got none")
+	}
+	if resp.Header.Get("Cache-Control") == "" {
+		t.Error("Expected a Cache-Control header, but got none")
+	}
+
+	tests := []struct {
+		name           string
+		ifNoneMatch    string
+		expectedStatus int
+	}{
+		{
+			name:           "Matching ETag",
+			ifNoneMatch:    etag,
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "Stale ETag",
+			ifNoneMatch:    `"sha256:stale"`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer closeBody(resp)
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if resp.Header.Get("ETag") != etag {
+				t.Errorf("Expected ETag %s, got %s", etag, resp.Header.Get("ETag"))
+			}
+		})
+	}
+}
+
 func Test_DeleteSchema(t *testing.T) {
 	// First create a schema to test deletion
 	func() {
@@ -339,7 +434,7 @@ func Test_DeleteSchema(t *testing.T) {
 				if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
 					t.Fatalf("Failed to decode response: %v", err)
 				}
-				if _, hasError := response["error"]; !hasError {
+				if _, hasError := response["code"]; !hasError {
 					t.Error("Expected error in response, but got none")
 				}
 			}
@@ -347,6 +442,201 @@ func Test_DeleteSchema(t *testing.T) {
 	}
 }
 
+func Test_SchemaHistory(t *testing.T) {
+	schemaName := "audited"
+	url := fmt.Sprintf("%s/schemas/%s/1.0.0", baseUrl, schemaName)
+
+	// Create and delete a schema version, so both actions are recorded
+	func() {
+		jsonBody, _ := json.Marshal(map[string]json.RawMessage{"schema": validSchemaV1})
+		req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(string(jsonBody)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Actor", "tester")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil || resp.StatusCode != http.StatusCreated {
+			t.Fatalf("Failed to create test schema: %v", err)
+		}
+		closeBody(resp)
+
+		req, _ = http.NewRequest(http.MethodDelete, url, nil)
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("Failed to delete test schema: %v", err)
+		}
+		closeBody(resp)
+	}()
+
+	t.Run("Get schema history", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf("%s/schemas/%s/history", baseUrl, schemaName))
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer closeBody(resp)
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+
+		var response struct {
+			Events []struct {
+				Actor   string `json:"actor"`
+				Action  string `json:"action"`
+				Version string `json:"version"`
+				Hash    string `json:"hash"`
+			} `json:"events"`
+		}
+		if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if len(response.Events) != 2 {
+			t.Fatalf("Expected 2 events, got %d", len(response.Events))
+		}
+		if response.Events[0].Action != "create" || response.Events[0].Actor != "tester" {
+			t.Errorf("Unexpected first event: %+v", response.Events[0])
+		}
+		if response.Events[1].Action != "delete" || response.Events[1].Actor != "anonymous" {
+			t.Errorf("Unexpected second event: %+v", response.Events[1])
+		}
+		if response.Events[0].Hash == "" || response.Events[0].Hash != response.Events[1].Hash {
+			t.Errorf("Expected matching content hashes, got %q and %q", response.Events[0].Hash, response.Events[1].Hash)
+		}
+	})
+
+	t.Run("Export audit log", func(t *testing.T) {
+		resp, err := http.Get(baseUrl + "/audit/export")
+		if err != nil {
+			t.Fatalf("Failed to make request: %v", err)
+		}
+		defer closeBody(resp)
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+
+		dec := json.NewDecoder(resp.Body)
+		var lines int
+		for dec.More() {
+			var event map[string]interface{}
+			if err = dec.Decode(&event); err != nil {
+				t.Fatalf("Failed to decode audit event: %v", err)
+			}
+			lines++
+		}
+		if lines < 2 {
+			t.Errorf("Expected at least 2 exported events, got %d", lines)
+		}
+	})
+}
+
+func Test_ListSchemas(t *testing.T) {
+	url := baseUrl + "/schemas/listed/1.0.0"
+	jsonBody, _ := json.Marshal(map[string]json.RawMessage{"schema": validSchemaV1})
+	resp, err := http.Post(url, "application/json", strings.NewReader(string(jsonBody)))
+	if err != nil || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Failed to create test schema: %v", err)
+	}
+	closeBody(resp)
+
+	resp, err = http.Get(baseUrl + "/schemas")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	var response struct {
+		Schemas []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"schemas"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	var found bool
+	for _, s := range response.Schemas {
+		found = found || (s.Name == "listed" && s.Version == "1.0.0")
+	}
+	if !found {
+		t.Errorf("Expected listed@1.0.0 in %+v", response.Schemas)
+	}
+}
+
+func Test_AvroSchema(t *testing.T) {
+	url := baseUrl + "/schemas/avro-user/1.0.0"
+	avsc := `{"type": "record", "name": "User", "fields": [{"name": "name", "type": "string"}]}`
+
+	resp, err := http.Post(url, "application/json", strings.NewReader(`{"type": "avro", "schema": {"type": "record", "name": "User", "fields": [{"name": "name", "type": "text"}]}}`))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	closeBody(resp)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d for an invalid avro schema, got %d", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+
+	resp, err = http.Post(url, "application/json", strings.NewReader(`{"type": "avro", "schema": `+avsc+`}`))
+	if err != nil || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Failed to create avro schema: %v", err)
+	}
+	closeBody(resp)
+
+	resp, err = http.Get(url)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer closeBody(resp)
+
+	var response struct {
+		Type   string          `json:"type"`
+		Schema json.RawMessage `json:"schema"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Type != "avro" {
+		t.Errorf("Expected type avro, got %q", response.Type)
+	}
+	var got, expected any
+	_ = json.Unmarshal(response.Schema, &got)
+	_ = json.Unmarshal([]byte(avsc), &expected)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected schema %s, got %s", avsc, response.Schema)
+	}
+}
+
+func Test_OpenAPIDocument(t *testing.T) {
+	tests := []struct {
+		path        string
+		contentType string
+	}{
+		{path: "/openapi.json", contentType: "application/json"},
+		{path: "/docs", contentType: "text/html; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := http.Get(baseUrl + tt.path)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer closeBody(resp)
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+			}
+			if resp.Header.Get("Content-Type") != tt.contentType {
+				t.Errorf("Expected content type %s, got %s", tt.contentType, resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
+
 func closeBody(body *http.Response) {
 	if body != nil && body.Body != nil {
 		_ = body.Body.Close()
@@ -11,8 +11,14 @@ import (
 
 type Redis interface {
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
 	Del(ctx context.Context, keys ...string) *redis.IntCmd
 	Get(ctx context.Context, key string) *redis.StringCmd
+	ScanType(ctx context.Context, cursor uint64, match string, count int64, keyType string) *redis.ScanCmd
+	XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd
+	XRange(ctx context.Context, stream, start, stop string) *redis.XMessageSliceCmd
+	Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
 }
 
 func NewRedisClient(cfg config.RepoServer) Redis {
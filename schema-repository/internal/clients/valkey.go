@@ -2,6 +2,7 @@ package clients
 
 import (
 	"context"
+	"time"
 
 	"github.com/valkey-io/valkey-go"
 
@@ -11,14 +12,23 @@ import (
 type Valkey interface {
 	B() valkey.Builder
 	Do(ctx context.Context, cmd valkey.Completed) (resp valkey.ValkeyResult)
+	DoCache(ctx context.Context, cmd valkey.Cacheable, ttl time.Duration) (resp valkey.ValkeyResult)
+	Nodes() map[string]valkey.Client
 }
 
-func NewValkeyClient(cfg config.RepoServer) Valkey {
+func NewValkeyClient(cfg config.RepoServer, cacheCfg *config.RepoCache) Valkey {
 	opts := valkey.MustParseURL(cfg.Address)
 	opts.Username = cfg.Username
 	opts.Password = cfg.Password
 	opts.ClientName = cfg.ClientName
 
+	// Client side caching relies on server assisted invalidation, so no extra invalidation mechanism is needed
+	if cacheCfg == nil {
+		opts.DisableCache = true
+	} else if cacheCfg.MaxBytes > 0 {
+		opts.CacheSizeEachConn = cacheCfg.MaxBytes
+	}
+
 	client, err := valkey.NewClient(opts)
 	if err != nil {
 		panic(err)
@@ -0,0 +1,102 @@
+package gitsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/models"
+)
+
+const schemaFileExt = ".json"
+
+// File is a schema version read from git. Files whose name can't be parsed are kept with a zero version and an Err.
+type File struct {
+	Name    string
+	Version models.Semver
+	Path    string
+	Content json.RawMessage
+	Err     error
+}
+
+// Source reads schema files from the commits of a git repository, either a working copy or a bare repository. Only
+// committed content is read, so uncommitted changes of a working copy are ignored.
+type Source struct {
+	repo string
+	ref  string
+	dir  string
+}
+
+// NewSource creates a Source reading <name>-<version>.json files under dir, at the commit ref points to.
+func NewSource(repo, ref, dir string) *Source {
+	return &Source{repo: repo, ref: ref, dir: strings.Trim(dir, "/")}
+}
+
+// Commit resolves the ref to a commit hash.
+func (s *Source) Commit(ctx context.Context) (string, error) {
+	out, err := s.git(ctx, "rev-parse", "--verify", s.ref+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Files reads every schema file of a commit.
+func (s *Source) Files(ctx context.Context, commit string) ([]File, error) {
+	args := []string{"ls-tree", "-r", "-z", "--name-only", commit}
+	if s.dir != "" {
+		args = append(args, "--", s.dir+"/")
+	}
+	out, err := s.git(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]File, 0)
+	for _, p := range strings.Split(string(out), "\x00") {
+		if !strings.HasSuffix(p, schemaFileExt) {
+			continue
+		}
+
+		f := File{Path: p}
+		f.Name, f.Version, f.Err = parseFileName(path.Base(p))
+		if f.Err == nil {
+			f.Content, f.Err = s.git(ctx, "cat-file", "blob", commit+":"+p)
+		}
+		files = append(files, f)
+	}
+
+	return files, nil
+}
+
+func (s *Source) git(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", s.repo}, args...)...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// parseFileName splits a <name>-<version>.json file name. Versions have no dashes, so the last one is the separator
+func parseFileName(file string) (string, models.Semver, error) {
+	base := strings.TrimSuffix(file, schemaFileExt)
+
+	i := strings.LastIndex(base, "-")
+	if i <= 0 {
+		return "", models.Semver{}, fmt.Errorf("file name %s doesn't follow the <name>-<version>.json format", file)
+	}
+
+	var version models.Semver
+	if err := version.UnmarshalParam(base[i+1:]); err != nil {
+		return "", models.Semver{}, fmt.Errorf("file name %s has an invalid version: %w", file, err)
+	}
+	return base[:i], version, nil
+}
@@ -0,0 +1,231 @@
+// Package gitsync keeps the registry in sync with the schemas committed to a git repository.
+package gitsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	zlog "github.com/rs/zerolog/log"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/models"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/service"
+)
+
+const (
+	defaultRef  = "HEAD"
+	actorPrefix = "git:"
+	shortHash   = 12
+)
+
+// Syncer registers the schema versions committed to git. Versions are immutable, so content differences are only
+// reported, and versions missing from git are only deleted when pruning is enabled.
+type Syncer struct {
+	src   *Source
+	svc   *service.SchemaService
+	prune bool
+	// mu serializes syncs, so the same version is never planned twice
+	mu         sync.Mutex
+	lastCommit string
+}
+
+// NewSyncer creates a Syncer for the configured repository.
+func NewSyncer(cfg config.Git, svc *service.SchemaService) *Syncer {
+	ref := cfg.Ref
+	if ref == "" {
+		ref = defaultRef
+	}
+
+	return &Syncer{
+		src:   NewSource(cfg.Path, ref, cfg.Dir),
+		svc:   svc,
+		prune: cfg.Prune,
+	}
+}
+
+// Plan reports what a sync would change, without changing anything.
+func (s *Syncer) Plan(ctx context.Context) (*models.SyncReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commit, err := s.src.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report, _, err := s.plan(ctx, commit)
+	return report, err
+}
+
+// Sync registers the versions missing from the registry, and deletes the ones missing from git if pruning.
+// Changes that fail to apply are kept in the report, with the failure as detail.
+func (s *Syncer) Sync(ctx context.Context) (*models.SyncReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commit, err := s.src.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.sync(ctx, commit)
+}
+
+// Start syncs right away, then every interval if the ref points to a new commit, until the context is done.
+func (s *Syncer) Start(ctx context.Context, interval time.Duration) {
+	s.poll(ctx)
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.poll(ctx)
+			}
+		}
+	}()
+}
+
+func (s *Syncer) poll(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commit, err := s.src.Commit(ctx)
+	if err != nil {
+		zlog.Err(err).Msg("failed to resolve the git ref to sync")
+		return
+	}
+	if commit == s.lastCommit {
+		return
+	}
+
+	report, err := s.sync(ctx, commit)
+	if err != nil {
+		zlog.Err(err).Str("commit", commit).Msg("failed to sync schemas from git")
+		return
+	}
+
+	failed := false
+	for _, c := range report.Changes {
+		zlog.Info().Str("commit", commit).Str("action", string(c.Action)).Str("schema", c.Name).
+			Str("version", c.Version).Str("detail", c.Detail).Msg("git sync")
+		failed = failed || (applicable(c.Action) && c.Detail != "")
+	}
+
+	// The commit is synced again on the next poll if any change failed to apply
+	if !failed {
+		s.lastCommit = commit
+	}
+}
+
+func (s *Syncer) sync(ctx context.Context, commit string) (*models.SyncReport, error) {
+	report, contents, err := s.plan(ctx, commit)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = false
+
+	actorCtx := service.WithActor(ctx, actorPrefix+commit[:min(shortHash, len(commit))])
+	for i, c := range report.Changes {
+		var version models.Semver
+		_ = version.UnmarshalParam(c.Version)
+
+		switch c.Action {
+		case models.SyncActionCreate:
+			err = s.svc.AddSchema(actorCtx, c.Name, version, models.SchemaTypeJSONSchema, contents[c.Path])
+		case models.SyncActionDelete:
+			err = s.svc.DeleteSchema(actorCtx, c.Name, version)
+		default:
+			continue
+		}
+
+		if err != nil {
+			report.Changes[i].Detail = err.Error()
+		}
+	}
+
+	return report, nil
+}
+
+// plan compares the commit with the registry, also returning the content of the files to create, by path
+func (s *Syncer) plan(ctx context.Context, commit string) (*models.SyncReport, map[string]json.RawMessage, error) {
+	files, err := s.src.Files(ctx, commit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Every version stored in the repository is listed, including the ones that didn't go through the service
+	registered, err := s.svc.Versions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := &models.SyncReport{Commit: commit, DryRun: true, Changes: make([]models.SyncChange, 0)}
+	contents := make(map[string]json.RawMessage)
+	inGit := make(map[service.SchemaVersion]bool, len(files))
+
+	for _, f := range files {
+		change := models.SyncChange{Name: f.Name, Path: f.Path}
+		if f.Name != "" {
+			change.Version = f.Version.String()
+			// An invalid file still holds its version, which mustn't be reported missing or pruned
+			inGit[service.SchemaVersion{Name: f.Name, Version: f.Version}] = true
+		}
+		if f.Err == nil {
+			f.Err = service.ValidateJSONSchema(f.Content)
+		}
+		if f.Err != nil {
+			change.Action = models.SyncActionInvalid
+			change.Detail = f.Err.Error()
+			report.Changes = append(report.Changes, change)
+			continue
+		}
+
+		change.GitHash = service.ContentHash(f.Content)
+
+		existing, _, err := s.svc.GetSchema(ctx, f.Name, f.Version)
+		switch {
+		case errors.Is(err, service.ErrSchemaNotFound):
+			change.Action = models.SyncActionCreate
+			contents[f.Path] = f.Content
+		case err != nil:
+			return nil, nil, err
+		case service.SameContent(existing, f.Content):
+			report.Unchanged++
+			continue
+		default:
+			change.Action = models.SyncActionConflict
+			change.RegistryHash = service.ContentHash(existing)
+		}
+		report.Changes = append(report.Changes, change)
+	}
+
+	for _, v := range registered {
+		if inGit[v] {
+			continue
+		}
+
+		change := models.SyncChange{Action: models.SyncActionMissing, Name: v.Name, Version: v.Version.String()}
+		if s.prune {
+			change.Action = models.SyncActionDelete
+		}
+		if existing, _, err := s.svc.GetSchema(ctx, v.Name, v.Version); err == nil {
+			change.RegistryHash = service.ContentHash(existing)
+		}
+		report.Changes = append(report.Changes, change)
+	}
+
+	return report, contents, nil
+}
+
+// applicable tells if a sync action changes the registry, as opposed to only being reported
+func applicable(action models.SyncAction) bool {
+	return action == models.SyncActionCreate || action == models.SyncActionDelete
+}
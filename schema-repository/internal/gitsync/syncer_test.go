@@ -0,0 +1,217 @@
+package gitsync
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/models"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/repository"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/service"
+)
+
+const schemasDir = "schemas/schemas"
+
+// testRepo is a git working copy where schema files are committed
+type testRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	r := &testRepo{t: t, dir: t.TempDir()}
+	r.git("init", "--quiet", "--initial-branch=main")
+	require.NoError(t, os.MkdirAll(filepath.Join(r.dir, schemasDir), 0o755))
+	return r
+}
+
+func (r *testRepo) git(args ...string) string {
+	args = append([]string{"-C", r.dir, "-c", "user.name=tester", "-c", "user.email=tester@example.com"}, args...)
+	out, err := exec.Command("git", args...).CombinedOutput()
+	require.NoError(r.t, err, string(out))
+	return string(out)
+}
+
+func (r *testRepo) commit(files map[string]string) string {
+	for name, content := range files {
+		path := filepath.Join(r.dir, schemasDir, name)
+		if content == "" {
+			require.NoError(r.t, os.Remove(path))
+			continue
+		}
+		require.NoError(r.t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	r.git("add", "--all")
+	r.git("commit", "--quiet", "--message", "update schemas")
+	return r.git("rev-parse", "HEAD")[:40]
+}
+
+// newTestService creates a service over a filesystem repository, also returning its directory
+func newTestService(t *testing.T) (*service.SchemaService, string) {
+	dir := t.TempDir()
+	data := config.RepoData{KeyPrefix: "schema-repository", KeySeparator: ":"}
+	repo := repository.NewRepository(config.Repository{Filesystem: &config.RepoFilesystem{Path: dir}, Data: data})
+	return service.NewSchemaService(data, repo, config.Lifecycle{}), dir
+}
+
+func actions(report *models.SyncReport) map[string]models.SyncAction {
+	a := make(map[string]models.SyncAction)
+	for _, c := range report.Changes {
+		a[c.Name+"@"+c.Version] = c.Action
+	}
+	return a
+}
+
+func TestSyncer(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	svc, storeDir := newTestService(t)
+	syncer := NewSyncer(config.Git{Path: repo.dir, Dir: schemasDir}, svc)
+
+	commit := repo.commit(map[string]string{
+		"user-1.0.0.json":   `{"type": "object"}`,
+		"order-2.0.0.json":  `{"type": "object", "properties": {"id": {"type": "string"}}}`,
+		"notes.json":        `{}`,
+		"broken-1.0.0.json": `{"type": 42}`,
+	})
+
+	// Planning changes nothing
+	report, err := syncer.Plan(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, commit, report.Commit)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, map[string]models.SyncAction{
+		"user@1.0.0":   models.SyncActionCreate,
+		"order@2.0.0":  models.SyncActionCreate,
+		"@":            models.SyncActionInvalid,
+		"broken@1.0.0": models.SyncActionInvalid,
+	}, actions(report))
+	_, _, err = svc.GetSchema(ctx, "user", models.Semver{Major: 1})
+	assert.ErrorIs(t, err, service.ErrSchemaNotFound)
+
+	// Syncing registers the valid versions, on behalf of the commit
+	report, err = syncer.Sync(ctx)
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	schema, _, err := svc.GetSchema(ctx, "user", models.Semver{Major: 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "object"}`, string(schema))
+	history, err := svc.History(ctx, "user")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "git:"+commit[:12], history[0].Actor)
+
+	// Versions registered through the API, or stored in the backend directly, are missing from git, and a changed
+	// file conflicts with the registry
+	require.NoError(t, svc.AddSchema(ctx, "user", models.Semver{Major: 2}, models.SchemaTypeJSONSchema, []byte(`{"type": "object"}`)))
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "address-1.0.0.json"), []byte(`{"type": "object"}`), 0o644))
+	repo.commit(map[string]string{
+		"user-1.0.0.json":   `{"type": "string"}`,
+		"notes.json":        "",
+		"broken-1.0.0.json": "",
+	})
+
+	report, err = syncer.Plan(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Unchanged)
+	assert.Equal(t, map[string]models.SyncAction{
+		"user@1.0.0":    models.SyncActionConflict,
+		"user@2.0.0":    models.SyncActionMissing,
+		"address@1.0.0": models.SyncActionMissing,
+	}, actions(report))
+	for _, c := range report.Changes {
+		assert.NotEmpty(t, c.RegistryHash)
+	}
+
+	// Conflicts are never applied, versions are immutable
+	_, err = syncer.Sync(ctx)
+	require.NoError(t, err)
+	schema, _, err = svc.GetSchema(ctx, "user", models.Semver{Major: 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "object"}`, string(schema))
+
+	// Pruning deletes the versions missing from git
+	pruning := NewSyncer(config.Git{Path: repo.dir, Dir: schemasDir, Prune: true}, svc)
+	report, err = pruning.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.SyncActionDelete, actions(report)["user@2.0.0"])
+	_, _, err = svc.GetSchema(ctx, "user", models.Semver{Major: 2})
+	assert.ErrorIs(t, err, service.ErrSchemaNotFound)
+}
+
+func TestSyncerInvalidFile(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	svc, _ := newTestService(t)
+	syncer := NewSyncer(config.Git{Path: repo.dir, Dir: schemasDir, Prune: true}, svc)
+
+	repo.commit(map[string]string{"order-2.0.0.json": `{"type": "object"}`})
+	_, err := syncer.Sync(ctx)
+	require.NoError(t, err)
+
+	// A broken edit of a registered version is reported, and the version is neither missing nor pruned
+	repo.commit(map[string]string{"order-2.0.0.json": `{"type": 42}`})
+	report, err := syncer.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]models.SyncAction{"order@2.0.0": models.SyncActionInvalid}, actions(report))
+	schema, _, err := svc.GetSchema(ctx, "order", models.Semver{Major: 2})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "object"}`, string(schema))
+}
+
+func TestSyncerBareRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+	repo.commit(map[string]string{"user-1.0.0.json": `{"type": "object"}`})
+
+	bare := filepath.Join(t.TempDir(), "schemas.git")
+	repo.git("clone", "--quiet", "--bare", repo.dir, bare)
+
+	svc, _ := newTestService(t)
+	syncer := NewSyncer(config.Git{Path: bare, Ref: "main", Dir: schemasDir}, svc)
+	syncer.Start(ctx, 0)
+
+	_, _, err := svc.GetSchema(ctx, "user", models.Semver{Major: 1})
+	assert.NoError(t, err)
+
+	_, err = NewSyncer(config.Git{Path: bare, Ref: "missing"}, svc).Plan(ctx)
+	assert.Error(t, err)
+}
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		file        string
+		name        string
+		version     models.Semver
+		expectError bool
+	}{
+		{file: "user-1.0.0.json", name: "user", version: models.Semver{Major: 1}},
+		{file: "shipping-address-2.1.json", name: "shipping-address", version: models.Semver{Major: 2, Minor: 1}},
+		{file: "user.json", expectError: true},
+		{file: "-1.0.0.json", expectError: true},
+		{file: "user-latest.json", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			name, version, err := parseFileName(tt.file)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.version, version)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+)
+
+const (
+	immutableMaxAge = 365 * 24 * 60 * 60
+	wildcardETag    = "*"
+	weakETagPrefix  = "W/"
+)
+
+// strongETag builds a strong entity tag from a schema content hash.
+func strongETag(hash string) string {
+	return `"` + hash + `"`
+}
+
+// etagMatches tells if any of the entity tags listed in an If-None-Match header matches the given one.
+// If-None-Match uses the weak comparison function, so weak validators match their strong counterparts.
+// https://www.rfc-editor.org/rfc/rfc9110#name-if-none-match
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == wildcardETag || strings.TrimPrefix(candidate, weakETagPrefix) == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheControl builds the Cache-Control header value for a retrieved schema. Locked versions never change, so they can
+// be cached forever, while the other ones may still be deleted and created again with another content.
+func cacheControl(cfg config.Cache, locked bool) string {
+	switch {
+	case locked:
+		return fmt.Sprintf("public, max-age=%d, immutable", immutableMaxAge)
+	case cfg.MaxAge > 0:
+		return fmt.Sprintf("public, max-age=%d, must-revalidate", int(cfg.MaxAge.Seconds()))
+	default:
+		return "no-cache"
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+)
+
+func TestEtagMatches(t *testing.T) {
+	etag := strongETag("sha256:abc")
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		expected    bool
+	}{
+		{name: "same tag", ifNoneMatch: `"sha256:abc"`, expected: true},
+		{name: "weak tag", ifNoneMatch: `W/"sha256:abc"`, expected: true},
+		{name: "wildcard", ifNoneMatch: `*`, expected: true},
+		{name: "listed tag", ifNoneMatch: `"sha256:def", "sha256:abc"`, expected: true},
+		{name: "different tag", ifNoneMatch: `"sha256:def"`, expected: false},
+		{name: "unquoted tag", ifNoneMatch: `sha256:abc`, expected: false},
+		{name: "empty header", ifNoneMatch: ``, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, etagMatches(tt.ifNoneMatch, etag))
+		})
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	assert.Equal(t, "public, max-age=31536000, immutable", cacheControl(config.Cache{MaxAge: time.Minute}, true))
+	assert.Equal(t, "public, max-age=31536000, immutable", cacheControl(config.Cache{}, true))
+	assert.Equal(t, "public, max-age=60, must-revalidate", cacheControl(config.Cache{MaxAge: time.Minute}, false))
+	assert.Equal(t, "no-cache", cacheControl(config.Cache{}, false))
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/api"
+)
+
+// swaggerUIPage renders the OpenAPI document with Swagger UI, loaded from a CDN to keep the binary small.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8"/>
+  <title>Schema Repository API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css"/>
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+<script>
+  window.onload = () => {
+    window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+  };
+</script>
+</body>
+</html>
+`
+
+// OpenAPIHandler serves the OpenAPI document of this API.
+func (h *Handler) OpenAPIHandler(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/json", api.Spec)
+}
+
+// SwaggerUIHandler serves a Swagger UI page for the OpenAPI document.
+func (h *Handler) SwaggerUIHandler(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
@@ -6,9 +6,11 @@ import (
 	"github.com/mfelipe/go-feijoada/schema-repository/internal/models"
 )
 
-// SchemaBody defines the request body for creating a new schema.
+// SchemaBody defines the request body for creating a new schema. The schema is checked against its type, JSON Schema
+// when absent.
 type SchemaBody struct {
-	Schema json.RawMessage `json:"schema" binding:"required,json_schema"`
+	Type   models.SchemaType `json:"type,omitempty" binding:"omitempty,oneof=json-schema avro protobuf"`
+	Schema json.RawMessage   `json:"schema" binding:"required"`
 }
 
 // SchemaRequestURI defines the response body for retrieving or creating a schema.
@@ -17,12 +19,41 @@ type SchemaRequestURI struct {
 	Version models.Semver `json:"version" uri:"version" binding:"required"`
 }
 
+// SchemaNameURI defines the request URI for operations over all versions of a schema.
+type SchemaNameURI struct {
+	Name string `json:"name" uri:"name" binding:"required"`
+}
+
 // SchemaResponseBody defines the response body for retrieving or creating a schema.
 type SchemaResponseBody struct {
-	Schema json.RawMessage `json:"schema"`
+	Type   models.SchemaType `json:"type"`
+	Schema json.RawMessage   `json:"schema"`
+}
+
+// HistoryResponseBody defines the response body for retrieving the change history of a schema.
+type HistoryResponseBody struct {
+	Events []models.AuditEvent `json:"events"`
+}
+
+// SchemaVersionBody identifies a registered schema version.
+type SchemaVersionBody struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// SchemaListResponseBody defines the response body for listing the registered schema versions.
+type SchemaListResponseBody struct {
+	Schemas []SchemaVersionBody `json:"schemas"`
 }
 
-// ErrorResponse defines the structure for error messages.
-type ErrorResponse struct {
-	Error string `json:"error"`
+// Problem defines the structure for error messages, following RFC 9457 problem details.
+// https://www.rfc-editor.org/rfc/rfc9457
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code is a stable, machine-readable identifier of the error
+	Code string `json:"code"`
 }
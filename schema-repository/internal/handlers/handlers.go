@@ -1,23 +1,33 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	zlog "github.com/rs/zerolog/log"
 
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
 	"github.com/mfelipe/go-feijoada/schema-repository/internal/service"
 )
 
+const (
+	// ActorHeader identifies who is performing a schema mutation, for auditing purposes.
+	ActorHeader = "X-Actor"
+
+	ndjsonContentType = "application/x-ndjson"
+)
+
 // Handler struct holds dependencies, like the schema service.
 type Handler struct {
 	SchemaSvc *service.SchemaService
+	cacheCfg  config.Cache
 }
 
 // NewHandler creates a new Handler instance.
-func NewHandler(svc *service.SchemaService) *Handler {
-	return &Handler{SchemaSvc: svc}
+func NewHandler(svc *service.SchemaService, cacheCfg config.Cache) *Handler {
+	return &Handler{SchemaSvc: svc, cacheCfg: cacheCfg}
 }
 
 // CreateSchemaHandler handles the creation of a new schema.
@@ -25,20 +35,19 @@ func (h *Handler) CreateSchemaHandler(ctx *gin.Context) {
 	var reqURI SchemaRequestURI
 	if err := ctx.ShouldBindUri(&reqURI); err != nil {
 		zlog.Warn().Msg("failed to bind request URI")
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		abortWithBindingError(ctx, err)
 		return
 	}
 
 	var req SchemaBody
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		zlog.Warn().Msg("failed to bind request body")
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		abortWithBindingError(ctx, err)
 		return
 	}
 
-	if err := h.SchemaSvc.AddSchema(ctx, reqURI.Name, reqURI.Version, req.Schema); err != nil {
-		zlog.Err(err).Msg("internal server error")
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Error: "An unexpected error occurred while persisting the schema"})
+	if err := h.SchemaSvc.AddSchema(service.WithActor(ctx, ctx.GetHeader(ActorHeader)), reqURI.Name, reqURI.Version, req.Type, req.Schema); err != nil {
+		abortWithServiceError(ctx, err, "persist")
 		return
 	}
 
@@ -50,49 +59,110 @@ func (h *Handler) GetSchemaHandler(ctx *gin.Context) {
 	var reqURI SchemaRequestURI
 	if err := ctx.ShouldBindUri(&reqURI); err != nil {
 		zlog.Warn().Msg("failed to bind request URI")
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		abortWithBindingError(ctx, err)
 		return
 	}
 
-	schema, err := h.SchemaSvc.GetSchema(ctx, reqURI.Name, reqURI.Version)
+	schema, schemaType, err := h.SchemaSvc.GetSchema(ctx, reqURI.Name, reqURI.Version)
 	if err != nil {
-		errStr := err.Error()
-		if errStr == service.ErrorSchemaNotFound {
-			zlog.Warn().Str("schema", reqURI.Name).Str("version", reqURI.Version.String()).Msg("schema not found")
-			ctx.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Error: errStr})
-		} else {
-			zlog.Err(err).Msg("internal server error")
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Error: "An unexpected error occurred while retrieving the schema"})
-		}
+		abortWithServiceError(ctx, err, "retrieve")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, SchemaBody{
+	// Schemas are served with validators, so clients can skip downloading the ones they already have. Failing to read
+	// the lifecycle of the version only shortens how long it is cached
+	locked, err := h.SchemaSvc.Locked(ctx, reqURI.Name, reqURI.Version)
+	if err != nil {
+		zlog.Warn().Err(err).Str("schema", reqURI.Name).Str("version", reqURI.Version.String()).Msg("failed to check whether schema is locked")
+	}
+	etag := strongETag(service.ContentHash(schema))
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", cacheControl(h.cacheCfg, locked))
+
+	if etagMatches(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, SchemaResponseBody{
+		Type:   schemaType,
 		Schema: schema,
 	})
 }
 
+// ListSchemasHandler handles the listing of every registered schema version.
+func (h *Handler) ListSchemasHandler(ctx *gin.Context) {
+	versions, err := h.SchemaSvc.Versions(ctx)
+	if err != nil {
+		abortWithServiceError(ctx, err, "list the versions of")
+		return
+	}
+
+	schemas := make([]SchemaVersionBody, 0, len(versions))
+	for _, v := range versions {
+		schemas = append(schemas, SchemaVersionBody{Name: v.Name, Version: v.Version.String()})
+	}
+
+	ctx.JSON(http.StatusOK, SchemaListResponseBody{
+		Schemas: schemas,
+	})
+}
+
 // DeleteSchemaHandler handles the retrieval of a schema.
 func (h *Handler) DeleteSchemaHandler(ctx *gin.Context) {
 	var reqURI SchemaRequestURI
 	if err := ctx.ShouldBindUri(&reqURI); err != nil {
 		zlog.Warn().Msg("failed to bind request URI")
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		abortWithBindingError(ctx, err)
 		return
 	}
 
-	err := h.SchemaSvc.DeleteSchema(ctx, reqURI.Name, reqURI.Version)
+	err := h.SchemaSvc.DeleteSchema(service.WithActor(ctx, ctx.GetHeader(ActorHeader)), reqURI.Name, reqURI.Version)
 	if err != nil {
-		errStr := err.Error()
-		if errStr == service.ErrorSchemaNotFound {
-			zlog.Warn().Str("schema", reqURI.Name).Str("version", reqURI.Version.String()).Msg("schema not found")
-			ctx.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Error: errStr})
-		} else {
-			zlog.Err(err).Msg("internal server error")
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Error: "An unexpected error occurred while deleting the schema"})
-		}
+		abortWithServiceError(ctx, err, "delete")
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// HistoryHandler handles the retrieval of the change history of a schema.
+func (h *Handler) HistoryHandler(ctx *gin.Context) {
+	var reqURI SchemaNameURI
+	if err := ctx.ShouldBindUri(&reqURI); err != nil {
+		zlog.Warn().Msg("failed to bind request URI")
+		abortWithBindingError(ctx, err)
+		return
+	}
+
+	events, err := h.SchemaSvc.History(ctx, reqURI.Name)
+	if err != nil {
+		abortWithServiceError(ctx, err, "retrieve the history of")
 		return
 	}
 
+	ctx.JSON(http.StatusOK, HistoryResponseBody{
+		Events: events,
+	})
+}
+
+// ExportAuditHandler handles the export of the whole audit log as newline delimited JSON.
+func (h *Handler) ExportAuditHandler(ctx *gin.Context) {
+	events, err := h.SchemaSvc.AuditLog(ctx)
+	if err != nil {
+		abortWithServiceError(ctx, err, "export the audit log of")
+		return
+	}
+
+	ctx.Header("Content-Type", ndjsonContentType)
+	ctx.Header("Content-Disposition", `attachment; filename="schema-audit.ndjson"`)
 	ctx.Status(http.StatusOK)
+
+	enc := json.NewEncoder(ctx.Writer)
+	for _, e := range events {
+		if err = enc.Encode(e); err != nil {
+			zlog.Err(err).Msg("failed to write audit event")
+			return
+		}
+	}
 }
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/go-playground/validator/v10"
+	zlog "github.com/rs/zerolog/log"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/repository"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/service"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	problemTypePrefix  = "urn:feijoada:schema-repository:problem:"
+)
+
+// Stable error codes, part of the API contract. Clients should rely on them instead of titles or details.
+const (
+	CodeInvalidRequest     = "invalid_request"
+	CodeInvalidSchema      = "invalid_schema"
+	CodeSchemaNotFound     = "schema_not_found"
+	CodeSchemaConflict     = "schema_conflict"
+	CodeSchemaLocked       = "schema_locked"
+	CodeReadOnly           = "read_only"
+	CodeBackendUnavailable = "backend_unavailable"
+	CodeInternalError      = "internal_error"
+)
+
+var problemTitles = map[string]string{
+	CodeInvalidRequest:     "The request is not valid",
+	CodeInvalidSchema:      "The schema is not valid for its type",
+	CodeSchemaNotFound:     "The schema doesn't exist",
+	CodeSchemaConflict:     "The schema version already exists with a different content",
+	CodeSchemaLocked:       "The schema version is locked and can't be deleted",
+	CodeReadOnly:           "The repository is read-only",
+	CodeBackendUnavailable: "The repository backend is unavailable",
+	CodeInternalError:      "An unexpected error occurred",
+}
+
+// newProblem builds a problem details object for one of the stable error codes.
+func newProblem(status int, code, detail string) Problem {
+	return Problem{
+		Type:   problemTypePrefix + code,
+		Title:  problemTitles[code],
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// abortWithProblem writes a RFC 9457 problem details response and stops the handler chain.
+func abortWithProblem(ctx *gin.Context, p Problem) {
+	p.Instance = ctx.Request.URL.Path
+	ctx.Header("Content-Type", problemContentType)
+	ctx.Abort()
+	ctx.Render(p.Status, render.JSON{Data: p})
+}
+
+// abortWithBindingError reports request binding failures. Schemas not valid for their type are reported as
+// unprocessable, as the request itself is well-formed.
+func abortWithBindingError(ctx *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		for _, fe := range validationErrs {
+			if fe.Tag() == validSchemaTag {
+				abortWithProblem(ctx, newProblem(http.StatusUnprocessableEntity, CodeInvalidSchema, err.Error()))
+				return
+			}
+		}
+	}
+	abortWithProblem(ctx, newProblem(http.StatusBadRequest, CodeInvalidRequest, err.Error()))
+}
+
+// abortWithServiceError maps errors from the service layer to their problem details. Unexpected errors are logged,
+// and their details are not disclosed.
+func abortWithServiceError(ctx *gin.Context, err error, action string) {
+	var schemaErr *service.SchemaError
+	if errors.As(err, &schemaErr) {
+		zlog.Warn().Err(err).Str("schema", schemaErr.Name).Str("version", schemaErr.Version.String()).Msgf("failed to %s schema", action)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrSchemaNotFound):
+		abortWithProblem(ctx, newProblem(http.StatusNotFound, CodeSchemaNotFound, err.Error()))
+	case errors.Is(err, service.ErrSchemaConflict):
+		abortWithProblem(ctx, newProblem(http.StatusConflict, CodeSchemaConflict, err.Error()))
+	case errors.Is(err, service.ErrSchemaLocked):
+		abortWithProblem(ctx, newProblem(http.StatusConflict, CodeSchemaLocked, err.Error()))
+	case errors.Is(err, service.ErrInvalidJSONSchema), errors.Is(err, service.ErrInvalidSchema):
+		abortWithProblem(ctx, newProblem(http.StatusUnprocessableEntity, CodeInvalidSchema, err.Error()))
+	case errors.Is(err, repository.ErrReadOnly):
+		abortWithProblem(ctx, newProblem(http.StatusForbidden, CodeReadOnly, "Schemas can't be changed through this instance"))
+	case errors.Is(err, repository.ErrUnavailable):
+		zlog.Err(err).Msg("repository unavailable")
+		abortWithProblem(ctx, newProblem(http.StatusServiceUnavailable, CodeBackendUnavailable, "Try again later"))
+	default:
+		zlog.Err(err).Msg("internal server error")
+		abortWithProblem(ctx, newProblem(http.StatusInternalServerError, CodeInternalError, "An unexpected error occurred while trying to "+action+" the schema"))
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/models"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/repository"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/service"
+)
+
+func TestAbortWithServiceError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "schema not found",
+			err:            &service.SchemaError{Name: "user", Version: models.Semver{Major: 1}, Err: service.ErrSchemaNotFound},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   CodeSchemaNotFound,
+		},
+		{
+			name:           "schema conflict",
+			err:            &service.SchemaError{Name: "user", Version: models.Semver{Major: 1}, Err: service.ErrSchemaConflict},
+			expectedStatus: http.StatusConflict,
+			expectedCode:   CodeSchemaConflict,
+		},
+		{
+			name:           "invalid schema",
+			err:            service.ErrInvalidJSONSchema,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   CodeInvalidSchema,
+		},
+		{
+			name:           "invalid avro schema",
+			err:            fmt.Errorf("%w: avro: unknown type name", service.ErrInvalidSchema),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   CodeInvalidSchema,
+		},
+		{
+			name:           "read-only repository",
+			err:            repository.ErrReadOnly,
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   CodeReadOnly,
+		},
+		{
+			name:           "backend unavailable",
+			err:            fmt.Errorf("%w: %w", repository.ErrUnavailable, errors.New("dial tcp: connection refused")),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   CodeBackendUnavailable,
+		},
+		{
+			name:           "unexpected error",
+			err:            errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   CodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/schemas/user/1.0.0", nil)
+
+			abortWithServiceError(ctx, tt.err, "retrieve")
+
+			assert.True(t, ctx.IsAborted())
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+
+			var p Problem
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+			assert.Equal(t, tt.expectedStatus, p.Status)
+			assert.Equal(t, tt.expectedCode, p.Code)
+			assert.Equal(t, problemTypePrefix+tt.expectedCode, p.Type)
+			assert.Equal(t, "/schemas/user/1.0.0", p.Instance)
+			assert.NotEmpty(t, p.Title)
+		})
+	}
+}
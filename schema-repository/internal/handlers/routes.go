@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes adds the routes of the API to the router. The sync routes are only added when sync isn't nil.
+func RegisterRoutes(router *gin.Engine, h *Handler, sync *SyncHandler) {
+	router.GET("/schemas", h.ListSchemasHandler)
+	router.Group("/schemas/:name/:version").
+		GET("", h.GetSchemaHandler).
+		POST("", h.CreateSchemaHandler).
+		DELETE("", h.DeleteSchemaHandler)
+	router.GET("/schemas/:name/history", h.HistoryHandler)
+	router.GET("/audit/export", h.ExportAuditHandler)
+	router.GET("/openapi.json", h.OpenAPIHandler)
+	router.GET("/docs", h.SwaggerUIHandler)
+
+	if sync != nil {
+		router.GET("/sync/plan", sync.PlanHandler)
+		router.POST("/sync", sync.SyncHandler)
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/gitsync"
+)
+
+// SyncHandler exposes the git sync of the registry, only available when a git repository is configured.
+type SyncHandler struct {
+	Syncer *gitsync.Syncer
+}
+
+// NewSyncHandler creates a new SyncHandler instance.
+func NewSyncHandler(syncer *gitsync.Syncer) *SyncHandler {
+	return &SyncHandler{Syncer: syncer}
+}
+
+// PlanHandler handles the dry-run of a sync, reporting what it would change.
+func (h *SyncHandler) PlanHandler(ctx *gin.Context) {
+	report, err := h.Syncer.Plan(ctx)
+	if err != nil {
+		abortWithServiceError(ctx, err, "plan the sync of")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// SyncHandler handles an on demand sync, reporting what was changed.
+func (h *SyncHandler) SyncHandler(ctx *gin.Context) {
+	report, err := h.Syncer.Sync(ctx)
+	if err != nil {
+		abortWithServiceError(ctx, err, "sync")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
@@ -3,24 +3,25 @@ package handlers
 import (
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
-	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/service"
 )
 
+// validSchemaTag is reported for schemas not valid for their type
+const validSchemaTag = "valid_schema"
+
 func RegisterCustomValidators() {
 	v := binding.Validator.Engine().(*validator.Validate)
-
-	err := v.RegisterValidation("json_schema", validateJSONSchema)
-	if err != nil {
-		panic(err)
-	}
+	v.RegisterStructValidation(validateSchemaBody, SchemaBody{})
 }
 
-func validateJSONSchema(fl validator.FieldLevel) bool {
-	schema := fl.Field().Bytes()
-
-	if _, err := jsonschema.CompileString("", string(schema)); err != nil {
-		return false
+// validateSchemaBody checks the schema against its type, which is only known at the struct level
+func validateSchemaBody(sl validator.StructLevel) {
+	body := sl.Current().Interface().(SchemaBody)
+	if len(body.Schema) == 0 {
+		return
+	}
+	if service.ValidateSchema(body.Type, body.Schema) != nil {
+		sl.ReportError(body.Schema, "Schema", "schema", validSchemaTag, string(body.Type.OrDefault()))
 	}
-
-	return true
 }
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	auditActorField     = "actor"
+	auditActionField    = "action"
+	auditNameField      = "name"
+	auditVersionField   = "version"
+	auditHashField      = "hash"
+	auditTimestampField = "timestamp"
+	auditTSFormat       = time.RFC3339Nano
+)
+
+// AuditAction identifies the kind of mutation recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// AuditEvent is a single entry of the schema change history.
+type AuditEvent struct {
+	ID        string      `json:"id"`
+	Actor     string      `json:"actor"`
+	Action    AuditAction `json:"action"`
+	Name      string      `json:"name"`
+	Version   string      `json:"version"`
+	Hash      string      `json:"hash"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// ToValues flattens the event into stream field/value pairs. The ID is assigned by the stream itself.
+func (e AuditEvent) ToValues() map[string]string {
+	return map[string]string{
+		auditActorField:     e.Actor,
+		auditActionField:    string(e.Action),
+		auditNameField:      e.Name,
+		auditVersionField:   e.Version,
+		auditHashField:      e.Hash,
+		auditTimestampField: e.Timestamp.Format(auditTSFormat),
+	}
+}
+
+// AuditEventFromValues rebuilds an event from a stream entry.
+func AuditEventFromValues(id string, v map[string]string) AuditEvent {
+	e := AuditEvent{
+		ID:      id,
+		Actor:   v[auditActorField],
+		Action:  AuditAction(v[auditActionField]),
+		Name:    v[auditNameField],
+		Version: v[auditVersionField],
+		Hash:    v[auditHashField],
+	}
+
+	e.Timestamp, _ = time.Parse(auditTSFormat, v[auditTimestampField])
+
+	return e
+}
@@ -0,0 +1,22 @@
+package models
+
+// SchemaType identifies the language a schema is written in, which selects how schema-validator validates records.
+type SchemaType string
+
+const (
+	// SchemaTypeJSONSchema schemas are JSON Schema documents, the default type
+	SchemaTypeJSONSchema SchemaType = "json-schema"
+	// SchemaTypeAvro schemas are Avro schema declarations, as found in .avsc files
+	SchemaTypeAvro SchemaType = "avro"
+	// SchemaTypeProtobuf schemas are {"descriptorSet": "<base64 FileDescriptorSet>", "message": "<full name>"}
+	// documents. The descriptor set must include every imported file, as built by protoc --include_imports
+	SchemaTypeProtobuf SchemaType = "protobuf"
+)
+
+// OrDefault returns the type, or SchemaTypeJSONSchema when empty.
+func (t SchemaType) OrDefault() SchemaType {
+	if t == "" {
+		return SchemaTypeJSONSchema
+	}
+	return t
+}
@@ -0,0 +1,38 @@
+package models
+
+// SyncAction identifies what a git sync does, or would do, with a schema version.
+type SyncAction string
+
+const (
+	// SyncActionCreate registers a version present in git and missing from the registry
+	SyncActionCreate SyncAction = "create"
+	// SyncActionDelete removes a version missing from git, only when pruning is enabled
+	SyncActionDelete SyncAction = "delete"
+	// SyncActionConflict reports a version whose content differs between git and the registry. Versions are
+	// immutable, so it is never applied
+	SyncActionConflict SyncAction = "conflict"
+	// SyncActionMissing reports a registered version missing from git, when pruning is disabled
+	SyncActionMissing SyncAction = "missing"
+	// SyncActionInvalid reports a file in git that is not a valid schema, which is skipped
+	SyncActionInvalid SyncAction = "invalid"
+)
+
+// SyncChange is a single difference found between git and the registry.
+type SyncChange struct {
+	Action       SyncAction `json:"action"`
+	Name         string     `json:"name"`
+	Version      string     `json:"version"`
+	Path         string     `json:"path,omitempty"`
+	GitHash      string     `json:"gitHash,omitempty"`
+	RegistryHash string     `json:"registryHash,omitempty"`
+	Detail       string     `json:"detail,omitempty"`
+}
+
+// SyncReport is the reconciliation of the registry against a git commit.
+type SyncReport struct {
+	Commit string `json:"commit"`
+	// DryRun tells the changes were only planned, not applied
+	DryRun    bool         `json:"dryRun"`
+	Changes   []SyncChange `json:"changes"`
+	Unchanged int          `json:"unchanged"`
+}
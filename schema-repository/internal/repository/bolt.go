@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	bolt "go.etcd.io/bbolt"
+	berrors "go.etcd.io/bbolt/errors"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+)
+
+var (
+	valuesBucket  = []byte("values")
+	streamsBucket = []byte("streams")
+)
+
+// boltClient keeps values in a single bucket and each stream in its own nested bucket, keyed by a sequence number so
+// entries are iterated in insertion order
+type boltClient struct {
+	db  *bolt.DB
+	ids streamIDs
+}
+
+func newBoltClient(cfg config.RepoBolt) (*boltClient, error) {
+	db, err := bolt.Open(cfg.Path, 0o600, &bolt.Options{Timeout: cfg.Timeout})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{valuesBucket, streamsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &boltClient{db: db}, nil
+}
+
+func (b *boltClient) Set(_ context.Context, key string, value string) error {
+	return b.wrapError(b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(valuesBucket).Put([]byte(key), []byte(value))
+	}))
+}
+
+func (b *boltClient) SetNX(_ context.Context, key string, value string) (bool, error) {
+	var set bool
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(valuesBucket)
+		if bucket.Get([]byte(key)) != nil {
+			return nil
+		}
+		set = true
+		return bucket.Put([]byte(key), []byte(value))
+	})
+	return set, b.wrapError(err)
+}
+
+func (b *boltClient) Del(_ context.Context, keys ...string) error {
+	return b.wrapError(b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(valuesBucket)
+
+		var deleted int
+		for _, key := range keys {
+			if bucket.Get([]byte(key)) == nil {
+				continue
+			}
+			if err := bucket.Delete([]byte(key)); err != nil {
+				return err
+			}
+			deleted++
+		}
+
+		if deleted == 0 {
+			return ErrKeyNotFound
+		}
+		return nil
+	}))
+}
+
+func (b *boltClient) Get(_ context.Context, key string) (string, error) {
+	var val string
+	err := b.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(valuesBucket).Get([]byte(key))
+		if v == nil {
+			return ErrKeyNotFound
+		}
+		// Values are only valid during the transaction, so they must be copied
+		val = string(v)
+		return nil
+	})
+	return val, b.wrapError(err)
+}
+
+func (b *boltClient) Keys(_ context.Context, prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	err := b.db.View(func(tx *bolt.Tx) error {
+		// Keys are kept sorted, so the ones with the prefix are contiguous
+		c := tx.Bucket(valuesBucket).Cursor()
+		for k, _ := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, _ = c.Next() {
+			keys = append(keys, string(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, b.wrapError(err)
+	}
+
+	return keys, nil
+}
+
+func (b *boltClient) Append(_ context.Context, stream string, values map[string]string) error {
+	return b.wrapError(b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(streamsBucket).CreateBucketIfNotExists([]byte(stream))
+		if err != nil {
+			return err
+		}
+
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		entry, err := json.Marshal(storedEntry{ID: b.ids.next(), Values: values})
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(binary.BigEndian.AppendUint64(nil, seq), entry)
+	}))
+}
+
+func (b *boltClient) Range(_ context.Context, stream string) ([]StreamEntry, error) {
+	entries := make([]StreamEntry, 0)
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(streamsBucket).Bucket([]byte(stream))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(_, v []byte) error {
+			var e storedEntry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			entries = append(entries, StreamEntry(e))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, b.wrapError(err)
+	}
+
+	return entries, nil
+}
+
+// Close releases the database file, so other processes can open it
+func (b *boltClient) Close() error {
+	return b.db.Close()
+}
+
+// wrapError flags a closed or locked database as ErrUnavailable
+func (b *boltClient) wrapError(err error) error {
+	if errors.Is(err, berrors.ErrDatabaseNotOpen) || errors.Is(err, berrors.ErrTimeout) {
+		return unavailable(err)
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	zlog "github.com/rs/zerolog/log"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+)
+
+const invalidationChannelSuffix = "invalidate"
+
+// notifier broadcasts keys that changed to every instance sharing the same backend, including the sender.
+type notifier interface {
+	Publish(ctx context.Context, channel string, key string) error
+	Subscribe(ctx context.Context, channel string, onKey func(key string))
+}
+
+// cachedRepository is a read-through cache in front of another Repository. Only successful reads are cached, and
+// every write is broadcast so other instances evict their copies.
+type cachedRepository struct {
+	Repository
+	cache    *lru
+	notifier notifier
+	channel  string
+}
+
+func newCachedRepository(r Repository, n notifier, channel string, cfg config.RepoCache) *cachedRepository {
+	c := &cachedRepository{
+		Repository: r,
+		cache:      newLRU(cfg.Size, cfg.TTL),
+		notifier:   n,
+		channel:    channel,
+	}
+
+	n.Subscribe(context.Background(), channel, c.cache.remove)
+
+	return c
+}
+
+func (c *cachedRepository) Get(ctx context.Context, key string) (string, error) {
+	if val, ok := c.cache.get(key); ok {
+		return val, nil
+	}
+
+	val, err := c.Repository.Get(ctx, key)
+	if err != nil {
+		return val, err
+	}
+
+	c.cache.add(key, val)
+	return val, nil
+}
+
+func (c *cachedRepository) Set(ctx context.Context, key string, value string) error {
+	defer c.invalidate(ctx, key)
+	return c.Repository.Set(ctx, key, value)
+}
+
+func (c *cachedRepository) SetNX(ctx context.Context, key string, value string) (bool, error) {
+	set, err := c.Repository.SetNX(ctx, key, value)
+	if set {
+		c.invalidate(ctx, key)
+	}
+	return set, err
+}
+
+func (c *cachedRepository) Del(ctx context.Context, keys ...string) error {
+	defer c.invalidate(ctx, keys...)
+	return c.Repository.Del(ctx, keys...)
+}
+
+func (c *cachedRepository) invalidate(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		c.cache.remove(key)
+		if err := c.notifier.Publish(ctx, c.channel, key); err != nil {
+			zlog.Err(err).Str("key", key).Msg("failed to publish cache invalidation")
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+)
+
+// memoryRepository is a map backed Repository counting reads, to check what is served from cache
+type memoryRepository struct {
+	Repository
+	values map[string]string
+	gets   int
+}
+
+func (m *memoryRepository) Set(_ context.Context, key string, value string) error {
+	m.values[key] = value
+	return nil
+}
+
+func (m *memoryRepository) Del(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(m.values, key)
+	}
+	return nil
+}
+
+func (m *memoryRepository) Get(_ context.Context, key string) (string, error) {
+	m.gets++
+	val, ok := m.values[key]
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+	return val, nil
+}
+
+// localNotifier delivers published keys synchronously to every subscriber
+type localNotifier struct {
+	subscribers []func(key string)
+	published   []string
+}
+
+func (n *localNotifier) Publish(_ context.Context, _ string, key string) error {
+	n.published = append(n.published, key)
+	for _, s := range n.subscribers {
+		s(key)
+	}
+	return nil
+}
+
+func (n *localNotifier) Subscribe(_ context.Context, _ string, onKey func(key string)) {
+	n.subscribers = append(n.subscribers, onKey)
+}
+
+func TestCachedRepository(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.RepoCache{Size: 10, TTL: time.Minute}
+
+	backend := &memoryRepository{values: map[string]string{}}
+	n := &localNotifier{}
+	instanceA := newCachedRepository(backend, n, "channel", cfg)
+	instanceB := newCachedRepository(backend, n, "channel", cfg)
+
+	assert.NoError(t, instanceA.Set(ctx, "key", "v1"))
+
+	// First read hits the backend, the second one is served from the cache
+	for range 2 {
+		val, err := instanceB.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "v1", val)
+	}
+	assert.Equal(t, 1, backend.gets)
+
+	// A write from another instance evicts the cached copy
+	assert.NoError(t, instanceA.Set(ctx, "key", "v2"))
+	val, err := instanceB.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", val)
+	assert.Equal(t, 2, backend.gets)
+
+	// Deletions are propagated as well, and misses are not cached
+	assert.NoError(t, instanceA.Del(ctx, "key"))
+	for range 2 {
+		_, err = instanceB.Get(ctx, "key")
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	}
+	assert.Equal(t, 4, backend.gets)
+	assert.Equal(t, []string{"key", "key", "key"}, n.published)
+}
+
+func TestNewRepository_cacheSize(t *testing.T) {
+	cache := &config.RepoCache{TTL: time.Minute}
+
+	// The LRU is only used in front of Redis, so the size isn't required by the other backends
+	assert.NotPanics(t, func() {
+		NewRepository(config.Repository{Filesystem: &config.RepoFilesystem{Path: t.TempDir()}, Data: conformanceData, Cache: cache})
+	})
+	assert.PanicsWithError(t, "repository cache size must be positive with Redis, got 0", func() {
+		NewRepository(config.Repository{Redis: &config.RepoServer{Address: "localhost:6379"}, Data: conformanceData, Cache: cache})
+	})
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+)
+
+var conformanceData = config.RepoData{KeyPrefix: "schema-repository", KeySeparator: ":"}
+
+// testConformance checks the behavior every Repository implementation must share. newRepo must return an empty
+// repository, cleaned up by the test.
+func testConformance(t *testing.T, newRepo func(t *testing.T) Repository) {
+	key := func(parts ...string) string {
+		k := conformanceData.KeyPrefix
+		for _, p := range parts {
+			k += conformanceData.KeySeparator + p
+		}
+		return k
+	}
+
+	t.Run("Get missing key", func(t *testing.T) {
+		r := newRepo(t)
+		_, err := r.Get(context.Background(), key("user", "1.0.0"))
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	})
+
+	t.Run("Set and Get", func(t *testing.T) {
+		ctx := context.Background()
+		r := newRepo(t)
+
+		require.NoError(t, r.Set(ctx, key("user", "1.0.0"), `{"type":"object"}`))
+		val, err := r.Get(ctx, key("user", "1.0.0"))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"type":"object"}`, val)
+
+		// Set overwrites existing values
+		require.NoError(t, r.Set(ctx, key("user", "1.0.0"), `{"type":"string"}`))
+		val, err = r.Get(ctx, key("user", "1.0.0"))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"type":"string"}`, val)
+	})
+
+	t.Run("SetNX", func(t *testing.T) {
+		ctx := context.Background()
+		r := newRepo(t)
+
+		set, err := r.SetNX(ctx, key("user", "1.0.0"), "first")
+		assert.NoError(t, err)
+		assert.True(t, set)
+
+		set, err = r.SetNX(ctx, key("user", "1.0.0"), "second")
+		assert.NoError(t, err)
+		assert.False(t, set)
+
+		val, err := r.Get(ctx, key("user", "1.0.0"))
+		assert.NoError(t, err)
+		assert.Equal(t, "first", val)
+	})
+
+	t.Run("SetNX concurrently", func(t *testing.T) {
+		ctx := context.Background()
+		r := newRepo(t)
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		winners := 0
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				set, err := r.SetNX(ctx, key("user", "1.0.0"), fmt.Sprint(i))
+				assert.NoError(t, err)
+				if set {
+					mu.Lock()
+					winners++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 1, winners)
+	})
+
+	t.Run("Del", func(t *testing.T) {
+		ctx := context.Background()
+		r := newRepo(t)
+
+		require.NoError(t, r.Set(ctx, key("user", "1.0.0"), "value"))
+		assert.NoError(t, r.Del(ctx, key("user", "1.0.0")))
+
+		_, err := r.Get(ctx, key("user", "1.0.0"))
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+		assert.ErrorIs(t, r.Del(ctx, key("user", "1.0.0")), ErrKeyNotFound)
+
+		// Deleting several keys only fails when none of them exists
+		require.NoError(t, r.Set(ctx, key("user", "2.0.0"), "value"))
+		assert.NoError(t, r.Del(ctx, key("user", "1.0.0"), key("user", "2.0.0")))
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		ctx := context.Background()
+		r := newRepo(t)
+
+		keys, err := r.Keys(ctx, key())
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+
+		for _, k := range []string{key("user", "2.0.0"), key("order", "1.0.0"), key("user", "1.0.0")} {
+			require.NoError(t, r.Set(ctx, k, "value"))
+		}
+		require.NoError(t, r.Append(ctx, key("audit"), map[string]string{"action": "create"}))
+
+		// Streams aren't listed, and the keys are sorted
+		keys, err = r.Keys(ctx, key())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{key("order", "1.0.0"), key("user", "1.0.0"), key("user", "2.0.0")}, keys)
+
+		keys, err = r.Keys(ctx, key("user")+conformanceData.KeySeparator)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{key("user", "1.0.0"), key("user", "2.0.0")}, keys)
+
+		require.NoError(t, r.Del(ctx, key("user", "1.0.0")))
+		keys, err = r.Keys(ctx, key("user")+conformanceData.KeySeparator)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{key("user", "2.0.0")}, keys)
+	})
+
+	t.Run("Range missing stream", func(t *testing.T) {
+		r := newRepo(t)
+		entries, err := r.Range(context.Background(), key("audit"))
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Append and Range", func(t *testing.T) {
+		ctx := context.Background()
+		r := newRepo(t)
+
+		for i := range 5 {
+			require.NoError(t, r.Append(ctx, key("audit"), map[string]string{"n": fmt.Sprint(i), "action": "create"}))
+		}
+		require.NoError(t, r.Append(ctx, key("other"), map[string]string{"n": "other"}))
+
+		entries, err := r.Range(ctx, key("audit"))
+		require.NoError(t, err)
+		require.Len(t, entries, 5)
+
+		ids := make(map[string]bool)
+		for i, e := range entries {
+			assert.Equal(t, map[string]string{"n": fmt.Sprint(i), "action": "create"}, e.Values)
+			assert.NotEmpty(t, e.ID)
+			ids[e.ID] = true
+		}
+		assert.Len(t, ids, 5, "stream entry IDs must be unique")
+	})
+}
+
+func TestFilesystemConformance(t *testing.T) {
+	testConformance(t, func(t *testing.T) Repository {
+		r, err := newFilesystemClient(config.RepoFilesystem{Path: t.TempDir()}, conformanceData)
+		require.NoError(t, err)
+		return r
+	})
+}
+
+func TestBoltConformance(t *testing.T) {
+	testConformance(t, func(t *testing.T) Repository {
+		r, err := newBoltClient(config.RepoBolt{Path: filepath.Join(t.TempDir(), "schemas.db"), Timeout: time.Second})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close() })
+		return r
+	})
+}
+
+func TestCachedRepositoryConformance(t *testing.T) {
+	testConformance(t, func(t *testing.T) Repository {
+		r, err := newBoltClient(config.RepoBolt{Path: filepath.Join(t.TempDir(), "schemas.db"), Timeout: time.Second})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = r.Close() })
+		return newCachedRepository(r, &localNotifier{}, "channel", config.RepoCache{Size: 10, TTL: time.Minute})
+	})
+}
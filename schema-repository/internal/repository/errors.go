@@ -1,5 +1,20 @@
 package repository
 
-const (
-	ErrorKeyNotFound = "key not found"
+import (
+	"errors"
+	"fmt"
 )
+
+var (
+	// ErrKeyNotFound is returned when the requested key doesn't exist
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrUnavailable is returned when the backend can't be reached, as opposed to errors reported by the backend itself
+	ErrUnavailable = errors.New("repository unavailable")
+	// ErrReadOnly is returned when writing to a repository configured as read-only
+	ErrReadOnly = errors.New("repository is read-only")
+)
+
+// unavailable flags a connectivity failure with ErrUnavailable, keeping the original error in the chain
+func unavailable(err error) error {
+	return fmt.Errorf("%w: %w", ErrUnavailable, err)
+}
@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+)
+
+const (
+	fileSeparator   = "-"
+	valueFileExt    = ".json"
+	streamFileExt   = ".ndjson"
+	tempFilePattern = ".tmp-*"
+)
+
+// filesystemClient keeps each value in its own file and each stream as a newline delimited JSON file. Keys are mapped
+// to file names by dropping the key prefix and joining the remaining parts with a dash, so the schema key of user
+// version 1.0.0 is stored as user-1.0.0.json
+type filesystemClient struct {
+	dir       string
+	readOnly  bool
+	keyPrefix string
+	keySep    string
+	// mu serializes stream appends, so concurrent lines don't interleave
+	mu  sync.Mutex
+	ids streamIDs
+}
+
+func newFilesystemClient(cfg config.RepoFilesystem, data config.RepoData) (*filesystemClient, error) {
+	info, err := os.Stat(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", cfg.Path)
+	}
+
+	return &filesystemClient{
+		dir:       cfg.Path,
+		readOnly:  cfg.ReadOnly,
+		keyPrefix: data.KeyPrefix + data.KeySeparator,
+		keySep:    data.KeySeparator,
+	}, nil
+}
+
+func (f *filesystemClient) Set(_ context.Context, key string, value string) error {
+	path, err := f.writablePath(key, valueFileExt)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := f.writeTemp(value)
+	if err != nil {
+		return err
+	}
+
+	// Renaming is atomic, so readers never see a partially written file
+	if err = os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func (f *filesystemClient) SetNX(_ context.Context, key string, value string) (bool, error) {
+	path, err := f.writablePath(key, valueFileExt)
+	if err != nil {
+		return false, err
+	}
+
+	tmp, err := f.writeTemp(value)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = os.Remove(tmp) }()
+
+	// Unlike renaming, linking fails when the target exists, which makes it an atomic create-if-absent
+	err = os.Link(tmp, path)
+	if errors.Is(err, fs.ErrExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (f *filesystemClient) Del(_ context.Context, keys ...string) error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+
+	var deleted int
+	for _, key := range keys {
+		path, err := f.path(key, valueFileExt)
+		if err != nil {
+			return err
+		}
+
+		err = os.Remove(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		deleted++
+	}
+
+	if deleted == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+func (f *filesystemClient) Get(_ context.Context, key string) (string, error) {
+	path, err := f.path(key, valueFileExt)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", ErrKeyNotFound
+	}
+	return string(b), err
+}
+
+func (f *filesystemClient) Keys(_ context.Context, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0)
+	for _, e := range entries {
+		name, isValue := strings.CutSuffix(e.Name(), valueFileExt)
+		if !isValue || e.IsDir() {
+			continue
+		}
+		if key := f.key(name); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+	return keys, nil
+}
+
+func (f *filesystemClient) Append(_ context.Context, stream string, values map[string]string) error {
+	path, err := f.writablePath(stream, streamFileExt)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	line, err := json.Marshal(storedEntry{ID: f.ids.next(), Values: values})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (f *filesystemClient) Range(_ context.Context, stream string) ([]StreamEntry, error) {
+	path, err := f.path(stream, streamFileExt)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []StreamEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	entries := make([]StreamEntry, 0)
+	dec := json.NewDecoder(file)
+	for {
+		var e storedEntry
+		err = dec.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("corrupted stream file %s: %w", path, err)
+		}
+		entries = append(entries, StreamEntry(e))
+	}
+}
+
+// path maps a key to its file, rejecting keys that would escape the directory
+func (f *filesystemClient) path(key, ext string) (string, error) {
+	name := strings.ReplaceAll(strings.TrimPrefix(key, f.keyPrefix), f.keySep, fileSeparator)
+	if !filepath.IsLocal(name) || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid key %q for the filesystem repository", key)
+	}
+	return filepath.Join(f.dir, name+ext), nil
+}
+
+// key maps a file name back to its key. Names may contain dashes too, so only the last one is taken as a separator,
+// which restores the <name><separator><version> layout of schema keys
+func (f *filesystemClient) key(name string) string {
+	if i := strings.LastIndex(name, fileSeparator); i >= 0 {
+		name = name[:i] + f.keySep + name[i+len(fileSeparator):]
+	}
+	return f.keyPrefix + name
+}
+
+func (f *filesystemClient) writablePath(key, ext string) (string, error) {
+	if f.readOnly {
+		return "", ErrReadOnly
+	}
+	return f.path(key, ext)
+}
+
+// writeTemp writes the value to a temporary file in the same directory, so it can be moved into place atomically
+func (f *filesystemClient) writeTemp(value string) (string, error) {
+	tmp, err := os.CreateTemp(f.dir, tempFilePattern)
+	if err != nil {
+		return "", err
+	}
+
+	// Temporary files are only readable by their owner, which would not suit the final file
+	err = tmp.Chmod(0o644)
+	if err == nil {
+		_, err = tmp.WriteString(value)
+	}
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
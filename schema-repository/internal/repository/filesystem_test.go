@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+)
+
+func TestFilesystemReadOnly(t *testing.T) {
+	ctx := context.Background()
+
+	// The generated schemas directory can be mounted as is
+	r, err := newFilesystemClient(config.RepoFilesystem{Path: "../../../schemas/schemas", ReadOnly: true}, conformanceData)
+	require.NoError(t, err)
+
+	val, err := r.Get(ctx, "schema-repository:user:1.0.0")
+	assert.NoError(t, err)
+	expected, err := os.ReadFile("../../../schemas/schemas/user-1.0.0.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), val)
+
+	assert.ErrorIs(t, r.Set(ctx, "schema-repository:user:1.0.0", "{}"), ErrReadOnly)
+	_, err = r.SetNX(ctx, "schema-repository:user:9.0.0", "{}")
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.ErrorIs(t, r.Del(ctx, "schema-repository:user:1.0.0"), ErrReadOnly)
+	assert.ErrorIs(t, r.Append(ctx, "schema-repository:audit", map[string]string{}), ErrReadOnly)
+
+	entries, err := r.Range(ctx, "schema-repository:audit")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestFilesystemKeys(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	r, err := newFilesystemClient(config.RepoFilesystem{Path: dir}, conformanceData)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Set(ctx, "schema-repository:user:1.0.0", "{}"))
+	assert.FileExists(t, filepath.Join(dir, "user-1.0.0.json"))
+	require.NoError(t, r.Append(ctx, "schema-repository:audit", map[string]string{"action": "create"}))
+	assert.FileExists(t, filepath.Join(dir, "audit.ndjson"))
+
+	// Keys can't escape the directory
+	for _, key := range []string{"schema-repository:../user:1.0.0", "../user", "/etc/passwd", "schema-repository:.."} {
+		_, err = r.Get(ctx, key)
+		assert.Error(t, err, key)
+		assert.NotErrorIs(t, err, ErrKeyNotFound, key)
+	}
+
+	_, err = newFilesystemClient(config.RepoFilesystem{Path: filepath.Join(dir, "user-1.0.0.json")}, conformanceData)
+	assert.Error(t, err)
+}
@@ -2,35 +2,76 @@ package repository
 
 import (
 	"context"
+	"strings"
 
 	"github.com/mfelipe/go-feijoada/schema-repository/config"
 	"github.com/mfelipe/go-feijoada/schema-repository/internal/clients"
 )
 
+// Repository is a key-value store for schemas. Implementations must return ErrKeyNotFound for missing keys, and wrap
+// connectivity failures with ErrUnavailable.
 type Repository interface {
 	Set(ctx context.Context, key string, value string) error
+	// SetNX sets the key only if it doesn't exist yet, reporting whether it was set
+	SetNX(ctx context.Context, key string, value string) (bool, error)
 	Del(ctx context.Context, keys ...string) error
 	Get(ctx context.Context, key string) (string, error)
+	// Keys lists the keys of the values starting with prefix, sorted. Streams aren't listed
+	Keys(ctx context.Context, prefix string) ([]string, error)
+	Append(ctx context.Context, stream string, values map[string]string) error
+	Range(ctx context.Context, stream string) ([]StreamEntry, error)
 }
 
-// NewRepository creates a new Redis or Valkey implementation of Repository interface
+// StreamEntry is a single entry read from an append-only stream
+type StreamEntry struct {
+	ID     string
+	Values map[string]string
+}
+
+// NewRepository creates the Repository implementation of the configured backend: Redis, Valkey, a filesystem
+// directory, PostgreSQL or an embedded bbolt file.
+// When a cache is configured, Redis and Valkey reads are cached locally and invalidated across instances
 // TODO: Options - WithClient
 func NewRepository(cfg config.Repository) Repository {
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+
 	var r Repository
+	var err error
 
 	if cfg.Redis != nil {
 		c := clients.NewRedisClient(*cfg.Redis)
-		r = &redisClient{
+		rc := &redisClient{
 			client: c,
 		}
+		r = rc
+		if cfg.Cache != nil {
+			channel := strings.Join([]string{cfg.Data.KeyPrefix, invalidationChannelSuffix}, cfg.Data.KeySeparator)
+			r = newCachedRepository(rc, rc, channel, *cfg.Cache)
+		}
 	} else if cfg.Valkey != nil {
-		c := clients.NewValkeyClient(*cfg.Valkey)
-		r = &valkeyClient{
+		c := clients.NewValkeyClient(*cfg.Valkey, cfg.Cache)
+		vc := &valkeyClient{
 			client: c,
 		}
+		if cfg.Cache != nil {
+			vc.cacheTTL = cfg.Cache.TTL
+		}
+		r = vc
+	} else if cfg.Filesystem != nil {
+		r, err = newFilesystemClient(*cfg.Filesystem, cfg.Data)
+	} else if cfg.Postgres != nil {
+		r, err = newPostgresClient(context.Background(), *cfg.Postgres)
+	} else if cfg.Bolt != nil {
+		r, err = newBoltClient(*cfg.Bolt)
 	} else {
 		panic("no repository configured")
 	}
 
+	if err != nil {
+		panic(err)
+	}
+
 	return r
 }
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lru is a size bounded, least recently used cache, whose entries also expire after a TTL.
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+func newLRU(size int, ttl time.Duration) *lru {
+	return &lru{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+func (c *lru) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if c.now().After(entry.expiresAt) {
+		c.removeElement(el)
+		return "", false
+	}
+
+	c.order.MoveToFront(el)
+	return entry.value, true
+}
+
+func (c *lru) add(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	if c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *lru) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+func (c *lru) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *lru) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry).key)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	t.Run("evicts least recently used", func(t *testing.T) {
+		c := newLRU(2, time.Minute)
+		c.add("a", "1")
+		c.add("b", "2")
+
+		// touch "a" so "b" becomes the least recently used
+		_, _ = c.get("a")
+		c.add("c", "3")
+
+		_, ok := c.get("b")
+		assert.False(t, ok)
+		val, ok := c.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, "1", val)
+		assert.Equal(t, 2, c.len())
+	})
+
+	t.Run("expires entries after ttl", func(t *testing.T) {
+		now := time.Now()
+		c := newLRU(2, time.Minute)
+		c.now = func() time.Time { return now }
+		c.add("a", "1")
+
+		now = now.Add(2 * time.Minute)
+		_, ok := c.get("a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.len())
+	})
+
+	t.Run("updates existing entries", func(t *testing.T) {
+		c := newLRU(2, time.Minute)
+		c.add("a", "1")
+		c.add("a", "2")
+
+		val, ok := c.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, "2", val)
+		assert.Equal(t, 1, c.len())
+	})
+
+	t.Run("removes entries", func(t *testing.T) {
+		c := newLRU(2, time.Minute)
+		c.add("a", "1")
+		c.remove("a")
+		c.remove("missing")
+
+		_, ok := c.get("a")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+)
+
+// Every write is a single statement, so it runs in its own transaction: concurrent SetNX calls from several instances
+// can't both succeed, and a Del of several keys removes all or none of them
+const postgresMigration = `
+CREATE TABLE IF NOT EXISTS schema_repository_values (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS schema_repository_streams (
+	seq        BIGSERIAL PRIMARY KEY,
+	stream     TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp(),
+	entry      JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS schema_repository_streams_stream ON schema_repository_streams (stream, seq);
+`
+
+type postgresClient struct {
+	pool *pgxpool.Pool
+}
+
+func newPostgresClient(ctx context.Context, cfg config.RepoPostgres) (*postgresClient, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxConns > 0 {
+		poolCfg.MaxConns = cfg.MaxConns
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = pool.Exec(ctx, postgresMigration); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to create the repository tables: %w", err)
+	}
+
+	return &postgresClient{pool: pool}, nil
+}
+
+func (p *postgresClient) Set(ctx context.Context, key string, value string) error {
+	_, err := p.pool.Exec(ctx, `
+		INSERT INTO schema_repository_values (key, value) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, value)
+	return p.wrapError(err)
+}
+
+func (p *postgresClient) SetNX(ctx context.Context, key string, value string) (bool, error) {
+	tag, err := p.pool.Exec(ctx, `
+		INSERT INTO schema_repository_values (key, value) VALUES ($1, $2)
+		ON CONFLICT (key) DO NOTHING`, key, value)
+	if err != nil {
+		return false, p.wrapError(err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (p *postgresClient) Del(ctx context.Context, keys ...string) error {
+	tag, err := p.pool.Exec(ctx, `DELETE FROM schema_repository_values WHERE key = ANY($1)`, keys)
+	if err != nil {
+		return p.wrapError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+func (p *postgresClient) Get(ctx context.Context, key string) (string, error) {
+	var val string
+	err := p.pool.QueryRow(ctx, `SELECT value FROM schema_repository_values WHERE key = $1`, key).Scan(&val)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrKeyNotFound
+	}
+	return val, p.wrapError(err)
+}
+
+func (p *postgresClient) Keys(ctx context.Context, prefix string) ([]string, error) {
+	// The C collation orders the keys by their bytes, as the other backends do
+	rows, err := p.pool.Query(ctx, `
+		SELECT key FROM schema_repository_values
+		WHERE starts_with(key, $1) ORDER BY key COLLATE "C"`, prefix)
+	if err != nil {
+		return nil, p.wrapError(err)
+	}
+
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, p.wrapError(err)
+	}
+	return keys, nil
+}
+
+func (p *postgresClient) Append(ctx context.Context, stream string, values map[string]string) error {
+	_, err := p.pool.Exec(ctx, `INSERT INTO schema_repository_streams (stream, entry) VALUES ($1, $2)`, stream, values)
+	return p.wrapError(err)
+}
+
+func (p *postgresClient) Range(ctx context.Context, stream string) ([]StreamEntry, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT seq, created_at, entry FROM schema_repository_streams
+		WHERE stream = $1 ORDER BY seq`, stream)
+	if err != nil {
+		return nil, p.wrapError(err)
+	}
+
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (StreamEntry, error) {
+		var (
+			seq       int64
+			createdAt time.Time
+			values    map[string]string
+		)
+		if err := row.Scan(&seq, &createdAt, &values); err != nil {
+			return StreamEntry{}, err
+		}
+		// The sequence is unique across instances, while the timestamp keeps the IDs in the Redis format
+		return StreamEntry{ID: fmt.Sprintf("%d-%d", createdAt.UnixMilli(), seq), Values: values}, nil
+	})
+	if err != nil {
+		return nil, p.wrapError(err)
+	}
+
+	return entries, nil
+}
+
+// Close releases every connection of the pool
+func (p *postgresClient) Close() {
+	p.pool.Close()
+}
+
+// wrapError flags errors not replied by the PostgreSQL server as ErrUnavailable
+func (p *postgresClient) wrapError(err error) error {
+	var pgErr *pgconn.PgError
+	if err == nil || errors.Is(err, pgx.ErrNoRows) || errors.As(err, &pgErr) {
+		return err
+	}
+	return unavailable(err)
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+	utilstc "github.com/mfelipe/go-feijoada/utils/testcontainers"
+)
+
+func TestPostgresConformance(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	container := utilstc.StartPostgres(ctx)
+	t.Cleanup(func() { _ = container.Terminate(ctx) })
+
+	url, err := container.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	r, err := newPostgresClient(ctx, config.RepoPostgres{URL: url})
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+
+	testConformance(t, func(t *testing.T) Repository {
+		_, err := r.pool.Exec(ctx, `TRUNCATE schema_repository_values, schema_repository_streams`)
+		require.NoError(t, err)
+		return r
+	})
+}
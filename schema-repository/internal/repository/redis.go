@@ -3,8 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/redis/go-redis/v9"
+	zlog "github.com/rs/zerolog/log"
 
 	"github.com/mfelipe/go-feijoada/schema-repository/internal/clients"
 )
@@ -14,25 +18,133 @@ type redisClient struct {
 }
 
 func (r *redisClient) Set(ctx context.Context, key string, value string) error {
-	return r.client.Set(ctx, key, value, 0).Err()
+	return r.wrapError(r.client.Set(ctx, key, value, 0).Err())
+}
+
+func (r *redisClient) SetNX(ctx context.Context, key string, value string) (bool, error) {
+	val, err := r.client.SetNX(ctx, key, value, 0).Result()
+	return val, r.wrapError(err)
 }
 
 func (r *redisClient) Del(ctx context.Context, keys ...string) error {
 	val, err := r.client.Del(ctx, keys...).Result()
 
 	if val == 0 && err == nil {
-		return errors.New(ErrorKeyNotFound)
+		return ErrKeyNotFound
 	}
 
-	return err
+	return r.wrapError(err)
 }
 
 func (r *redisClient) Get(ctx context.Context, key string) (string, error) {
 	val, err := r.client.Get(ctx, key).Result()
 
 	if val == "" && errors.Is(err, redis.Nil) {
-		return val, errors.New(ErrorKeyNotFound)
+		return val, ErrKeyNotFound
+	}
+
+	return val, r.wrapError(err)
+}
+
+func (r *redisClient) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var mu sync.Mutex
+	keys := make([]string, 0)
+	scan := func(ctx context.Context, client clients.Redis) error {
+		var cursor uint64
+		for {
+			// Streams have their own type, so only the values are listed
+			page, next, err := client.ScanType(ctx, cursor, matchPrefix(prefix), scanCount, "string").Result()
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			keys = append(keys, page...)
+			mu.Unlock()
+			if cursor = next; cursor == 0 {
+				return nil
+			}
+		}
+	}
+
+	// SCAN only walks the keys of the node it's sent to, so every master of a cluster is scanned
+	var err error
+	if cluster, ok := r.client.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return scan(ctx, client)
+		})
+	} else {
+		err = scan(ctx, r.client)
+	}
+	if err != nil {
+		return nil, r.wrapError(err)
+	}
+
+	// SCAN may return a key more than once
+	slices.Sort(keys)
+	return slices.Compact(keys), nil
+}
+
+func (r *redisClient) Append(ctx context.Context, stream string, values map[string]string) error {
+	err := r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		ID:     "*",
+		Values: values,
+	}).Err()
+	return r.wrapError(err)
+}
+
+func (r *redisClient) Range(ctx context.Context, stream string) ([]StreamEntry, error) {
+	msgs, err := r.client.XRange(ctx, stream, "-", "+").Result()
+	if err != nil {
+		return nil, r.wrapError(err)
+	}
+
+	entries := make([]StreamEntry, 0, len(msgs))
+	for _, msg := range msgs {
+		values := make(map[string]string, len(msg.Values))
+		for k, v := range msg.Values {
+			values[k], _ = v.(string)
+		}
+		entries = append(entries, StreamEntry{ID: msg.ID, Values: values})
+	}
+
+	return entries, nil
+}
+
+func (r *redisClient) Publish(ctx context.Context, channel string, key string) error {
+	return r.wrapError(r.client.Publish(ctx, channel, key).Err())
+}
+
+func (r *redisClient) Subscribe(ctx context.Context, channel string, onKey func(key string)) {
+	ps := r.client.Subscribe(ctx, channel)
+	go func() {
+		zlog.Debug().Str("channel", channel).Msg("listening to cache invalidations")
+		for msg := range ps.Channel() {
+			onKey(msg.Payload)
+		}
+	}()
+}
+
+// scanCount is the number of keys SCAN is hinted to walk per call
+const scanCount = 100
+
+// matchPrefix builds the SCAN pattern of the keys starting with prefix, escaping its glob characters
+func matchPrefix(prefix string) string {
+	var b strings.Builder
+	for _, r := range prefix {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
 	}
+	return b.String() + "*"
+}
 
-	return val, err
+// wrapError flags errors not replied by the Redis server as ErrUnavailable
+func (r *redisClient) wrapError(err error) error {
+	var redisErr redis.Error
+	if err == nil || errors.Is(err, redis.Nil) || errors.As(err, &redisErr) {
+		return err
+	}
+	return unavailable(err)
 }
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/clients"
+	utilstc "github.com/mfelipe/go-feijoada/utils/testcontainers"
+)
+
+func TestRedisConformance(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	container := utilstc.StartRedis(ctx)
+	t.Cleanup(func() { _ = container.Terminate(ctx) })
+
+	address, err := container.Endpoint(ctx, "")
+	require.NoError(t, err)
+	client := clients.NewRedisClient(config.RepoServer{Address: address})
+
+	testConformance(t, func(t *testing.T) Repository {
+		require.NoError(t, client.(*redis.Client).FlushAll(ctx).Err())
+		return &redisClient{client: client}
+	})
+}
+
+func TestValkeyConformance(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	container := utilstc.StartValkey(ctx)
+	t.Cleanup(func() { _ = container.Terminate(ctx) })
+
+	address, err := container.ConnectionString(ctx)
+	require.NoError(t, err)
+	v := &valkeyClient{client: clients.NewValkeyClient(config.RepoServer{Address: address}, nil)}
+
+	testConformance(t, func(t *testing.T) Repository {
+		require.NoError(t, v.client.Do(ctx, v.client.B().Flushall().Build()).Error())
+		return v
+	})
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// storedEntry is the serialized form of a StreamEntry, for backends without native streams
+type storedEntry struct {
+	ID     string            `json:"id"`
+	Values map[string]string `json:"values"`
+}
+
+// streamIDs generates increasing stream entry IDs, in the same <milliseconds>-<sequence> format used by Redis
+type streamIDs struct {
+	mu  sync.Mutex
+	ms  int64
+	seq int64
+	now func() time.Time
+}
+
+func (g *streamIDs) next() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now
+	if g.now != nil {
+		now = g.now
+	}
+
+	// A clock going backwards must not produce a smaller ID, so the last timestamp is kept instead
+	if ms := now().UnixMilli(); ms > g.ms {
+		g.ms = ms
+		g.seq = 0
+	} else {
+		g.seq++
+	}
+
+	return fmt.Sprintf("%d-%d", g.ms, g.seq)
+}
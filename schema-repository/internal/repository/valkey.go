@@ -2,7 +2,8 @@ package repository
 
 import (
 	"context"
-	"errors"
+	"slices"
+	"time"
 
 	"github.com/valkey-io/valkey-go"
 
@@ -11,28 +12,102 @@ import (
 
 type valkeyClient struct {
 	client clients.Valkey
+	// cacheTTL enables client side caching for reads when greater than zero
+	cacheTTL time.Duration
 }
 
 func (v *valkeyClient) Set(ctx context.Context, key string, value string) error {
-	return v.client.Do(ctx, v.client.B().Set().Key(key).Value(value).Build()).Error()
+	return v.wrapError(v.client.Do(ctx, v.client.B().Set().Key(key).Value(value).Build()).Error())
+}
+
+func (v *valkeyClient) SetNX(ctx context.Context, key string, value string) (bool, error) {
+	err := v.client.Do(ctx, v.client.B().Set().Key(key).Value(value).Nx().Build()).Error()
+
+	// SET NX replies with nil when the key already exists
+	if valkey.IsValkeyNil(err) {
+		return false, nil
+	}
+
+	return err == nil, v.wrapError(err)
 }
 
 func (v *valkeyClient) Del(ctx context.Context, keys ...string) error {
 	val, err := v.client.Do(ctx, v.client.B().Del().Key(keys...).Build()).ToInt64()
 
 	if val == 0 && err == nil {
-		return errors.New(ErrorKeyNotFound)
+		return ErrKeyNotFound
 	}
 
-	return err
+	return v.wrapError(err)
 }
 
 func (v *valkeyClient) Get(ctx context.Context, key string) (string, error) {
-	val, err := v.client.Do(ctx, v.client.B().Get().Key(key).Build()).ToString()
+	var resp valkey.ValkeyResult
+	if v.cacheTTL > 0 {
+		resp = v.client.DoCache(ctx, v.client.B().Get().Key(key).Cache(), v.cacheTTL)
+	} else {
+		resp = v.client.Do(ctx, v.client.B().Get().Key(key).Build())
+	}
+
+	val, err := resp.ToString()
 
 	if val == "" && valkey.IsValkeyNil(err) {
-		return val, errors.New(ErrorKeyNotFound)
+		return val, ErrKeyNotFound
+	}
+
+	return val, v.wrapError(err)
+}
+
+func (v *valkeyClient) Keys(ctx context.Context, prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	// SCAN only walks the keys of the node it's sent to, so every node of a cluster is scanned. Replicas repeat the
+	// keys of their primary, which are dropped below
+	for _, node := range v.client.Nodes() {
+		var cursor uint64
+		for {
+			// Streams have their own type, so only the values are listed
+			cmd := node.B().Scan().Cursor(cursor).Match(matchPrefix(prefix)).Count(scanCount).Type("string").Build()
+			page, err := node.Do(ctx, cmd).AsScanEntry()
+			if err != nil {
+				return nil, v.wrapError(err)
+			}
+			keys = append(keys, page.Elements...)
+			if cursor = page.Cursor; cursor == 0 {
+				break
+			}
+		}
 	}
 
-	return val, err
+	slices.Sort(keys)
+	return slices.Compact(keys), nil
+}
+
+func (v *valkeyClient) Append(ctx context.Context, stream string, values map[string]string) error {
+	cmd := v.client.B().Xadd().Key(stream).Id("*").FieldValue()
+	for field, value := range values {
+		cmd = cmd.FieldValue(field, value)
+	}
+	return v.wrapError(v.client.Do(ctx, cmd.Build()).Error())
+}
+
+func (v *valkeyClient) Range(ctx context.Context, stream string) ([]StreamEntry, error) {
+	xEntries, err := v.client.Do(ctx, v.client.B().Xrange().Key(stream).Start("-").End("+").Build()).AsXRange()
+	if err != nil {
+		return nil, v.wrapError(err)
+	}
+
+	entries := make([]StreamEntry, 0, len(xEntries))
+	for _, e := range xEntries {
+		entries = append(entries, StreamEntry{ID: e.ID, Values: e.FieldValues})
+	}
+
+	return entries, nil
+}
+
+// wrapError flags errors not replied by the Valkey server as ErrUnavailable
+func (v *valkeyClient) wrapError(err error) error {
+	if _, isValkeyErr := valkey.IsValkeyErr(err); err == nil || isValkeyErr {
+		return err
+	}
+	return unavailable(err)
 }
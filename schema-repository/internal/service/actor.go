@@ -0,0 +1,25 @@
+package service
+
+import "context"
+
+const (
+	// AnonymousActor is recorded in the audit log when the caller didn't identify itself.
+	AnonymousActor = "anonymous"
+
+	auditKeySuffix = "audit"
+)
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the identity of who is performing a schema mutation.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, or AnonymousActor if none.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
@@ -1,6 +1,36 @@
 package service
 
-const (
-	ErrorSchemaNotFound    = "schema not found"
-	ErrorInvalidJSONSchema = "invalid JSON schema"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/models"
+)
+
+var (
+	// ErrSchemaNotFound is returned when the requested schema version doesn't exist
+	ErrSchemaNotFound = errors.New("schema not found")
+	// ErrSchemaConflict is returned when creating a schema version that already exists with a different content
+	ErrSchemaConflict = errors.New("schema version already exists with a different content")
+	// ErrSchemaLocked is returned when deleting a schema version that is locked, see SchemaService.Locked
+	ErrSchemaLocked = errors.New("schema version is locked")
+	// ErrInvalidJSONSchema is returned when a schema is not a valid JSON schema
+	ErrInvalidJSONSchema = errors.New("invalid JSON schema")
+	// ErrInvalidSchema is returned when an Avro or Protobuf schema is not valid, or its type is unknown
+	ErrInvalidSchema = errors.New("invalid schema")
 )
+
+// SchemaError identifies the schema version an error refers to. Use errors.Is to check for the underlying cause.
+type SchemaError struct {
+	Name    string
+	Version models.Semver
+	Err     error
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s (schema %s, version %s)", e.Err, e.Name, e.Version.String())
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
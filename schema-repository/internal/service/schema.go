@@ -1,10 +1,18 @@
 package service
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	zlog "github.com/rs/zerolog/log"
 
@@ -15,49 +23,240 @@ import (
 
 // SchemaService provides methods to manage JSON schemas.
 type SchemaService struct {
-	cfg config.RepoData
-	r   repository.Repository
+	cfg       config.RepoData
+	r         repository.Repository
+	lockAfter time.Duration
+	now       func() time.Time
+	// locked remembers the keys of locked versions, which stay locked as they can't be deleted
+	locked sync.Map
 }
 
 // NewSchemaService creates a new instance of SchemaService.
-func NewSchemaService(cfg config.RepoData, r repository.Repository) *SchemaService {
+func NewSchemaService(cfg config.RepoData, r repository.Repository, lifecycle config.Lifecycle) *SchemaService {
 	if r == nil {
 		panic(errors.New("repository not initialized"))
 	}
 	return &SchemaService{
-		cfg: cfg,
-		r:   r,
+		cfg:       cfg,
+		r:         r,
+		lockAfter: lifecycle.LockAfter,
+		now:       time.Now,
 	}
 }
 
-// AddSchema adds a new schema or a new version of an existing schema.
-func (s *SchemaService) AddSchema(ctx context.Context, name string, version models.Semver, schema json.RawMessage) error {
-	zlog.Debug().Msgf("Adding schema: %s, version: %s", name, version.String())
-	return s.r.Set(ctx, s.schemaKey(name, version), string(schema))
+// AddSchema adds a new schema or a new version of an existing schema, of the given type. An empty type is a JSON Schema.
+// Versions are immutable: adding an existing version again is a no-op when the content and type are the same, and
+// fails with ErrSchemaConflict otherwise.
+func (s *SchemaService) AddSchema(ctx context.Context, name string, version models.Semver, schemaType models.SchemaType, schema json.RawMessage) error {
+	zlog.Debug().Msgf("Adding %s schema: %s, version: %s", schemaType.OrDefault(), name, version.String())
+	key := s.schemaKey(name, version)
+
+	stored, err := encodeSchema(schemaType, schema)
+	if err != nil {
+		return err
+	}
+
+	set, err := s.r.SetNX(ctx, key, stored)
+	if err != nil {
+		return err
+	}
+
+	if !set {
+		existing, err := s.r.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if !SameContent(json.RawMessage(existing), json.RawMessage(stored)) {
+			return &SchemaError{Name: name, Version: version, Err: ErrSchemaConflict}
+		}
+		zlog.Debug().Msgf("Schema %s, version: %s already exists with the same content", name, version.String())
+		return nil
+	}
+
+	s.audit(ctx, models.AuditActionCreate, name, version, schema)
+	return nil
 }
 
-// DeleteSchema removes a specific version of a schema
+// DeleteSchema removes a specific version of a schema, failing with ErrSchemaLocked once the version is locked
 func (s *SchemaService) DeleteSchema(ctx context.Context, name string, version models.Semver) error {
 	zlog.Debug().Msgf("Removing schema: %s, version: %s", name, version.String())
-	err := s.r.Del(ctx, s.schemaKey(name, version))
+	key := s.schemaKey(name, version)
 
-	if err != nil && err.Error() == repository.ErrorKeyNotFound {
-		err = errors.New(ErrorSchemaNotFound)
+	locked, err := s.Locked(ctx, name, version)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return &SchemaError{Name: name, Version: version, Err: ErrSchemaLocked}
+	}
+
+	// Read the current content first, so the audit event records what was removed
+	stored, _ := s.r.Get(ctx, key)
+	_, schema := decodeSchema(json.RawMessage(stored))
+
+	err = s.r.Del(ctx, key)
+	if errors.Is(err, repository.ErrKeyNotFound) {
+		return &SchemaError{Name: name, Version: version, Err: ErrSchemaNotFound}
+	}
+	if err != nil {
+		return err
 	}
 
-	return err
+	s.audit(ctx, models.AuditActionDelete, name, version, schema)
+	return nil
 }
 
-// GetSchema retrieves a specific version of a schema.
-func (s *SchemaService) GetSchema(ctx context.Context, name string, version models.Semver) (json.RawMessage, error) {
+// GetSchema retrieves a specific version of a schema, along with its type.
+func (s *SchemaService) GetSchema(ctx context.Context, name string, version models.Semver) (json.RawMessage, models.SchemaType, error) {
 	zlog.Debug().Msgf("Getting schema: %s, version: %s", name, version.String())
-	schema, err := s.r.Get(ctx, s.schemaKey(name, version))
+	stored, err := s.r.Get(ctx, s.schemaKey(name, version))
 
-	if err != nil && err.Error() == repository.ErrorKeyNotFound {
-		return nil, errors.New(ErrorSchemaNotFound)
+	if errors.Is(err, repository.ErrKeyNotFound) {
+		return nil, "", &SchemaError{Name: name, Version: version, Err: ErrSchemaNotFound}
+	}
+	if err != nil {
+		return nil, "", err
 	}
 
-	return safeToRawMessage(schema)
+	raw, err := safeToRawMessage(stored)
+	if err != nil {
+		return nil, "", err
+	}
+	schemaType, schema := decodeSchema(raw)
+	return schema, schemaType, nil
+}
+
+// Locked tells if a schema version is locked: registered through the service for longer than the lifecycle LockAfter,
+// according to its latest audit event. Locked versions can't be deleted, so their content never changes. Versions
+// stored without going through the service have no registration time, and are never locked.
+func (s *SchemaService) Locked(ctx context.Context, name string, version models.Semver) (bool, error) {
+	key := s.schemaKey(name, version)
+	if s.lockAfter <= 0 {
+		return false, nil
+	}
+	if _, ok := s.locked.Load(key); ok {
+		return true, nil
+	}
+
+	history, err := s.History(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		e := history[i]
+		if e.Version != version.String() {
+			continue
+		}
+		if e.Action != models.AuditActionCreate || s.now().Sub(e.Timestamp) < s.lockAfter {
+			return false, nil
+		}
+		s.locked.Store(key, struct{}{})
+		return true, nil
+	}
+	return false, nil
+}
+
+// History retrieves the audit events of all versions of a schema, oldest first.
+func (s *SchemaService) History(ctx context.Context, name string) ([]models.AuditEvent, error) {
+	zlog.Debug().Msgf("Getting history of schema: %s", name)
+	events, err := s.AuditLog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]models.AuditEvent, 0)
+	for _, e := range events {
+		if e.Name == name {
+			history = append(history, e)
+		}
+	}
+
+	return history, nil
+}
+
+// AuditLog retrieves every audit event recorded, oldest first.
+func (s *SchemaService) AuditLog(ctx context.Context) ([]models.AuditEvent, error) {
+	entries, err := s.r.Range(ctx, s.auditKey())
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.AuditEvent, 0, len(entries))
+	for _, e := range entries {
+		events = append(events, models.AuditEventFromValues(e.ID, e.Values))
+	}
+
+	return events, nil
+}
+
+// SchemaVersion identifies a version of a schema.
+type SchemaVersion struct {
+	Name    string
+	Version models.Semver
+}
+
+// Versions lists the schema versions stored in the repository, sorted by name and version. Keys that aren't schema
+// keys are skipped.
+func (s *SchemaService) Versions(ctx context.Context) ([]SchemaVersion, error) {
+	prefix := s.cfg.KeyPrefix + s.cfg.KeySeparator
+	keys, err := s.r.Keys(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]SchemaVersion, 0, len(keys))
+	for _, key := range keys {
+		// Names may contain the separator, versions can't
+		name, version, ok := cutLast(strings.TrimPrefix(key, prefix), s.cfg.KeySeparator)
+		if !ok || name == "" {
+			continue
+		}
+
+		v := SchemaVersion{Name: name}
+		if v.Version.UnmarshalParam(version) != nil || v.Version.String() != version {
+			continue
+		}
+		versions = append(versions, v)
+	}
+
+	slices.SortFunc(versions, func(a, b SchemaVersion) int {
+		return cmp.Or(
+			strings.Compare(a.Name, b.Name),
+			cmp.Compare(a.Version.Major, b.Version.Major),
+			cmp.Compare(a.Version.Minor, b.Version.Minor),
+			cmp.Compare(a.Version.Patch, b.Version.Patch),
+		)
+	})
+	return versions, nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// audit appends a mutation event to the audit stream. The mutation has already happened at this point, so failures
+// are only logged instead of being reported to the caller.
+func (s *SchemaService) audit(ctx context.Context, action models.AuditAction, name string, version models.Semver, schema json.RawMessage) {
+	event := models.AuditEvent{
+		Actor:     ActorFromContext(ctx),
+		Action:    action,
+		Name:      name,
+		Version:   version.String(),
+		Hash:      ContentHash(schema),
+		Timestamp: s.now().UTC(),
+	}
+
+	if err := s.r.Append(ctx, s.auditKey(), event.ToValues()); err != nil {
+		zlog.Err(err).Str("schema", name).Str("version", version.String()).Str("action", string(action)).
+			Msg("failed to append audit event")
+	}
+}
+
+func (s *SchemaService) auditKey() string {
+	return strings.Join([]string{s.cfg.KeyPrefix, auditKeySuffix}, s.cfg.KeySeparator)
 }
 
 func (s *SchemaService) schemaKey(name string, version models.Semver) string {
@@ -68,14 +267,46 @@ func (s *SchemaService) schemaKey(name string, version models.Semver) string {
 func safeToRawMessage(schema string) (rm json.RawMessage, e error) {
 	defer func() {
 		if r := recover(); r != nil {
-			e = errors.New(ErrorInvalidJSONSchema)
+			e = ErrInvalidJSONSchema
 			switch err := r.(type) {
 			case error:
-				zlog.Err(err).Msgf("%s: %s", ErrorInvalidJSONSchema, r)
+				zlog.Err(err).Msgf("%s: %s", ErrInvalidJSONSchema, r)
 			default:
-				zlog.Error().Msgf("%s: %s", ErrorInvalidJSONSchema, err)
+				zlog.Error().Msgf("%s: %s", ErrInvalidJSONSchema, err)
 			}
 		}
 	}()
 	return json.RawMessage(schema), nil
 }
+
+// ValidateJSONSchema checks that the schema compiles with the vocabulary, reporting why it doesn't wrapped with
+// ErrInvalidJSONSchema.
+func ValidateJSONSchema(schema json.RawMessage) error {
+	c := newJSONSchemaCompiler()
+	err := c.AddResource("", bytes.NewReader(schema))
+	if err == nil {
+		_, err = c.Compile("")
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidJSONSchema, err)
+	}
+	return nil
+}
+
+// ContentHash returns the hex encoded SHA-256 of the schema content, prefixed by the algorithm name.
+func ContentHash(schema json.RawMessage) string {
+	if len(schema) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(schema)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// SameContent compares two schemas ignoring insignificant whitespace.
+func SameContent(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
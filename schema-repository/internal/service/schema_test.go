@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/config"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/models"
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/repository"
+)
+
+func TestSchemaService_Versions(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	data := config.RepoData{KeyPrefix: "schema-repository", KeySeparator: ":"}
+	svc := NewSchemaService(data, repository.NewRepository(config.Repository{
+		Filesystem: &config.RepoFilesystem{Path: dir},
+		Data:       data,
+	}), config.Lifecycle{})
+
+	for _, v := range []SchemaVersion{
+		{Name: "user", Version: models.Semver{Major: 10}},
+		{Name: "user", Version: models.Semver{Major: 2}},
+		{Name: "order-created", Version: models.Semver{Major: 1}},
+	} {
+		require.NoError(t, svc.AddSchema(ctx, v.Name, v.Version, "", json.RawMessage(`{"type": "object"}`)))
+	}
+	require.NoError(t, svc.DeleteSchema(ctx, "user", models.Semver{Major: 2}))
+	// Files added to the directory directly aren't in the audit log, but are listed all the same
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "address-1.0.0.json"), []byte(`{"type": "object"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.json"), []byte(`{}`), 0o644))
+
+	versions, err := svc.Versions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaVersion{
+		{Name: "address", Version: models.Semver{Major: 1}},
+		{Name: "order-created", Version: models.Semver{Major: 1}},
+		{Name: "user", Version: models.Semver{Major: 10}},
+	}, versions)
+}
+
+func TestSchemaService_Locked(t *testing.T) {
+	ctx := context.Background()
+	data := config.RepoData{KeyPrefix: "schema-repository", KeySeparator: ":"}
+	svc := NewSchemaService(data, repository.NewRepository(config.Repository{
+		Filesystem: &config.RepoFilesystem{Path: t.TempDir()},
+		Data:       data,
+	}), config.Lifecycle{LockAfter: time.Hour})
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	v1, v2 := models.Semver{Major: 1}, models.Semver{Major: 2}
+	schema := json.RawMessage(`{"type": "object"}`)
+	require.NoError(t, svc.AddSchema(ctx, "user", v1, models.SchemaTypeJSONSchema, schema))
+	require.NoError(t, svc.AddSchema(ctx, "user", v2, models.SchemaTypeJSONSchema, schema))
+
+	// Recently registered versions may still be deleted
+	locked, err := svc.Locked(ctx, "user", v1)
+	require.NoError(t, err)
+	assert.False(t, locked)
+	require.NoError(t, svc.DeleteSchema(ctx, "user", v2))
+
+	// Versions registered for longer than LockAfter can't be deleted anymore
+	now = now.Add(time.Hour)
+	locked, err = svc.Locked(ctx, "user", v1)
+	require.NoError(t, err)
+	assert.True(t, locked)
+	assert.ErrorIs(t, svc.DeleteSchema(ctx, "user", v1), ErrSchemaLocked)
+	_, _, err = svc.GetSchema(ctx, "user", v1)
+	assert.NoError(t, err)
+
+	// Deleted versions, and versions stored without going through the service, are never locked
+	for _, v := range []models.Semver{v2, {Major: 3}} {
+		locked, err = svc.Locked(ctx, "user", v)
+		require.NoError(t, err)
+		assert.False(t, locked, v.String())
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/linkedin/goavro/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/models"
+)
+
+// typedSchema is how schemas other than JSON Schemas are stored, along with their type. JSON Schemas are stored as is,
+// so schemas stored before types existed are still read as JSON Schemas. A JSON Schema is never mistaken for a typed
+// schema, as "avro" and "protobuf" aren't valid values of its type keyword.
+type typedSchema struct {
+	Type   models.SchemaType `json:"type"`
+	Schema json.RawMessage   `json:"schema"`
+}
+
+// protobufSchema is the content of protobuf schemas: a serialized FileDescriptorSet, base64 encoded in JSON, and the
+// full name of the message records are encoded with
+type protobufSchema struct {
+	DescriptorSet []byte `json:"descriptorSet"`
+	Message       string `json:"message"`
+}
+
+// encodeSchema returns the stored form of a schema of the given type
+func encodeSchema(schemaType models.SchemaType, schema json.RawMessage) (string, error) {
+	if schemaType.OrDefault() == models.SchemaTypeJSONSchema {
+		return string(schema), nil
+	}
+	data, err := json.Marshal(typedSchema{Type: schemaType, Schema: schema})
+	return string(data), err
+}
+
+// decodeSchema splits a stored schema into its type and content
+func decodeSchema(stored json.RawMessage) (models.SchemaType, json.RawMessage) {
+	var typed typedSchema
+	if json.Unmarshal(stored, &typed) == nil && len(typed.Schema) > 0 &&
+		(typed.Type == models.SchemaTypeAvro || typed.Type == models.SchemaTypeProtobuf) {
+		return typed.Type, typed.Schema
+	}
+	return models.SchemaTypeJSONSchema, stored
+}
+
+// ValidateSchema checks that the schema is valid for its type, reporting why it isn't wrapped with ErrInvalidSchema,
+// or with ErrInvalidJSONSchema for JSON Schemas.
+func ValidateSchema(schemaType models.SchemaType, schema json.RawMessage) error {
+	switch schemaType.OrDefault() {
+	case models.SchemaTypeJSONSchema:
+		return ValidateJSONSchema(schema)
+	case models.SchemaTypeAvro:
+		if _, err := goavro.NewCodec(string(schema)); err != nil {
+			return fmt.Errorf("%w: avro: %w", ErrInvalidSchema, err)
+		}
+		return nil
+	case models.SchemaTypeProtobuf:
+		if _, err := protobufMessage(schema); err != nil {
+			return fmt.Errorf("%w: protobuf: %w", ErrInvalidSchema, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported type %q", ErrInvalidSchema, schemaType)
+	}
+}
+
+// protobufMessage builds the descriptor of the message of a protobuf schema
+func protobufMessage(schema json.RawMessage) (protoreflect.MessageDescriptor, error) {
+	var doc protobufSchema
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Message == "" {
+		return nil, errors.New("message is required")
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(doc.DescriptorSet, &set); err != nil {
+		return nil, fmt.Errorf("descriptorSet: %w", err)
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("descriptorSet: %w", err)
+	}
+
+	d, err := files.FindDescriptorByName(protoreflect.FullName(doc.Message))
+	if err != nil {
+		return nil, fmt.Errorf("message %s: %w", doc.Message, err)
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message", doc.Message)
+	}
+	return md, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/mfelipe/go-feijoada/schema-repository/internal/models"
+)
+
+// protobufOrderSchema describes an orders.Order message with an id and an amount
+func protobufOrderSchema(t *testing.T, message string) json.RawMessage {
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("orders.proto"),
+		Package: proto.String("orders"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Order"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("id"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), JsonName: proto.String("id")},
+				{Name: proto.String("amount"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_DOUBLE.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), JsonName: proto.String("amount")},
+			},
+		}},
+	}}}
+	data, err := proto.Marshal(set)
+	require.NoError(t, err)
+
+	schema, err := json.Marshal(protobufSchema{DescriptorSet: data, Message: message})
+	require.NoError(t, err)
+	return schema
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		schemaType models.SchemaType
+		schema     json.RawMessage
+		err        error
+	}{
+		{name: "default type", schema: json.RawMessage(`{"type": "object"}`)},
+		{name: "invalid JSON schema", schemaType: models.SchemaTypeJSONSchema, schema: json.RawMessage(`{"type": "avro"}`), err: ErrInvalidJSONSchema},
+		{name: "avro", schemaType: models.SchemaTypeAvro, schema: json.RawMessage(`{"type": "record", "name": "User", "fields": [{"name": "email", "type": "string"}]}`)},
+		{name: "invalid avro", schemaType: models.SchemaTypeAvro, schema: json.RawMessage(`{"type": "record", "name": "User", "fields": [{"name": "email", "type": "text"}]}`), err: ErrInvalidSchema},
+		{name: "protobuf", schemaType: models.SchemaTypeProtobuf, schema: protobufOrderSchema(t, "orders.Order")},
+		{name: "unknown protobuf message", schemaType: models.SchemaTypeProtobuf, schema: protobufOrderSchema(t, "orders.Refund"), err: ErrInvalidSchema},
+		{name: "invalid descriptor set", schemaType: models.SchemaTypeProtobuf, schema: json.RawMessage(`{"descriptorSet": "bm90IGEgZGVzY3JpcHRvcg==", "message": "orders.Order"}`), err: ErrInvalidSchema},
+		{name: "unknown type", schemaType: "xml", schema: json.RawMessage(`{}`), err: ErrInvalidSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchema(tt.schemaType, tt.schema)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestEncodeSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		schemaType models.SchemaType
+		schema     json.RawMessage
+		stored     string
+	}{
+		{name: "JSON schemas are stored as is", schema: json.RawMessage(`{"type": "object"}`), stored: `{"type": "object"}`},
+		{name: "avro", schemaType: models.SchemaTypeAvro, schema: json.RawMessage(`"string"`), stored: `{"type":"avro","schema":"string"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := encodeSchema(tt.schemaType, tt.schema)
+			require.NoError(t, err)
+			assert.Equal(t, tt.stored, stored)
+
+			schemaType, schema := decodeSchema(json.RawMessage(stored))
+			assert.Equal(t, tt.schemaType.OrDefault(), schemaType)
+			assert.JSONEq(t, string(tt.schema), string(schema))
+		})
+	}
+
+	// JSON Schemas with schema and type properties are not mistaken for typed schemas
+	schemaType, schema := decodeSchema(json.RawMessage(`{"type": "object", "schema": {}}`))
+	assert.Equal(t, models.SchemaTypeJSONSchema, schemaType)
+	assert.JSONEq(t, `{"type": "object", "schema": {}}`, string(schema))
+}
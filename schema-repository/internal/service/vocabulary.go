@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"github.com/mfelipe/go-feijoada/utils/vocabulary"
+)
+
+// schemaVocabulary is the vocabulary JSON Schemas are compiled with, the same schema-validator validates them with
+var schemaVocabulary atomic.Pointer[vocabulary.Vocabulary]
+
+func init() {
+	v, _ := vocabulary.New(vocabulary.Config{})
+	schemaVocabulary.Store(v)
+}
+
+// UseVocabulary sets the formats and keywords JSON Schemas are checked against, replacing the built-in formats alone
+func UseVocabulary(v *vocabulary.Vocabulary) {
+	schemaVocabulary.Store(v)
+}
+
+// newJSONSchemaCompiler returns a compiler knowing the formats of the vocabulary, rejecting invalid values of its
+// keywords, and unknown formats when they are asserted
+func newJSONSchemaCompiler() *jsonschema.Compiler {
+	v := schemaVocabulary.Load()
+	c := jsonschema.NewCompiler()
+	c.AssertFormat = v.AssertFormat()
+	for name, format := range v.Formats() {
+		c.Formats[name] = format
+	}
+	c.RegisterExtension("vocabulary", nil, vocabularyCompiler{
+		formats:      c.Formats,
+		keywords:     v.Keywords(),
+		assertFormat: c.AssertFormat,
+	})
+	return c
+}
+
+// vocabularyCompiler checks the custom keywords and formats of each subschema. Values are not validated by the
+// repository, so no extension schema is returned
+type vocabularyCompiler struct {
+	formats      map[string]func(any) bool
+	keywords     map[string]vocabulary.Keyword
+	assertFormat bool
+}
+
+func (c vocabularyCompiler) Compile(_ jsonschema.CompilerContext, m map[string]any) (jsonschema.ExtSchema, error) {
+	for name, keyword := range c.keywords {
+		if value, ok := m[name]; ok {
+			if _, err := keyword(value); err != nil {
+				return nil, fmt.Errorf("keyword %s: %w", name, err)
+			}
+		}
+	}
+
+	// Validations against an unknown format always fail when formats are asserted
+	if format, ok := m["format"].(string); ok && c.assertFormat {
+		_, custom := c.formats[format]
+		_, standard := jsonschema.Formats[format]
+		if !custom && !standard {
+			return nil, fmt.Errorf("unknown format %q", format)
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/utils/vocabulary"
+)
+
+func TestValidateJSONSchema_Vocabulary(t *testing.T) {
+	v, err := vocabulary.New(vocabulary.Config{AssertFormat: true, Formats: map[string]string{"order-id": `^ord_`}})
+	require.NoError(t, err)
+	v.RegisterKeyword("currencyOf", func(value any) (vocabulary.Check, error) {
+		if _, ok := value.(string); !ok {
+			return nil, errors.New("must be a property name")
+		}
+		return func(any) error { return nil }, nil
+	})
+
+	UseVocabulary(v)
+	t.Cleanup(func() {
+		defaults, _ := vocabulary.New(vocabulary.Config{})
+		UseVocabulary(defaults)
+	})
+
+	tests := []struct {
+		name   string
+		schema string
+		err    string
+	}{
+		{name: "built-in formats", schema: `{"properties": {"currency": {"format": "iso4217"}, "phone": {"format": "e164"}}}`},
+		{name: "configured format", schema: `{"properties": {"id": {"format": "order-id"}}}`},
+		{name: "standard format", schema: `{"format": "date-time"}`},
+		{name: "unknown format", schema: `{"properties": {"id": {"format": "customer-id"}}}`, err: `unknown format "customer-id"`},
+		{name: "built-in keyword", schema: `{"items": {"maxDecimals": 2}}`},
+		{name: "invalid keyword value", schema: `{"items": {"maxDecimals": "two"}}`, err: "keyword maxDecimals: must be a non-negative integer"},
+		{name: "registered keyword", schema: `{"properties": {"amount": {"currencyOf": "currency"}}}`},
+		{name: "invalid registered keyword value", schema: `{"properties": {"amount": {"currencyOf": 1}}}`, err: "keyword currencyOf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJSONSchema(json.RawMessage(tt.schema))
+			if tt.err != "" {
+				assert.ErrorIs(t, err, ErrInvalidJSONSchema)
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		defaults, _ := vocabulary.New(vocabulary.Config{})
+		UseVocabulary(defaults)
+		assert.NoError(t, ValidateJSONSchema(json.RawMessage(`{"format": "customer-id", "currencyOf": 1}`)))
+	})
+}
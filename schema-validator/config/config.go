@@ -1,5 +1,58 @@
 package config
 
+import (
+	"time"
+
+	"github.com/mfelipe/go-feijoada/utils/vocabulary"
+)
+
 type Config struct {
 	DefaultBaseURI string `json:"defaultBaseURI" koanf:"defaultBaseURI,required"`
+	// Offline never contacts schema-repository: only schemas added with AddSchema or preloaded from Preload.Dir are
+	// known, any other being not found
+	Offline bool `json:"offline" koanf:"offline"`
+	// Loader configures how unknown schemas are fetched from schema-repository. When absent, each schema is fetched with
+	// a single attempt and kept forever
+	Loader *Loader `json:"loader" koanf:"loader"`
+	// Preload configures which schemas are compiled by SchemaValidator.Preload, before they are first used
+	Preload *Preload `json:"preload" koanf:"preload"`
+	// Cache keeps the reports of validated raw payloads, so identical payloads are not validated again. Disabled when
+	// absent
+	Cache *Cache `json:"cache" koanf:"cache"`
+	// Vocabulary adds formats and toggles format assertion, shared with schema-repository so both validate schemas the
+	// same way. When absent, formats are annotations and only the built-in ones are known
+	Vocabulary *vocabulary.Config `json:"vocabulary" koanf:"vocabulary"`
+}
+
+// Cache configures the validation results cache
+type Cache struct {
+	// Size is the maximum number of reports kept, the least recently used being evicted first
+	Size int `json:"size" koanf:"size,gt=0"`
+}
+
+// Loader configures how schemas are fetched and how long they are kept
+type Loader struct {
+	// RetryMax is how many times a failed fetch is retried. Not found responses are never retried
+	RetryMax int `json:"retryMax" koanf:"retryMax,gte=0"`
+	// RetryWaitMin and RetryWaitMax bound the exponential backoff between retries
+	RetryWaitMin time.Duration `json:"retryWaitMin" koanf:"retryWaitMin"`
+	RetryWaitMax time.Duration `json:"retryWaitMax" koanf:"retryWaitMax"`
+	// Timeout bounds each attempt, zero means no timeout
+	Timeout time.Duration `json:"timeout" koanf:"timeout"`
+	// TTL is how long a fetched schema is used before being refreshed in the background. The stale schema keeps being
+	// used until the refresh completes. Zero keeps schemas forever
+	TTL time.Duration `json:"ttl" koanf:"ttl"`
+	// NotFoundTTL is how long a schema not found is remembered, failing validations without fetching it again
+	NotFoundTTL time.Duration `json:"notFoundTTL" koanf:"notFoundTTL"`
+}
+
+// Preload selects the schemas compiled ahead of their first use
+type Preload struct {
+	// Schemas restricts preloading to the given schemas, either <name> for every version of a schema, or
+	// <name>@<version>. Every schema is preloaded when empty
+	Schemas []string `json:"schemas" koanf:"schemas"`
+	// Dir reads schemas from the <name>-<version>.json JSON Schema files and <name>-<version>.avsc Avro schema files of a
+	// local directory, such as schemas/schemas, instead of listing them from schema-repository. They are registered
+	// under their $id, or under <defaultBaseURI>/schemas/<name>/<version> without one, and never expire
+	Dir string `json:"dir" koanf:"dir"`
 }
@@ -7,15 +7,18 @@ replace github.com/mfelipe/go-feijoada/utils => ../utils
 require (
 	github.com/hashicorp/go-retryablehttp v0.7.8
 	github.com/kaptinlin/jsonschema v0.4.6
+	github.com/linkedin/goavro/v2 v2.12.0
 	github.com/mfelipe/go-feijoada/utils v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.10.0
 	golang.org/x/sync v0.16.0
+	google.golang.org/protobuf v1.36.6
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/goccy/go-json v0.10.5 // indirect
 	github.com/goccy/go-yaml v1.18.0 // indirect
+	github.com/golang/snappy v0.0.1 // indirect
 	github.com/gotnospirit/makeplural v0.0.0-20180622080156-a5f48d94d976 // indirect
 	github.com/gotnospirit/messageformat v0.0.0-20221001023931-dfe49f1eb092 // indirect
 	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// avroSchema is a compiled Avro schema. JSON is read and written as standard JSON, unions not being wrapped in an
+// object naming their type.
+type avroSchema struct {
+	codec *goavro.Codec
+}
+
+func newAvroSchema(schema []byte) (compiledSchema, error) {
+	codec, err := goavro.NewCodecForStandardJSONFull(string(schema))
+	if err != nil {
+		return nil, fmt.Errorf("compiling avro schema: %w", err)
+	}
+	return avroSchema{codec: codec}, nil
+}
+
+func (s avroSchema) validate(uri string, obj any) (*Report, error) {
+	data, ok := obj.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	_, rest, err := s.codec.NativeFromTextual(data)
+	if err == nil && len(bytes.TrimSpace(rest)) > 0 {
+		err = fmt.Errorf("%d bytes after the value", len(rest))
+	}
+	if err != nil {
+		return decodeReport(uri, SchemaTypeAvro, err), nil
+	}
+	return &Report{Valid: true}, nil
+}
+
+// decode reads the binary encoding of a single datum, without the header of Avro container files
+func (s avroSchema) decode(uri, contentType string, payload []byte) (json.RawMessage, *Report, error) {
+	if contentType != ContentTypeAvro {
+		return nil, nil, fmt.Errorf("%w: %s payloads of an avro schema", ErrUnsupportedContentType, contentType)
+	}
+
+	native, rest, err := s.codec.NativeFromBinary(payload)
+	if err == nil && len(rest) > 0 {
+		err = fmt.Errorf("%d bytes after the datum", len(rest))
+	}
+	if err != nil {
+		return nil, decodeReport(uri, SchemaTypeAvro, err), nil
+	}
+
+	canonical, err := s.codec.TextualFromNative(nil, native)
+	if err != nil {
+		return nil, nil, err
+	}
+	return canonical, &Report{Valid: true}, nil
+}
@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mfelipe/go-feijoada/schema-validator/config"
+)
+
+const benchmarkSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["id", "userId", "items", "total", "status"],
+	"properties": {
+		"id": {"type": "string", "format": "uuid"},
+		"userId": {"type": "string", "format": "uuid"},
+		"items": {
+			"type": "array",
+			"minItems": 1,
+			"items": {
+				"type": "object",
+				"required": ["productId", "quantity", "price"],
+				"properties": {
+					"productId": {"type": "string"},
+					"quantity": {"type": "integer", "minimum": 1},
+					"price": {"type": "number", "minimum": 0}
+				}
+			}
+		},
+		"total": {"type": "number", "minimum": 0},
+		"status": {"enum": ["pending", "paid", "shipped", "delivered", "cancelled"]},
+		"createdAt": {"type": "string", "format": "date-time"}
+	}
+}`
+
+const benchmarkPayload = `{
+	"id": "8f14e45f-ceea-4e6b-9a6d-0c3e1a5b7d21",
+	"userId": "c9f0f895-fb98-4b91-8f2e-3f7c2a1d4e56",
+	"items": [
+		{"productId": "p-1", "quantity": 2, "price": 10.5},
+		{"productId": "p-2", "quantity": 1, "price": 99.9},
+		{"productId": "p-3", "quantity": 5, "price": 1.25}
+	],
+	"total": 127.15,
+	"status": "paid",
+	"createdAt": "2025-01-01T12:00:00Z"
+}`
+
+const benchmarkURI = "http://localhost:8080/schemas/order/1.0.0"
+
+func newBenchmarkValidator(b *testing.B, cache *config.Cache) *validator {
+	v := New(config.Config{DefaultBaseURI: "http://localhost:8080", Cache: cache})
+	if err := v.AddSchema(benchmarkURI, json.RawMessage(benchmarkSchema)); err != nil {
+		b.Fatal(err)
+	}
+	return v
+}
+
+func validate(b *testing.B, v *validator, obj any) {
+	report, err := v.Validate(benchmarkURI, obj)
+	if err != nil || !report.Valid {
+		b.Fatalf("unexpected validation outcome: %v %+v", err, report)
+	}
+}
+
+// BenchmarkValidate_Raw validates the payload as received from kafka, leaving the decoding to the validator
+func BenchmarkValidate_Raw(b *testing.B) {
+	v := newBenchmarkValidator(b, nil)
+	payload := []byte(benchmarkPayload)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		validate(b, v, payload)
+	}
+}
+
+// BenchmarkValidate_Unmarshalled validates a payload decoded beforehand, as when the decoded value is needed anyway
+func BenchmarkValidate_Unmarshalled(b *testing.B) {
+	v := newBenchmarkValidator(b, nil)
+	var obj any
+	if err := json.Unmarshal([]byte(benchmarkPayload), &obj); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		validate(b, v, obj)
+	}
+}
+
+// BenchmarkValidate_UnmarshalAndValidate decodes the payload before validating it, on every iteration
+func BenchmarkValidate_UnmarshalAndValidate(b *testing.B) {
+	v := newBenchmarkValidator(b, nil)
+	payload := []byte(benchmarkPayload)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		var obj any
+		if err := json.Unmarshal(payload, &obj); err != nil {
+			b.Fatal(err)
+		}
+		validate(b, v, obj)
+	}
+}
+
+// BenchmarkValidate_RawCached validates the same raw payload over and over, with the results cache
+func BenchmarkValidate_RawCached(b *testing.B) {
+	v := newBenchmarkValidator(b, &config.Cache{Size: 1024})
+	payload := []byte(benchmarkPayload)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		validate(b, v, payload)
+	}
+}
+
+// BenchmarkValidate_RawCacheMiss measures the overhead of the results cache when payloads are never repeated, cycling
+// through more payloads than the cache holds
+func BenchmarkValidate_RawCacheMiss(b *testing.B) {
+	v := newBenchmarkValidator(b, &config.Cache{Size: 1024})
+	payloads := make([][]byte, 4096)
+	for i := range payloads {
+		payloads[i] = []byte(strings.TrimSuffix(benchmarkPayload, "}") + `, "seq": ` + strconv.Itoa(i) + `}`)
+	}
+
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		validate(b, v, payloads[i%len(payloads)])
+		i++
+	}
+}
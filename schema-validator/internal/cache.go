@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// resultCache is a size bounded, least recently used cache of validation reports, keyed by schema fingerprint and
+// payload hash. Reports never go stale, as a changed schema has a different fingerprint.
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type resultEntry struct {
+	key    string
+	report *Report
+}
+
+func newResultCache(size int) *resultCache {
+	return &resultCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// key identifies the validation of obj against a schema. Only raw JSON payloads are cacheable, hashing decoded values
+// would cost about as much as validating them.
+func (c *resultCache) key(fingerprint string, obj any) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	payload, ok := obj.([]byte)
+	if !ok {
+		return "", false
+	}
+
+	sum := sha256.Sum256(payload)
+	return fingerprint + ":" + hex.EncodeToString(sum[:]), true
+}
+
+func (c *resultCache) get(key string) (*Report, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*resultEntry).report, true
+}
+
+func (c *resultCache) add(key string, report *Report) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*resultEntry).report = report
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&resultEntry{key: key, report: report})
+	if c.order.Len() > c.size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*resultEntry).key)
+	}
+}
+
+func (c *resultCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// fingerprint identifies a schema by its URI and content
+func fingerprint(uri string, schema []byte) string {
+	h := sha256.New()
+	h.Write([]byte(uri))
+	h.Write([]byte{0})
+	h.Write(schema)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-validator/config"
+)
+
+func TestResultCache(t *testing.T) {
+	t.Run("evicts least recently used", func(t *testing.T) {
+		c := newResultCache(2)
+		a, b := &Report{Valid: true}, &Report{Valid: false}
+		c.add("a", a)
+		c.add("b", b)
+
+		// touch "a" so "b" becomes the least recently used
+		_, _ = c.get("a")
+		c.add("c", &Report{})
+
+		_, ok := c.get("b")
+		assert.False(t, ok)
+		report, ok := c.get("a")
+		assert.True(t, ok)
+		assert.Same(t, a, report)
+		assert.Equal(t, 2, c.len())
+	})
+
+	t.Run("only raw payloads are cacheable", func(t *testing.T) {
+		c := newResultCache(2)
+
+		raw, ok := c.key("fp", []byte(`{"id": 1}`))
+		assert.True(t, ok)
+
+		other, _ := c.key("other", []byte(`{"id": 1}`))
+		assert.NotEqual(t, raw, other)
+
+		_, ok = c.key("fp", map[string]any{"id": 1})
+		assert.False(t, ok)
+
+		var disabled *resultCache
+		_, ok = disabled.key("fp", []byte(`{"id": 1}`))
+		assert.False(t, ok)
+	})
+}
+
+func TestValidator_CachedResults(t *testing.T) {
+	server := newSchemaServer(t, `{"type": "object", "required": ["id"]}`)
+	v, c := newLoaderValidator(server.URL, config.Loader{TTL: time.Minute})
+	v.results = newResultCache(10)
+	uri := server.URL + "/schemas/user/1.0.0"
+
+	first, err := v.Validate(uri, []byte(`{"name": "John"}`))
+	require.NoError(t, err)
+	assert.False(t, first.Valid)
+
+	// Identical payloads get the same report
+	again, err := v.Validate(uri, json.RawMessage(`{"name": "John"}`))
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+	assert.Equal(t, 1, v.results.len())
+
+	// Decoded payloads are always validated
+	decoded, err := v.Validate(uri, map[string]any{"name": "John"})
+	require.NoError(t, err)
+	assert.NotSame(t, first, decoded)
+	assert.Equal(t, 1, v.results.len())
+
+	// Once the schema changes, previous reports are not used anymore
+	server.set(200, `{"type": "object"}`)
+	c.Advance(2 * time.Minute)
+	_, _ = v.Validate(uri, []byte(`{}`))
+	assert.Eventually(t, func() bool {
+		report, err := v.Validate(uri, []byte(`{"name": "John"}`))
+		return err == nil && report.Valid
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/kaptinlin/jsonschema"
+
+	"github.com/mfelipe/go-feijoada/utils/schemabody"
+)
+
+// Schema types, as stored by schema-repository
+const (
+	SchemaTypeJSONSchema = schemabody.TypeJSONSchema
+	SchemaTypeAvro       = "avro"
+	SchemaTypeProtobuf   = "protobuf"
+)
+
+// Content types of payloads. JSON payloads are validated as they are, binary ones are decoded with their schema.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeAvro     = "application/avro"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+var (
+	// ErrUnsupportedSchemaType is returned for schemas of a type without engine
+	ErrUnsupportedSchemaType = errors.New("unsupported schema type")
+	// ErrUnsupportedContentType is returned for payloads the engine of their schema can't decode
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+)
+
+// contentTypeAliases are other common names of the supported content types
+var contentTypeAliases = map[string]string{
+	"":                                   ContentTypeJSON,
+	"text/json":                          ContentTypeJSON,
+	"avro/binary":                        ContentTypeAvro,
+	"application/vnd.apache.avro+binary": ContentTypeAvro,
+	"application/protobuf":               ContentTypeProtobuf,
+	"application/vnd.google.protobuf":    ContentTypeProtobuf,
+}
+
+// compiledSchema validates payloads against a schema, whatever its type
+type compiledSchema interface {
+	// validate checks a JSON payload, either raw or already decoded
+	validate(uri string, obj any) (*Report, error)
+	// decode checks a binary payload, returning it as canonical JSON when valid
+	decode(uri, contentType string, payload []byte) (json.RawMessage, *Report, error)
+}
+
+// engines compile the schemas of each type but JSON Schema, which the validator compiles itself so references to other
+// schemas are resolved
+var engines = map[string]func(schema []byte) (compiledSchema, error){
+	SchemaTypeAvro:     newAvroSchema,
+	SchemaTypeProtobuf: newProtobufSchema,
+}
+
+// normalizeContentType drops the parameters of a content type and resolves its aliases. JSON is the default
+func normalizeContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	if alias, ok := contentTypeAliases[mediaType]; ok {
+		return alias
+	}
+	return mediaType
+}
+
+// decodeReport reports a payload the schema couldn't decode, under the schema type as keyword
+func decodeReport(uri, schemaType string, err error) *Report {
+	base, _, _ := strings.Cut(uri, "#")
+	return &Report{Errors: []ValidationError{{
+		Keyword:        schemaType,
+		SchemaLocation: base + "#",
+		Message:        err.Error(),
+	}}}
+}
+
+// jsonSchema is a compiled JSON Schema, along with the checks of the custom keywords it uses, if any
+type jsonSchema struct {
+	schema   *jsonschema.Schema
+	keywords *keywordSchema
+}
+
+func (s jsonSchema) validate(uri string, obj any) (*Report, error) {
+	if s.schema == nil {
+		return nil, ErrSchemaNotFound
+	}
+	result := s.schema.Validate(obj)
+	if result == nil {
+		return nil, errors.New("validation result is nil")
+	}
+	report := newReport(uri, result)
+	if s.keywords != nil {
+		if err := s.validateKeywords(report, uri, obj); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// validateKeywords adds the failed checks of custom keywords to the report. Values that aren't valid JSON were already
+// reported by the compiler
+func (s jsonSchema) validateKeywords(report *Report, uri string, obj any) error {
+	data, ok := obj.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(obj); err != nil {
+			return err
+		}
+	}
+	instance, err := decodeJSON(data)
+	if err != nil {
+		return nil
+	}
+
+	base, _, _ := strings.Cut(uri, "#")
+	s.keywords.validate(report, base, "", instance, map[*keywordSchema]bool{})
+	report.Valid = len(report.Errors) == 0
+	return nil
+}
+
+func (s jsonSchema) decode(_, contentType string, _ []byte) (json.RawMessage, *Report, error) {
+	return nil, nil, fmt.Errorf("%w: %s payloads of a JSON schema", ErrUnsupportedContentType, contentType)
+}
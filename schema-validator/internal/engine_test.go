@@ -0,0 +1,199 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/mfelipe/go-feijoada/schema-validator/config"
+)
+
+const avroUser = `{"type": "record", "name": "User", "fields": [
+	{"name": "name", "type": "string"},
+	{"name": "email", "type": ["null", "string"], "default": null}
+]}`
+
+// protobufOrder is an orders.Order message with a required id, in proto2 so required fields are checked, and an amount
+func protobufOrder(t *testing.T) (string, *descriptorpb.FileDescriptorSet) {
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:    proto.String("orders.proto"),
+		Package: proto.String("orders"),
+		Syntax:  proto.String("proto2"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Order"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("id"), JsonName: proto.String("id"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_REQUIRED.Enum()},
+				{Name: proto.String("total_amount"), JsonName: proto.String("totalAmount"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_DOUBLE.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+			},
+		}},
+	}}}
+	data, err := proto.Marshal(set)
+	require.NoError(t, err)
+
+	doc, err := json.Marshal(protobufDocument{DescriptorSet: data, Message: "orders.Order"})
+	require.NoError(t, err)
+	return string(doc), set
+}
+
+// newTypedValidator fetches the given schemas from a schema-repository serving them with their type
+func newTypedValidator(t *testing.T, schemaType string, schemas map[string]string) *validator {
+	typed := make(map[string]string, len(schemas))
+	for path, schema := range schemas {
+		// newRepositoryServer wraps the schema in {"schema": ...}, the type is added as a second member
+		typed[path] = schema + `, "type": "` + schemaType + `"`
+	}
+	server, _ := newRepositoryServer(t, typed)
+	return New(config.Config{DefaultBaseURI: server.URL})
+}
+
+func TestValidator_Avro(t *testing.T) {
+	v := newTypedValidator(t, SchemaTypeAvro, map[string]string{"/schemas/user/1.0.0": avroUser})
+	uri := v.cfg.DefaultBaseURI + "/schemas/user/1.0.0"
+
+	codec, err := goavro.NewCodec(avroUser)
+	require.NoError(t, err)
+	binary, err := codec.BinaryFromNative(nil, map[string]any{"name": "Ana", "email": goavro.Union("string", "ana@example.com")})
+	require.NoError(t, err)
+
+	t.Run("binary", func(t *testing.T) {
+		canonical, report, err := v.Decode(uri, "avro/binary", binary)
+		require.NoError(t, err)
+		assert.True(t, report.Valid)
+		// Unions are not wrapped in an object naming their type
+		assert.JSONEq(t, `{"name": "Ana", "email": "ana@example.com"}`, string(canonical))
+	})
+
+	t.Run("truncated binary", func(t *testing.T) {
+		canonical, report, err := v.Decode(uri, ContentTypeAvro, binary[:4])
+		require.NoError(t, err)
+		assert.False(t, report.Valid)
+		assert.Nil(t, canonical)
+		require.Len(t, report.Errors, 1)
+		assert.Equal(t, SchemaTypeAvro, report.Errors[0].Keyword)
+		assert.Equal(t, uri+"#", report.Errors[0].SchemaLocation)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		report, err := v.Validate(uri, []byte(`{"name": "Ana", "email": null}`))
+		require.NoError(t, err)
+		assert.True(t, report.Valid)
+
+		report, err = v.Validate(uri, map[string]any{"name": 42})
+		require.NoError(t, err)
+		assert.False(t, report.Valid)
+	})
+
+	t.Run("protobuf payload", func(t *testing.T) {
+		_, _, err := v.Decode(uri, ContentTypeProtobuf, binary)
+		assert.ErrorIs(t, err, ErrUnsupportedContentType)
+	})
+}
+
+func TestValidator_Protobuf(t *testing.T) {
+	doc, set := protobufOrder(t)
+	v := newTypedValidator(t, SchemaTypeProtobuf, map[string]string{"/schemas/order/1.0.0": doc})
+	uri := v.cfg.DefaultBaseURI + "/schemas/order/1.0.0"
+
+	md, err := newProtobufSchema([]byte(doc))
+	require.NoError(t, err)
+	msg := dynamicpb.NewMessage(md.(protobufSchema).message)
+	msg.Set(msg.Descriptor().Fields().ByName("id"), protoreflect.ValueOf("o-1"))
+	msg.Set(msg.Descriptor().Fields().ByName("total_amount"), protoreflect.ValueOf(12.5))
+	binary, err := proto.Marshal(msg)
+	require.NoError(t, err)
+
+	t.Run("binary", func(t *testing.T) {
+		canonical, report, err := v.Decode(uri, "application/x-protobuf; messageType=orders.Order", binary)
+		require.NoError(t, err)
+		assert.True(t, report.Valid)
+		assert.Equal(t, `{"id":"o-1","totalAmount":12.5}`, string(canonical))
+	})
+
+	t.Run("missing required field", func(t *testing.T) {
+		msg.Clear(msg.Descriptor().Fields().ByName("id"))
+		partial, err := proto.MarshalOptions{AllowPartial: true}.Marshal(msg)
+		require.NoError(t, err)
+
+		_, report, err := v.Decode(uri, ContentTypeProtobuf, partial)
+		require.NoError(t, err)
+		assert.False(t, report.Valid)
+		assert.Equal(t, SchemaTypeProtobuf, report.Errors[0].Keyword)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		report, err := v.Validate(uri, []byte(`{"id": "o-1", "totalAmount": 3}`))
+		require.NoError(t, err)
+		assert.True(t, report.Valid)
+
+		report, err = v.Validate(uri, []byte(`{"id": "o-1", "discount": 3}`))
+		require.NoError(t, err)
+		assert.False(t, report.Valid)
+	})
+
+	t.Run("unknown message", func(t *testing.T) {
+		data, err := proto.Marshal(set)
+		require.NoError(t, err)
+		doc, err := json.Marshal(protobufDocument{DescriptorSet: data, Message: "orders.Refund"})
+		require.NoError(t, err)
+		_, err = newProtobufSchema(doc)
+		assert.ErrorContains(t, err, "orders.Refund")
+	})
+}
+
+func TestValidator_DecodeJSON(t *testing.T) {
+	server, _ := newRepositoryServer(t, map[string]string{"/schemas/user/1.0.0": `{"type": "object", "required": ["name"]}`})
+	v := New(config.Config{DefaultBaseURI: server.URL})
+	uri := server.URL + "/schemas/user/1.0.0"
+
+	payload := []byte(`{"name": "Ana"}`)
+	canonical, report, err := v.Decode(uri, "application/json; charset=utf-8", payload)
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, payload, []byte(canonical))
+
+	canonical, report, err = v.Decode(uri, "", []byte(`{}`))
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	assert.Nil(t, canonical)
+
+	_, _, err = v.Decode(uri, ContentTypeAvro, payload)
+	assert.ErrorIs(t, err, ErrUnsupportedContentType)
+}
+
+func TestUnwrap(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		schemaType string
+		schema     string
+	}{
+		{name: "schema-repository", body: `{"schema": {"type": "object"}}`, schemaType: SchemaTypeJSONSchema, schema: `{"type": "object"}`},
+		{name: "typed", body: `{"type": "avro", "schema": "string"}`, schemaType: SchemaTypeAvro, schema: `"string"`},
+		{name: "plain schema", body: `{"type": "object"}`, schemaType: SchemaTypeJSONSchema, schema: `{"type": "object"}`},
+		{name: "plain schema with a schema property", body: `{"type": "object", "schema": {}}`, schemaType: SchemaTypeJSONSchema, schema: `{"type": "object", "schema": {}}`},
+		{name: "unknown type", body: `{"type": "xsd", "schema": "<xs:schema/>"}`, schemaType: "xsd", schema: `"<xs:schema/>"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := unwrap([]byte(tt.body))
+			assert.Equal(t, tt.schemaType, doc.schemaType)
+			assert.JSONEq(t, tt.schema, string(doc.schema))
+		})
+	}
+}
+
+func TestNormalizeContentType(t *testing.T) {
+	assert.Equal(t, ContentTypeJSON, normalizeContentType(""))
+	assert.Equal(t, ContentTypeJSON, normalizeContentType("Application/JSON; charset=utf-8"))
+	assert.Equal(t, ContentTypeAvro, normalizeContentType("avro/binary"))
+	assert.Equal(t, ContentTypeProtobuf, normalizeContentType("application/protobuf"))
+	assert.Equal(t, "text/csv", normalizeContentType("text/csv"))
+}
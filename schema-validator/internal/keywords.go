@@ -0,0 +1,266 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mfelipe/go-feijoada/utils/vocabulary"
+)
+
+// keywordSchema checks the custom keywords of a JSON Schema, which the compiler ignores. They are evaluated in the
+// subschemas applying to the whole value or to its members: $ref to a JSON pointer of the same document, allOf,
+// properties, patternProperties, additionalProperties, prefixItems, items and additionalItems. Subschemas only
+// applying conditionally, such as those of anyOf or if, are not evaluated.
+type keywordSchema struct {
+	checks               []keywordCheck
+	ref                  *keywordSchema
+	allOf                []*keywordSchema
+	properties           map[string]*keywordSchema
+	patternProperties    []patternKeywordSchema
+	additionalProperties *keywordSchema
+	prefixItems          []*keywordSchema
+	items                *keywordSchema
+}
+
+type keywordCheck struct {
+	keyword string
+	// location is the JSON pointer of the keyword in its document
+	location string
+	check    vocabulary.Check
+}
+
+type patternKeywordSchema struct {
+	pattern *regexp.Regexp
+	schema  *keywordSchema
+}
+
+// keywordCompiler compiles the subschemas of a document, each JSON pointer once so recursive references terminate
+type keywordCompiler struct {
+	keywords map[string]vocabulary.Keyword
+	doc      any
+	compiled map[string]*keywordSchema
+	found    bool
+}
+
+// compileKeywords returns the checks of the custom keywords used by the schema at the JSON pointer of uri, nil when it
+// uses none. Keywords with invalid values fail the compilation
+func compileKeywords(keywords map[string]vocabulary.Keyword, uri string, schema []byte) (*keywordSchema, error) {
+	_, pointer, _ := strings.Cut(uri, "#")
+	if len(keywords) == 0 || (pointer != "" && !strings.HasPrefix(pointer, "/")) {
+		return nil, nil
+	}
+
+	doc, err := decodeJSON(schema)
+	if err != nil {
+		return nil, err
+	}
+	c := &keywordCompiler{keywords: keywords, doc: doc, compiled: make(map[string]*keywordSchema)}
+	root, err := c.compileRef(pointer)
+	if err != nil || !c.found {
+		return nil, err
+	}
+	return root, nil
+}
+
+// compileRef compiles the subschema at a JSON pointer of the document. Missing subschemas are left to the compiler
+func (c *keywordCompiler) compileRef(pointer string) (*keywordSchema, error) {
+	value, ok := resolvePointer(c.doc, pointer)
+	if !ok {
+		return nil, nil
+	}
+	return c.compile(pointer, value)
+}
+
+func (c *keywordCompiler) compile(pointer string, value any) (*keywordSchema, error) {
+	if s, ok := c.compiled[pointer]; ok {
+		return s, nil
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	s := &keywordSchema{}
+	c.compiled[pointer] = s
+
+	for _, name := range slices.Sorted(maps.Keys(c.keywords)) {
+		kv, ok := m[name]
+		if !ok {
+			continue
+		}
+		location := pointer + "/" + escapePointer(name)
+		check, err := c.keywords[name](kv)
+		if err != nil {
+			return nil, fmt.Errorf("keyword %s at %s: %w", name, location, err)
+		}
+		c.found = true
+		s.checks = append(s.checks, keywordCheck{keyword: name, location: location, check: check})
+	}
+
+	var err error
+	if ref, ok := m["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+		if s.ref, err = c.compileRef(strings.TrimPrefix(ref, "#")); err != nil {
+			return nil, err
+		}
+	}
+	if s.allOf, err = c.compileList(pointer+"/allOf", m["allOf"]); err != nil {
+		return nil, err
+	}
+	if properties, ok := m["properties"].(map[string]any); ok {
+		s.properties = make(map[string]*keywordSchema, len(properties))
+		for name, sub := range properties {
+			if s.properties[name], err = c.compile(pointer+"/properties/"+escapePointer(name), sub); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if patterns, ok := m["patternProperties"].(map[string]any); ok {
+		for _, pattern := range slices.Sorted(maps.Keys(patterns)) {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				// Reported by the compiler
+				continue
+			}
+			ps, err := c.compile(pointer+"/patternProperties/"+escapePointer(pattern), patterns[pattern])
+			if err != nil {
+				return nil, err
+			}
+			s.patternProperties = append(s.patternProperties, patternKeywordSchema{pattern: re, schema: ps})
+		}
+	}
+	if s.additionalProperties, err = c.compile(pointer+"/additionalProperties", m["additionalProperties"]); err != nil {
+		return nil, err
+	}
+
+	// Before draft 2020-12, an items array is what prefixItems is, and additionalItems what items is
+	prefixItems, rest := m["prefixItems"], m["items"]
+	if list, ok := rest.([]any); ok {
+		if s.prefixItems, err = c.compileList(pointer+"/items", list); err != nil {
+			return nil, err
+		}
+		s.items, err = c.compile(pointer+"/additionalItems", m["additionalItems"])
+		return s, err
+	}
+	if s.prefixItems, err = c.compileList(pointer+"/prefixItems", prefixItems); err != nil {
+		return nil, err
+	}
+	s.items, err = c.compile(pointer+"/items", rest)
+	return s, err
+}
+
+func (c *keywordCompiler) compileList(pointer string, value any) ([]*keywordSchema, error) {
+	list, ok := value.([]any)
+	if !ok {
+		return nil, nil
+	}
+	schemas := make([]*keywordSchema, len(list))
+	for i, sub := range list {
+		var err error
+		if schemas[i], err = c.compile(pointer+"/"+strconv.Itoa(i), sub); err != nil {
+			return nil, err
+		}
+	}
+	return schemas, nil
+}
+
+// validate adds to the report the failed checks of value, located at instancePath. Schemas reached again on the same
+// value through recursive references are skipped
+func (s *keywordSchema) validate(r *Report, base, instancePath string, value any, visited map[*keywordSchema]bool) {
+	if s == nil || visited[s] {
+		return
+	}
+	visited[s] = true
+	defer delete(visited, s)
+
+	for _, c := range s.checks {
+		if err := c.check(value); err != nil {
+			r.Errors = append(r.Errors, ValidationError{
+				InstanceLocation: instancePath,
+				Keyword:          c.keyword,
+				SchemaLocation:   base + "#" + c.location,
+				Message:          err.Error(),
+			})
+		}
+	}
+
+	s.ref.validate(r, base, instancePath, value, visited)
+	for _, sub := range s.allOf {
+		sub.validate(r, base, instancePath, value, visited)
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		// Members are evaluated in order, so reports are stable
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			member := v[name]
+			path, matched := instancePath+"/"+escapePointer(name), false
+			if sub, ok := s.properties[name]; ok {
+				matched = true
+				sub.validate(r, base, path, member, map[*keywordSchema]bool{})
+			}
+			for _, p := range s.patternProperties {
+				if p.pattern.MatchString(name) {
+					matched = true
+					p.schema.validate(r, base, path, member, map[*keywordSchema]bool{})
+				}
+			}
+			if !matched {
+				s.additionalProperties.validate(r, base, path, member, map[*keywordSchema]bool{})
+			}
+		}
+	case []any:
+		for i, item := range v {
+			sub := s.items
+			if i < len(s.prefixItems) {
+				sub = s.prefixItems[i]
+			}
+			sub.validate(r, base, instancePath+"/"+strconv.Itoa(i), item, map[*keywordSchema]bool{})
+		}
+	}
+}
+
+// decodeJSON decodes numbers as json.Number, as keywords expect
+func decodeJSON(data []byte) (any, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// resolvePointer returns the value at a JSON pointer of doc
+func resolvePointer(doc any, pointer string) (any, bool) {
+	if pointer == "" {
+		return doc, true
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := doc.(type) {
+		case map[string]any:
+			var ok bool
+			if doc, ok = v[token]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			doc = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return doc, true
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
@@ -0,0 +1,191 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-validator/config"
+	"github.com/mfelipe/go-feijoada/utils/vocabulary"
+)
+
+const paymentSchema = `{
+	"type": "object",
+	"properties": {
+		"currency": {"type": "string", "format": "iso4217"},
+		"phone": {"type": "string", "format": "e164"},
+		"orderId": {"type": "string", "format": "order-id"},
+		"amount": {"$ref": "#/$defs/amount"},
+		"installments": {"type": "array", "items": {"$ref": "#/$defs/amount"}}
+	},
+	"$defs": {
+		"amount": {"type": "number", "maxDecimals": 2}
+	}
+}`
+
+func TestValidator_Formats(t *testing.T) {
+	cfg := config.Config{
+		DefaultBaseURI: "http://localhost:8080",
+		Vocabulary:     &vocabulary.Config{Formats: map[string]string{"order-id": `^ord_[0-9a-z]{8}$`}},
+	}
+	uri := "http://localhost:8080/schemas/payment/1.0.0"
+	payload := []byte(`{"currency": "XYZ", "phone": "11912345678", "orderId": "ORD-1"}`)
+
+	t.Run("annotations", func(t *testing.T) {
+		v := New(cfg)
+		require.NoError(t, v.AddSchema(uri, json.RawMessage(paymentSchema)))
+
+		report, err := v.Validate(uri, payload)
+		require.NoError(t, err)
+		assert.True(t, report.Valid)
+	})
+
+	t.Run("asserted", func(t *testing.T) {
+		v := New(cfg)
+		v.SetAssertFormat(true)
+		require.NoError(t, v.AddSchema(uri, json.RawMessage(paymentSchema)))
+
+		report, err := v.Validate(uri, payload)
+		require.NoError(t, err)
+		assert.False(t, report.Valid)
+		var locations []string
+		for _, e := range report.Errors {
+			assert.Equal(t, "format", e.Keyword)
+			locations = append(locations, e.InstanceLocation)
+		}
+		assert.ElementsMatch(t, []string{"/currency", "/phone", "/orderId"}, locations)
+
+		report, err = v.Validate(uri, []byte(`{"currency": "BRL", "phone": "+5511912345678", "orderId": "ord_1a2b3c4d"}`))
+		require.NoError(t, err)
+		assert.True(t, report.Valid)
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		v := New(config.Config{DefaultBaseURI: cfg.DefaultBaseURI, Vocabulary: &vocabulary.Config{AssertFormat: true}})
+		v.RegisterFormat("order-id", func(value any) bool { return value == "ord_1a2b3c4d" })
+		require.NoError(t, v.AddSchema(uri, json.RawMessage(paymentSchema)))
+
+		report, err := v.Validate(uri, map[string]any{"orderId": "ord_1a2b3c4d"})
+		require.NoError(t, err)
+		assert.True(t, report.Valid)
+	})
+}
+
+func TestValidator_Keywords(t *testing.T) {
+	v := New(config.Config{DefaultBaseURI: "http://localhost:8080"})
+	uri := "http://localhost:8080/schemas/payment/1.0.0"
+	require.NoError(t, v.AddSchema(uri, json.RawMessage(paymentSchema)))
+
+	tests := []struct {
+		name   string
+		data   any
+		errors []ValidationError
+	}{
+		{name: "valid", data: []byte(`{"amount": 10.25, "installments": [5, 5.25]}`)},
+		{name: "valid decoded", data: map[string]any{"amount": 10.5}},
+		{
+			name: "through a reference",
+			data: []byte(`{"amount": 10.255}`),
+			errors: []ValidationError{{
+				InstanceLocation: "/amount",
+				Keyword:          "maxDecimals",
+				SchemaLocation:   uri + "#/$defs/amount/maxDecimals",
+				Message:          "10.255 has more than 2 decimal places",
+			}},
+		},
+		{
+			name: "items",
+			data: []byte(`{"installments": [5, 5.125, 1.001]}`),
+			errors: []ValidationError{
+				{InstanceLocation: "/installments/1", Keyword: "maxDecimals", SchemaLocation: uri + "#/$defs/amount/maxDecimals", Message: "5.125 has more than 2 decimal places"},
+				{InstanceLocation: "/installments/2", Keyword: "maxDecimals", SchemaLocation: uri + "#/$defs/amount/maxDecimals", Message: "1.001 has more than 2 decimal places"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := v.Validate(uri, tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.errors) == 0, report.Valid)
+			assert.Equal(t, tt.errors, report.Errors)
+		})
+	}
+
+	t.Run("standard and custom failures", func(t *testing.T) {
+		report, err := v.Validate(uri, []byte(`{"amount": "10", "installments": [0.001]}`))
+		require.NoError(t, err)
+		assert.False(t, report.Valid)
+		// Custom keywords are reported after the standard ones
+		require.Len(t, report.Errors, 3)
+		assert.Equal(t, "$ref", report.Errors[0].Keyword)
+		assert.Equal(t, "type", report.Errors[1].Keyword)
+		assert.Equal(t, "maxDecimals", report.Errors[2].Keyword)
+	})
+
+	t.Run("invalid keyword value", func(t *testing.T) {
+		err := v.AddSchema(uri+"-broken", json.RawMessage(`{"properties": {"amount": {"maxDecimals": "two"}}}`))
+		assert.ErrorContains(t, err, "keyword maxDecimals at /properties/amount/maxDecimals")
+	})
+
+	t.Run("registered keyword", func(t *testing.T) {
+		v.RegisterKeyword("currencyOf", func(value any) (vocabulary.Check, error) {
+			property, ok := value.(string)
+			if !ok {
+				return nil, errors.New("must be a property name")
+			}
+			return func(value any) error {
+				if m, ok := value.(map[string]any); ok && m[property] == nil {
+					return errors.New(property + " is required along with amounts")
+				}
+				return nil
+			}, nil
+		})
+		require.NoError(t, v.AddSchema(uri+"-priced", json.RawMessage(`{"currencyOf": "currency", "$ref": "`+uri+`"}`)))
+
+		report, err := v.Validate(uri+"-priced", []byte(`{"amount": 1.5}`))
+		require.NoError(t, err)
+		assert.False(t, report.Valid)
+		require.Len(t, report.Errors, 1)
+		assert.Equal(t, "currencyOf", report.Errors[0].Keyword)
+		assert.Equal(t, "currency is required along with amounts", report.Errors[0].Message)
+	})
+}
+
+func TestCompileKeywords(t *testing.T) {
+	vocab, err := vocabulary.New(vocabulary.Config{})
+	require.NoError(t, err)
+	keywords := vocab.Keywords()
+
+	t.Run("unused", func(t *testing.T) {
+		s, err := compileKeywords(keywords, "", []byte(`{"type": "number"}`))
+		require.NoError(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		s, err := compileKeywords(keywords, "", []byte(`{
+			"properties": {"value": {"maxDecimals": 1}, "children": {"items": {"$ref": "#"}}}
+		}`))
+		require.NoError(t, err)
+		instance, err := decodeJSON([]byte(`{"value": 1.5, "children": [{"value": 2.25, "children": [{"value": 3.125}]}]}`))
+		require.NoError(t, err)
+
+		var r Report
+		s.validate(&r, "s", "", instance, map[*keywordSchema]bool{})
+		// Members are evaluated in order, depth first
+		require.Len(t, r.Errors, 2)
+		assert.Equal(t, "/children/0/children/0/value", r.Errors[0].InstanceLocation)
+		assert.Equal(t, "/children/0/value", r.Errors[1].InstanceLocation)
+	})
+
+	t.Run("fragment", func(t *testing.T) {
+		s, err := compileKeywords(keywords, "s#/$defs/amount", []byte(`{"$defs": {"amount": {"maxDecimals": 0}}}`))
+		require.NoError(t, err)
+		require.NotNil(t, s)
+		assert.Equal(t, "/$defs/amount/maxDecimals", s.checks[0].location)
+	})
+}
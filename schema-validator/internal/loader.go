@@ -0,0 +1,193 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/kaptinlin/jsonschema"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/mfelipe/go-feijoada/schema-validator/config"
+	utilshttp "github.com/mfelipe/go-feijoada/utils/http"
+	"github.com/mfelipe/go-feijoada/utils/schemabody"
+)
+
+// ErrSchemaNotFound is returned when a schema is not known by the validator nor by schema-repository
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// ErrOffline is returned when listing schema-repository with an offline validator
+var ErrOffline = errors.New("offline validator can't list schema-repository")
+
+// httpLoader fetches schemas with retries and pooled connections. Concurrent fetches of the same URL are merged with
+// single flight, and schemas not found are remembered for a while, so a burst of records referencing an unknown
+// schema turns into a single request.
+type httpLoader struct {
+	client      *http.Client
+	group       singleflight.Group
+	notFoundTTL time.Duration
+	now         func() time.Time
+	// offline loaders never send requests, every schema being not found
+	offline bool
+
+	mu       sync.Mutex
+	notFound map[string]time.Time
+}
+
+func newHTTPLoader(cfg config.Loader) *httpLoader {
+	client := retryablehttp.NewClient()
+	client.HTTPClient.Transport = utilshttp.CustomPooledTransport()
+	client.HTTPClient.Timeout = cfg.Timeout
+	client.RetryMax = cfg.RetryMax
+	if cfg.RetryWaitMin > 0 {
+		client.RetryWaitMin = cfg.RetryWaitMin
+	}
+	if cfg.RetryWaitMax > 0 {
+		client.RetryWaitMax = cfg.RetryWaitMax
+	}
+	client.Logger = nil
+
+	return &httpLoader{
+		client:      client.StandardClient(),
+		notFoundTTL: cfg.NotFoundTTL,
+		now:         time.Now,
+		notFound:    make(map[string]time.Time),
+	}
+}
+
+// document is a fetched schema, with its type
+type document struct {
+	schemaType string
+	schema     []byte
+}
+
+// fetch retrieves the schema served at url, failing with ErrSchemaNotFound on 404 responses. Schemas served by
+// schema-repository are unwrapped from their {"type": ..., "schema": ...} body.
+func (l *httpLoader) fetch(url string) (document, error) {
+	if l.offline || l.isNotFound(url) {
+		return document{}, fmt.Errorf("%w: %s", ErrSchemaNotFound, url)
+	}
+
+	// The body is read inside the single flight, so every caller gets the whole content
+	data, err, _ := l.group.Do(url, func() (any, error) {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := l.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", jsonschema.ErrFailedToFetch, err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, err
+			}
+			return unwrap(body), nil
+		case http.StatusNotFound:
+			l.setNotFound(url)
+			return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, url)
+		default:
+			return nil, fmt.Errorf("%w: %d", jsonschema.ErrInvalidHTTPStatusCode, resp.StatusCode)
+		}
+	})
+	if err != nil {
+		return document{}, err
+	}
+
+	return data.(document), nil
+}
+
+// list retrieves the schema versions registered in the schema-repository served at baseURI
+func (l *httpLoader) list(ctx context.Context, baseURI string) ([]schemaVersion, error) {
+	if l.offline {
+		return nil, ErrOffline
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURI, "/")+"/schemas", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("listing schemas: %w: %d", jsonschema.ErrInvalidHTTPStatusCode, resp.StatusCode)
+	}
+
+	var body struct {
+		Schemas []schemaVersion `json:"schemas"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("listing schemas: %w", err)
+	}
+	return body.Schemas, nil
+}
+
+// schemaVersion is a schema version listed by schema-repository
+type schemaVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// unwrap extracts the schema and its type of a schema-repository response body, see schemabody.Unwrap
+func unwrap(body []byte) document {
+	schemaType, schema := schemabody.Unwrap(body)
+	return document{schemaType: schemaType, schema: schema}
+}
+
+// load is the compiler loader, used to resolve references to other schemas, which must be JSON Schemas
+func (l *httpLoader) load(url string) (io.ReadCloser, error) {
+	doc, err := l.fetch(url)
+	if err != nil {
+		return nil, err
+	}
+	if doc.schemaType != SchemaTypeJSONSchema {
+		return nil, fmt.Errorf("%w: %s referenced from a JSON schema: %s", ErrUnsupportedSchemaType, doc.schemaType, url)
+	}
+	return io.NopCloser(bytes.NewReader(doc.schema)), nil
+}
+
+func (l *httpLoader) isNotFound(url string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until, ok := l.notFound[url]
+	if ok && l.now().After(until) {
+		delete(l.notFound, url)
+		return false
+	}
+	return ok
+}
+
+func (l *httpLoader) setNotFound(url string) {
+	if l.notFoundTTL <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.notFound[url] = l.now().Add(l.notFoundTTL)
+}
+
+// forget clears a not found entry, for schemas added locally
+func (l *httpLoader) forget(url string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.notFound, url)
+}
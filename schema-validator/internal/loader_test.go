@@ -0,0 +1,218 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-validator/config"
+)
+
+// schemaServer serves a schema under /schemas/user/1.0.0, and 404 for any other path. Its content and status can be
+// changed during the test.
+type schemaServer struct {
+	*httptest.Server
+	requests atomic.Int32
+	mu       sync.Mutex
+	schema   string
+	status   int
+	// delay holds responses, so concurrent requests overlap
+	delay time.Duration
+}
+
+func newSchemaServer(t *testing.T, schema string) *schemaServer {
+	s := &schemaServer{schema: schema, status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		time.Sleep(s.delay)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if r.URL.Path != "/schemas/user/1.0.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(s.status)
+		_, _ = w.Write([]byte(s.schema))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *schemaServer) set(status int, schema string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+	s.schema = schema
+}
+
+// clock is a manually advanced time source
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newLoaderValidator(baseURI string, loaderCfg config.Loader) (*validator, *clock) {
+	c := &clock{now: time.Now()}
+	v := New(config.Config{DefaultBaseURI: baseURI, Loader: &loaderCfg})
+	v.now = c.Now
+	v.loader.now = c.Now
+	return v, c
+}
+
+func TestValidator_LoaderFetchesOnce(t *testing.T) {
+	server := newSchemaServer(t, `{"type": "string"}`)
+	server.delay = 50 * time.Millisecond
+	v, _ := newLoaderValidator(server.URL, config.Loader{RetryMax: 1})
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := v.Validate(server.URL+"/schemas/user/1.0.0", "value")
+			assert.NoError(t, err)
+			assert.True(t, result.Valid)
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 1, server.requests.Load())
+}
+
+func TestValidator_LoaderRepositoryResponse(t *testing.T) {
+	// schema-repository wraps the schemas it serves in {"schema": ...}
+	server := newSchemaServer(t, `{"schema": {"type": "object", "properties": {"id": {"type": "integer"}}, "required": ["id"]}}`)
+	v, _ := newLoaderValidator(server.URL, config.Loader{})
+	uri := server.URL + "/schemas/user/1.0.0"
+
+	result, err := v.Validate(uri, json.RawMessage(`{"id": 1}`))
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	for _, payload := range []string{`{"name": "Ana"}`, `{"id": "1"}`, `"value"`} {
+		result, err = v.Validate(uri, json.RawMessage(payload))
+		require.NoError(t, err)
+		assert.False(t, result.Valid, payload)
+	}
+}
+
+func TestValidator_LoaderUnsupportedType(t *testing.T) {
+	server := newSchemaServer(t, `{"type": "xsd", "schema": "<xs:schema/>"}`)
+	v, _ := newLoaderValidator(server.URL, config.Loader{})
+
+	_, err := v.Validate(server.URL+"/schemas/user/1.0.0", json.RawMessage(`{"id": 1}`))
+	assert.ErrorIs(t, err, ErrUnsupportedSchemaType)
+}
+
+func TestValidator_LoaderNegativeCache(t *testing.T) {
+	server := newSchemaServer(t, `{"type": "string"}`)
+	v, c := newLoaderValidator(server.URL, config.Loader{RetryMax: 3, RetryWaitMin: time.Millisecond, NotFoundTTL: time.Minute})
+	unknown := server.URL + "/schemas/unknown/1.0.0"
+
+	// 404 responses are not retried, and are remembered for a while
+	for range 5 {
+		_, err := v.Validate(unknown, "value")
+		assert.ErrorIs(t, err, ErrSchemaNotFound)
+	}
+	assert.EqualValues(t, 1, server.requests.Load())
+
+	c.Advance(2 * time.Minute)
+	_, err := v.Validate(unknown, "value")
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+	assert.EqualValues(t, 2, server.requests.Load())
+
+	// Adding the schema locally overrides the negative cache
+	require.NoError(t, v.AddSchema(unknown, []byte(`{"type": "string"}`)))
+	_, err = v.Validate(unknown, "value")
+	assert.NoError(t, err)
+}
+
+func TestValidator_LoaderRetries(t *testing.T) {
+	server := newSchemaServer(t, `{"type": "string"}`)
+	server.set(http.StatusServiceUnavailable, "")
+	v, _ := newLoaderValidator(server.URL, config.Loader{RetryMax: 2, RetryWaitMin: time.Millisecond, RetryWaitMax: time.Millisecond})
+
+	_, err := v.Validate(server.URL+"/schemas/user/1.0.0", "value")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrSchemaNotFound)
+	assert.EqualValues(t, 3, server.requests.Load())
+
+	// Failures are not cached
+	server.set(http.StatusOK, `{"type": "string"}`)
+	_, err = v.Validate(server.URL+"/schemas/user/1.0.0", "value")
+	assert.NoError(t, err)
+}
+
+func TestValidator_LoaderRefresh(t *testing.T) {
+	server := newSchemaServer(t, `{"type": "string"}`)
+	v, c := newLoaderValidator(server.URL, config.Loader{TTL: time.Minute, NotFoundTTL: time.Minute})
+	uri := server.URL + "/schemas/user/1.0.0"
+
+	result, err := v.Validate(uri, "value")
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	// Within the TTL, the schema is not fetched again
+	server.set(http.StatusOK, `{"type": "number"}`)
+	c.Advance(30 * time.Second)
+	result, err = v.Validate(uri, "value")
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.EqualValues(t, 1, server.requests.Load())
+
+	// Once expired, the stale schema is used while it is refreshed in the background
+	c.Advance(time.Minute)
+	result, err = v.Validate(uri, "value")
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Eventually(t, func() bool {
+		result, err = v.Validate(uri, "value")
+		return err == nil && !result.Valid
+	}, time.Second, 10*time.Millisecond)
+
+	// A schema deleted from the repository is dropped on refresh
+	server.set(http.StatusNotFound, "")
+	c.Advance(2 * time.Minute)
+	_, _ = v.Validate(uri, "value")
+	assert.Eventually(t, func() bool {
+		_, err = v.Validate(uri, "value")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+}
+
+func TestValidator_Offline(t *testing.T) {
+	server := newSchemaServer(t, `{"type": "string"}`)
+	v := New(config.Config{DefaultBaseURI: server.URL, Offline: true, Preload: &config.Preload{}})
+
+	_, err := v.Validate(server.URL+"/schemas/user/1.0.0", "value")
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+	assert.ErrorIs(t, v.Preload(context.Background()), ErrOffline)
+
+	// References to unknown schemas are not fetched either
+	err = v.AddSchema(server.URL+"/schemas/order/1.0.0", json.RawMessage(`{"$ref": "`+server.URL+`/schemas/user/1.0.0"}`))
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+	assert.ErrorContains(t, err, "/schemas/user/1.0.0")
+	assert.Zero(t, server.requests.Load())
+}
@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// Preload compiles the configured schemas ahead of their first use, so records don't wait for schema-repository.
+// Schemas are read from the local directory when configured, otherwise listed from schema-repository. A schema failing
+// to load doesn't prevent the others from being compiled, and every failure is returned.
+func (v *validator) Preload(ctx context.Context) error {
+	if v.cfg.Preload == nil {
+		return nil
+	}
+	if v.cfg.Preload.Dir != "" {
+		return v.preloadDir(ctx, v.cfg.Preload.Dir)
+	}
+	return v.preloadRepository(ctx)
+}
+
+func (v *validator) preloadRepository(ctx context.Context) error {
+	versions, err := v.loader.list(ctx, v.cfg.DefaultBaseURI)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sv := range versions {
+		if err = ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if !v.preloaded(sv.Name, sv.Version) {
+			continue
+		}
+
+		uri := v.schemaURI(sv.Name, sv.Version)
+		if _, err = v.getSchema(uri); err != nil {
+			errs = append(errs, fmt.Errorf("preloading %s: %w", uri, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// preloadDir compiles the JSON schema files of dir all at once, so references between them are resolved locally, leaving
+// out the files that aren't JSON schemas. Avro schema files are compiled on their own.
+func (v *validator) preloadDir(ctx context.Context, dir string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	schemas := make(map[string][]byte)
+	for _, f := range files {
+		if err = ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		name, version, schemaType, ok := parseFileName(f.Name())
+		if f.IsDir() || !ok || !v.preloaded(name, version) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if schemaType != SchemaTypeJSONSchema {
+			uri := v.schemaURI(name, version)
+			schema, err := engines[schemaType](data)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("preloading %s: %w", f.Name(), err))
+				continue
+			}
+			v.loader.forget(uri)
+			v.store(uri, schema, fingerprint(uri, data), time.Time{})
+			continue
+		}
+
+		// The batch fails on the first schema that doesn't parse, so each one is parsed beforehand and left out on failure
+		var doc jsonschema.Schema
+		if err = json.Unmarshal(data, &doc); err != nil {
+			errs = append(errs, fmt.Errorf("preloading %s: %w", f.Name(), err))
+			continue
+		}
+		if doc.ID == "" {
+			doc.ID = v.schemaURI(name, version)
+		}
+		schemas[doc.ID] = data
+	}
+
+	compiled, err := v.compiler.CompileBatch(schemas)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	keywords := v.vocabulary.Keywords()
+	for uri, schema := range compiled {
+		checks, err := compileKeywords(keywords, "", schemas[uri])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("preloading %s: %w", uri, err))
+			continue
+		}
+		v.loader.forget(uri)
+		v.store(uri, jsonSchema{schema: schema, keywords: checks}, fingerprint(uri, schemas[uri]), time.Time{})
+	}
+	return errors.Join(errs...)
+}
+
+// preloaded tells whether a schema version is selected by the preload configuration
+func (v *validator) preloaded(name, version string) bool {
+	selected := v.cfg.Preload.Schemas
+	return len(selected) == 0 || slices.Contains(selected, name) || slices.Contains(selected, name+"@"+version)
+}
+
+// schemaURI is where schema-repository serves a schema version
+func (v *validator) schemaURI(name, version string) string {
+	return fmt.Sprintf("%s/schemas/%s/%s", strings.TrimSuffix(v.cfg.DefaultBaseURI, "/"), name, version)
+}
+
+// schemaFileTypes are the schema types of the file extensions read by preloadDir
+var schemaFileTypes = map[string]string{
+	".json": SchemaTypeJSONSchema,
+	".avsc": SchemaTypeAvro,
+}
+
+// parseFileName splits <name>-<version>.json file names, as kept in schemas/schemas, and <name>-<version>.avsc ones
+func parseFileName(file string) (name, version, schemaType string, ok bool) {
+	ext := filepath.Ext(file)
+	schemaType, known := schemaFileTypes[ext]
+	base := strings.TrimSuffix(file, ext)
+	i := strings.LastIndex(base, "-")
+	if !known || i <= 0 || i == len(base)-1 {
+		return "", "", "", false
+	}
+	return base[:i], base[i+1:], schemaType, true
+}
@@ -0,0 +1,186 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-validator/config"
+)
+
+// newRepositoryServer mimics schema-repository, listing and serving the given schemas by path
+func newRepositoryServer(t *testing.T, schemas map[string]string) (*httptest.Server, *sync.Map) {
+	var requests sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count, _ := requests.LoadOrStore(r.URL.Path, new(int))
+		*count.(*int)++
+
+		if r.URL.Path == "/schemas" {
+			_, _ = w.Write([]byte(`{"schemas": [{"name": "user", "version": "1.0.0"}, {"name": "user", "version": "2.0.0"}, {"name": "order", "version": "1.0.0"}, {"name": "gone", "version": "1.0.0"}]}`))
+			return
+		}
+		schema, ok := schemas[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"schema": ` + schema + `}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func requestCount(requests *sync.Map, path string) int {
+	count, ok := requests.Load(path)
+	if !ok {
+		return 0
+	}
+	return *count.(*int)
+}
+
+func TestValidator_PreloadRepository(t *testing.T) {
+	schemas := map[string]string{
+		"/schemas/user/1.0.0":  `{"type": "string"}`,
+		"/schemas/user/2.0.0":  `{"type": "number"}`,
+		"/schemas/order/1.0.0": `{"type": "object"}`,
+	}
+
+	t.Run("every schema", func(t *testing.T) {
+		server, requests := newRepositoryServer(t, schemas)
+		v := New(config.Config{DefaultBaseURI: server.URL, Preload: &config.Preload{}})
+
+		// A schema listed but missing is reported, without preventing the others from loading
+		err := v.Preload(context.Background())
+		assert.ErrorIs(t, err, ErrSchemaNotFound)
+		assert.ErrorContains(t, err, "/schemas/gone/1.0.0")
+
+		for path := range schemas {
+			_, err = v.Validate(server.URL+path, "value")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, requestCount(requests, path), path)
+		}
+
+		// Schemas are unwrapped from schema-repository responses
+		result, err := v.Validate(server.URL+"/schemas/user/2.0.0", "value")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("subset", func(t *testing.T) {
+		server, requests := newRepositoryServer(t, schemas)
+		v := New(config.Config{DefaultBaseURI: server.URL, Preload: &config.Preload{Schemas: []string{"user@2.0.0", "order"}}})
+
+		require.NoError(t, v.Preload(context.Background()))
+		assert.Equal(t, 0, requestCount(requests, "/schemas/user/1.0.0"))
+		assert.Equal(t, 1, requestCount(requests, "/schemas/user/2.0.0"))
+		assert.Equal(t, 1, requestCount(requests, "/schemas/order/1.0.0"))
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		server, requests := newRepositoryServer(t, schemas)
+		v := New(config.Config{DefaultBaseURI: server.URL})
+
+		require.NoError(t, v.Preload(context.Background()))
+		assert.Equal(t, 0, requestCount(requests, "/schemas"))
+	})
+
+	t.Run("repository unavailable", func(t *testing.T) {
+		server, _ := newRepositoryServer(t, schemas)
+		server.Close()
+		v := New(config.Config{DefaultBaseURI: server.URL, Preload: &config.Preload{}})
+
+		assert.Error(t, v.Preload(context.Background()))
+	})
+}
+
+func TestValidator_PreloadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"address-1.0.0.json": `{"$id": "http://schema-repository:8080/schemas/address/1.0.0", "type": "object", "required": ["street"]}`,
+		"user-1.0.0.json":    `{"type": "object", "properties": {"address": {"$ref": "http://schema-repository:8080/schemas/address/1.0.0"}}}`,
+		"order-1.0.0.json":   `{"type": "object"}`,
+		"email-1.0.0.avsc":   `"string"`,
+		"README.md":          `# Schemas`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	// Nothing is fetched, references between files are resolved locally
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	v := New(config.Config{DefaultBaseURI: server.URL, Preload: &config.Preload{Dir: dir, Schemas: []string{"user", "address", "email"}}})
+	require.NoError(t, v.Preload(context.Background()))
+
+	// Schemas without $id are registered under the default base URI
+	result, err := v.Validate(server.URL+"/schemas/user/1.0.0", map[string]any{"address": map[string]any{}})
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+
+	result, err = v.Validate("http://schema-repository:8080/schemas/address/1.0.0", map[string]any{"street": "Main"})
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	// Avro schema files are registered under the default base URI
+	result, err = v.Validate(server.URL+"/schemas/email/1.0.0", []byte(`"someone@example.com"`))
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	v.mu.RLock()
+	_, ok := v.entries[server.URL+"/schemas/order/1.0.0"]
+	v.mu.RUnlock()
+	assert.False(t, ok, "schemas not selected are not preloaded")
+}
+
+func TestValidator_PreloadDirInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "address-1.0.0.json"), []byte(`{"type": "object", "required": ["street"]}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "user-1.0.0.json"), []byte(`{"type": 42}`), 0o644))
+
+	v := New(config.Config{DefaultBaseURI: "http://schema-repository:8080", Offline: true, Preload: &config.Preload{Dir: dir}})
+	err := v.Preload(context.Background())
+	assert.ErrorContains(t, err, "preloading user-1.0.0.json")
+
+	// The valid schema is still preloaded
+	result, err := v.Validate("http://schema-repository:8080/schemas/address/1.0.0", map[string]any{})
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+}
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		file       string
+		name       string
+		version    string
+		schemaType string
+		ok         bool
+	}{
+		{file: "user-1.0.0.json", name: "user", version: "1.0.0", schemaType: SchemaTypeJSONSchema, ok: true},
+		{file: "shipping-address-2.1.json", name: "shipping-address", version: "2.1", schemaType: SchemaTypeJSONSchema, ok: true},
+		{file: "user-2.0.0.avsc", name: "user", version: "2.0.0", schemaType: SchemaTypeAvro, ok: true},
+		{file: "user.json"},
+		{file: "-1.0.0.json"},
+		{file: "user-1.0.0.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			name, version, schemaType, ok := parseFileName(tt.file)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.version, version)
+			assert.Equal(t, tt.schemaType, schemaType)
+		})
+	}
+}
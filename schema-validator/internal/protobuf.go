@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// protobufDocument is how schema-repository stores protobuf schemas: a serialized FileDescriptorSet, base64 encoded in
+// JSON, with every imported file, and the full name of the message payloads are encoded with
+type protobufDocument struct {
+	DescriptorSet []byte `json:"descriptorSet"`
+	Message       string `json:"message"`
+}
+
+// protobufSchema is the descriptor of a protobuf message. JSON is read and written with the protobuf JSON mapping.
+type protobufSchema struct {
+	message protoreflect.MessageDescriptor
+}
+
+func newProtobufSchema(schema []byte) (compiledSchema, error) {
+	var doc protobufDocument
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return nil, fmt.Errorf("compiling protobuf schema: %w", err)
+	}
+	if doc.Message == "" {
+		return nil, errors.New("compiling protobuf schema: message is required")
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(doc.DescriptorSet, &set); err != nil {
+		return nil, fmt.Errorf("compiling protobuf schema: descriptorSet: %w", err)
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("compiling protobuf schema: descriptorSet: %w", err)
+	}
+
+	d, err := files.FindDescriptorByName(protoreflect.FullName(doc.Message))
+	if err != nil {
+		return nil, fmt.Errorf("compiling protobuf schema: message %s: %w", doc.Message, err)
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("compiling protobuf schema: %s is not a message", doc.Message)
+	}
+	return protobufSchema{message: md}, nil
+}
+
+func (s protobufSchema) validate(uri string, obj any) (*Report, error) {
+	data, ok := obj.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := protojson.Unmarshal(data, dynamicpb.NewMessage(s.message)); err != nil {
+		return decodeReport(uri, SchemaTypeProtobuf, err), nil
+	}
+	return &Report{Valid: true}, nil
+}
+
+// decode reads the wire format of the message. Required fields of proto2 messages are checked
+func (s protobufSchema) decode(uri, contentType string, payload []byte) (json.RawMessage, *Report, error) {
+	if contentType != ContentTypeProtobuf {
+		return nil, nil, fmt.Errorf("%w: %s payloads of a protobuf schema", ErrUnsupportedContentType, contentType)
+	}
+
+	msg := dynamicpb.NewMessage(s.message)
+	if err := proto.Unmarshal(payload, msg); err != nil {
+		return nil, decodeReport(uri, SchemaTypeProtobuf, err), nil
+	}
+
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	// protojson output is deliberately unstable, compacting it makes the same message always give the same JSON
+	var canonical bytes.Buffer
+	if err = json.Compact(&canonical, data); err != nil {
+		return nil, nil, err
+	}
+	return canonical.Bytes(), &Report{Valid: true}, nil
+}
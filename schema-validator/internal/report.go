@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/kaptinlin/jsonschema"
+)
+
+// Report is the outcome of validating a value against a schema
+type Report struct {
+	Valid  bool              `json:"valid"`
+	Errors []ValidationError `json:"errors,omitempty"`
+}
+
+// ValidationError is a single failed assertion of a schema
+type ValidationError struct {
+	// InstanceLocation is the JSON pointer of the failing value, empty for the root
+	InstanceLocation string `json:"instanceLocation"`
+	Keyword          string `json:"keyword"`
+	// SchemaLocation is the URI of the failing keyword, with its JSON pointer as fragment
+	SchemaLocation string `json:"schemaLocation"`
+	Message        string `json:"message"`
+	// Expected and Actual are set for keywords comparing the value with a constraint, such as type or minimum
+	Expected any `json:"expected,omitempty"`
+	Actual   any `json:"actual,omitempty"`
+}
+
+// aggregateKeywords only summarize the failures of subschemas, which are reported on their own
+var aggregateKeywords = map[string]bool{
+	"properties":           true,
+	"additionalProperties": true,
+	"patternProperties":    true,
+	"dependentSchemas":     true,
+	"propertyNames":        true,
+	"items":                true,
+	"prefixItems":          true,
+	"unevaluatedItems":     true,
+}
+
+// newReport flattens the evaluation result tree into the failing assertions, in evaluation order
+func newReport(schemaURI string, result *jsonschema.EvaluationResult) *Report {
+	base, _, _ := strings.Cut(schemaURI, "#")
+	r := &Report{Valid: result.IsValid()}
+	if !r.Valid {
+		r.collect(base, "", "", result)
+	}
+	return r
+}
+
+// collect adds the errors of result and of its details. Locations of details are relative to their parent.
+func (r *Report) collect(base, instancePath, evaluationPath string, result *jsonschema.EvaluationResult) {
+	instancePath += result.InstanceLocation
+	evaluationPath += result.EvaluationPath
+
+	invalidDetails := slices.ContainsFunc(result.Details, func(d *jsonschema.EvaluationResult) bool { return !d.IsValid() })
+	for _, key := range slices.Sorted(maps.Keys(result.Errors)) {
+		e := result.Errors[key]
+		if aggregateKeywords[e.Keyword] && invalidDetails {
+			continue
+		}
+
+		keyword, location := e.Keyword, evaluationPath+"/"+e.Keyword
+		if e.Code == "false_schema_mismatch" {
+			// The false schema of a keyword such as additionalProperties, reported as the keyword itself
+			keyword, _, _ = strings.Cut(strings.TrimPrefix(result.EvaluationPath, "/"), "/")
+			location = strings.TrimSuffix(evaluationPath, result.EvaluationPath) + "/" + keyword
+		}
+
+		r.Errors = append(r.Errors, ValidationError{
+			InstanceLocation: instancePath,
+			Keyword:          keyword,
+			SchemaLocation:   base + "#" + location,
+			Message:          e.Error(),
+			Expected:         expected(e),
+			Actual:           actual(e),
+		})
+	}
+
+	for _, d := range result.Details {
+		if !d.IsValid() {
+			r.collect(base, instancePath, evaluationPath, d)
+		}
+	}
+}
+
+// expected is the constraint of the failing keyword, named after the keyword in the error parameters
+func expected(e *jsonschema.EvaluationError) any {
+	if v, ok := e.Params["expected"]; ok {
+		return v
+	}
+	return e.Params[snakeCase(e.Keyword)]
+}
+
+// actual is the failing value, or its measure for length and size keywords
+func actual(e *jsonschema.EvaluationError) any {
+	for _, p := range []string{"received", "value", "length"} {
+		if v, ok := e.Params[p]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func snakeCase(keyword string) string {
+	var b strings.Builder
+	for _, c := range keyword {
+		if c >= 'A' && c <= 'Z' {
+			b.WriteByte('_')
+			c += 'a' - 'A'
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
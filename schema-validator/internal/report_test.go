@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schema-validator/config"
+)
+
+func TestValidator_Report(t *testing.T) {
+	v := New(config.Config{DefaultBaseURI: "http://localhost:8080"})
+	uri := "http://localhost:8080/schemas/user/1.0.0"
+	require.NoError(t, v.AddSchema(uri, json.RawMessage(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string"},
+			"age": {"type": "integer", "minimum": 0},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"address": {"type": "object", "properties": {"zip": {"type": "string", "maxLength": 3}}},
+			"kind": {"enum": ["a", "b"]}
+		},
+		"additionalProperties": false
+	}`)))
+
+	var obj any
+	require.NoError(t, json.Unmarshal([]byte(`{"age": -1, "tags": ["ok", 3], "address": {"zip": "12345"}, "kind": "c", "extra": true}`), &obj))
+	report, err := v.Validate(uri, obj)
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+
+	byLocation := make(map[string]ValidationError)
+	for _, e := range report.Errors {
+		byLocation[e.InstanceLocation+" "+e.Keyword] = e
+		assert.NotEmpty(t, e.Message)
+	}
+
+	expected := []ValidationError{
+		{InstanceLocation: "", Keyword: "required", SchemaLocation: uri + "#/required"},
+		{InstanceLocation: "/age", Keyword: "minimum", SchemaLocation: uri + "#/properties/age/minimum", Expected: "0", Actual: "-1"},
+		{InstanceLocation: "/tags/1", Keyword: "type", SchemaLocation: uri + "#/properties/tags/items/1/type", Expected: "string", Actual: "integer"},
+		{InstanceLocation: "/address/zip", Keyword: "maxLength", SchemaLocation: uri + "#/properties/address/properties/zip/maxLength", Expected: "3", Actual: 5},
+		{InstanceLocation: "/kind", Keyword: "enum", Expected: "a, b", Actual: "c"},
+		{InstanceLocation: "/extra", Keyword: "additionalProperties", SchemaLocation: uri + "#/additionalProperties"},
+	}
+	for _, e := range expected {
+		actual, ok := byLocation[e.InstanceLocation+" "+e.Keyword]
+		if !assert.True(t, ok, "missing %s %s in %+v", e.InstanceLocation, e.Keyword, report.Errors) {
+			continue
+		}
+		if e.SchemaLocation != "" {
+			assert.Equal(t, e.SchemaLocation, actual.SchemaLocation)
+		}
+		if e.Expected != nil {
+			assert.Equal(t, e.Expected, actual.Expected)
+			assert.Equal(t, e.Actual, actual.Actual)
+		}
+	}
+
+	// Only the failing assertions are reported, not the keywords summarizing them
+	for _, e := range report.Errors {
+		assert.NotContains(t, []string{"properties", "items"}, e.Keyword)
+	}
+
+	report, err = v.Validate(uri, map[string]any{"name": "John"})
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Empty(t, report.Errors)
+}
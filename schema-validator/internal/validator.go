@@ -3,93 +3,311 @@ package internal
 import (
 	"encoding/json"
 	"errors"
-	"io"
-	"net/http"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/hashicorp/go-retryablehttp"
 	"github.com/kaptinlin/jsonschema"
 	"golang.org/x/sync/singleflight"
 
 	"github.com/mfelipe/go-feijoada/schema-validator/config"
-	utilshttp "github.com/mfelipe/go-feijoada/utils/http"
+	"github.com/mfelipe/go-feijoada/utils/vocabulary"
 )
 
 // validator is a struct that holds the schemas and provides methods for validation.
 type validator struct {
-	compiler *jsonschema.Compiler
+	cfg        config.Config
+	compiler   *jsonschema.Compiler
+	vocabulary *vocabulary.Vocabulary
+	loader     *httpLoader
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]*entry
+	fetches singleflight.Group
+	// results caches validation reports of raw payloads, when configured
+	results *resultCache
+}
+
+// entry is a compiled schema fetched by the loader, or added locally
+type entry struct {
+	schema compiledSchema
+	// fingerprint identifies the schema content, so cached results of a replaced schema are not used
+	fingerprint string
+	// expires is when the schema must be refreshed, zero for schemas that never expire
+	expires    time.Time
+	refreshing atomic.Bool
 }
 
-func (v *validator) Validate(schemaURI string, obj any) (*jsonschema.EvaluationResult, error) {
-	schema, err := v.compiler.GetSchema(schemaURI)
+func (v *validator) Validate(schemaURI string, obj any) (*Report, error) {
+	e, err := v.getSchema(schemaURI)
 	if err != nil {
 		return nil, err
 	}
-	if schema == nil {
-		return nil, errors.New("schema not found")
+	if e.schema == nil {
+		return nil, ErrSchemaNotFound
 	}
-	result := schema.Validate(obj)
-	if result == nil {
-		return nil, errors.New("validation result is nil")
+
+	// The compiler only decodes plain byte slices, other named types would be validated as Go values
+	if raw, ok := obj.(json.RawMessage); ok {
+		obj = []byte(raw)
 	}
 
-	return result, nil
+	key, cacheable := v.results.key(e.fingerprint, obj)
+	if cacheable {
+		if report, ok := v.results.get(key); ok {
+			return report, nil
+		}
+	}
+
+	report, err := e.schema.validate(schemaURI, obj)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable {
+		v.results.add(key, report)
+	}
+	return report, nil
+}
+
+// Decode validates a payload encoded as contentType, returning its canonical JSON when valid. JSON payloads are
+// returned as they are, binary payloads are decoded by the engine of their schema type.
+func (v *validator) Decode(schemaURI, contentType string, payload []byte) (json.RawMessage, *Report, error) {
+	contentType = normalizeContentType(contentType)
+	if contentType == ContentTypeJSON {
+		report, err := v.Validate(schemaURI, payload)
+		if err != nil || !report.Valid {
+			return nil, report, err
+		}
+		return payload, report, nil
+	}
+
+	e, err := v.getSchema(schemaURI)
+	if err != nil {
+		return nil, nil, err
+	}
+	if e.schema == nil {
+		return nil, nil, ErrSchemaNotFound
+	}
+	return e.schema.decode(schemaURI, contentType, payload)
 }
 
 func (v *validator) AddSchema(uri string, schema json.RawMessage) error {
-	_, err := v.compiler.Compile(schema, uri)
-	return err
+	compiled, err := v.compilerFor(uri).Compile(schema, uri)
+	if err != nil {
+		return err
+	}
+	// References that can't be resolved are ignored by the compiler, offline they would never be
+	if unresolved := compiled.GetUnresolvedReferenceURIs(); v.cfg.Offline && len(unresolved) > 0 {
+		return fmt.Errorf("%w: references %s", ErrSchemaNotFound, strings.Join(unresolved, ", "))
+	}
+	keywords, err := compileKeywords(v.vocabulary.Keywords(), "", schema)
+	if err != nil {
+		return err
+	}
+
+	v.loader.forget(uri)
+	// Schemas compiled afterward reference the added one, even when it replaces a known schema
+	v.compiler.SetSchema(uri, compiled)
+	if compiled.ID != "" {
+		v.compiler.SetSchema(compiled.ID, compiled)
+	}
+	v.store(uri, jsonSchema{schema: compiled, keywords: keywords}, fingerprint(uri, schema), time.Time{})
+	return nil
+}
+
+// compilerFor returns the compiler adding the schema of uri. Compilers never replace a known schema, so a replacement
+// is compiled by a new compiler, knowing the other JSON schemas it may reference.
+func (v *validator) compilerFor(uri string) *jsonschema.Compiler {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if _, known := v.entries[uri]; !known {
+		return v.compiler
+	}
+
+	compiler := v.newCompiler()
+	for other, e := range v.entries {
+		if js, ok := e.schema.(jsonSchema); ok && other != uri && !strings.Contains(other, "#") {
+			compiler.SetSchema(other, js.schema)
+		}
+	}
+	return compiler
+}
+
+// RegisterFormat adds a format to the vocabulary, used by every schema
+func (v *validator) RegisterFormat(name string, format vocabulary.Format) {
+	v.vocabulary.RegisterFormat(name, format)
+	v.compiler.RegisterFormat(name, format)
+}
+
+// RegisterKeyword adds a keyword to the vocabulary, used by the schemas compiled afterward
+func (v *validator) RegisterKeyword(name string, keyword vocabulary.Keyword) {
+	v.vocabulary.RegisterKeyword(name, keyword)
+}
+
+// SetAssertFormat toggles format assertion, for every schema
+func (v *validator) SetAssertFormat(assert bool) {
+	v.vocabulary.SetAssertFormat(assert)
+	v.compiler.SetAssertFormat(assert)
 }
 
 //goland:noinspection GoExportedFuncWithUnexportedType
-func New(config config.Config) *validator {
-	compiler := jsonschema.NewCompiler()
-	compiler.DefaultBaseURI = config.DefaultBaseURI
+func New(cfg config.Config) *validator {
+	v := &validator{
+		cfg:     cfg,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+
+	// Without loader configuration, schemas are fetched with a single attempt and kept forever
+	var loaderCfg config.Loader
+	if cfg.Loader != nil {
+		loaderCfg = *cfg.Loader
+	}
+	v.loader = newHTTPLoader(loaderCfg)
+	v.loader.offline = cfg.Offline
+	v.ttl = loaderCfg.TTL
 
-	return &validator{
-		compiler: compiler,
+	// Without vocabulary configuration, formats are annotations and only the built-in ones are known
+	var vocabularyCfg vocabulary.Config
+	if cfg.Vocabulary != nil {
+		vocabularyCfg = *cfg.Vocabulary
 	}
+	vocab, err := vocabulary.New(vocabularyCfg)
+	if err != nil {
+		panic(fmt.Errorf("schema-validator vocabulary: %w", err))
+	}
+	v.vocabulary = vocab
+	v.compiler = v.newCompiler()
+
+	if cfg.Cache != nil && cfg.Cache.Size > 0 {
+		v.results = newResultCache(cfg.Cache.Size)
+	}
+
+	return v
+}
+
+func (v *validator) newCompiler() *jsonschema.Compiler {
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultBaseURI = v.cfg.DefaultBaseURI
+	compiler.SetAssertFormat(v.vocabulary.AssertFormat())
+	for name, format := range v.vocabulary.Formats() {
+		compiler.RegisterFormat(name, format)
+	}
+	v.overrideHTTPLoader(compiler)
+	return compiler
 }
 
 // overrideHTTPLoader overwrites the default Compiler http client to get schemas
-// The retriable client could be configured accordingly to each scenario, here is on the defaults
-// Although this scenario would be extremely rare (besides initial load where the compiler may be empty), single flight
-// calls for new schemas may prevent unnecessary high load for the schema repository service.
-func (v *validator) overrideHTTPLoader() {
-	var singleFlightGroup = singleflight.Group{}
-	var httpClient = *retryablehttp.NewClient()
-	httpClient.HTTPClient.Transport = utilshttp.CustomPooledTransport()
-	var stdHTTPClient = httpClient.StandardClient()
-
-	sfHTTPLoader := func(url string) (io.ReadCloser, error) {
-		did, err, _ := singleFlightGroup.Do(url, func() (interface{}, error) {
-			req, err := http.NewRequest("GET", url, nil)
-			if err != nil {
-				return nil, err
-			}
-
-			resp, err := stdHTTPClient.Do(req)
-			if err != nil {
-				return nil, jsonschema.ErrFailedToFetch
-			}
-
-			if resp.StatusCode != http.StatusOK {
-				err = resp.Body.Close()
-				if err != nil {
-					return nil, err
-				}
-				return nil, jsonschema.ErrInvalidHTTPStatusCode
-			}
-
-			return resp.Body, nil
-		})
+// The retriable client is configured through config.Loader, and single flight calls for new schemas prevent
+// unnecessary high load for the schema repository service.
+func (v *validator) overrideHTTPLoader(compiler *jsonschema.Compiler) {
+	compiler.RegisterLoader("http", v.loader.load)
+	compiler.RegisterLoader("https", v.loader.load)
+}
+
+// getSchema returns the compiled schema, fetching it when unknown. Expired schemas are still returned while they are
+// refreshed in the background.
+func (v *validator) getSchema(uri string) (*entry, error) {
+	v.mu.RLock()
+	e, ok := v.entries[uri]
+	v.mu.RUnlock()
+
+	if ok {
+		if !e.expires.IsZero() && v.now().After(e.expires) && e.refreshing.CompareAndSwap(false, true) {
+			go v.refresh(uri, e)
+		}
+		return e, nil
+	}
+
+	f, err, _ := v.fetches.Do(uri, func() (any, error) {
+		return v.fetch(v.compiler, uri)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := f.(fetched)
+	return v.store(uri, fetched.schema, fetched.fingerprint, v.expiry()), nil
+}
+
+// fetched is a schema compiled by fetch
+type fetched struct {
+	schema      compiledSchema
+	fingerprint string
+}
+
+// fetch loads and compiles the schema with the engine of its type. JSON Schemas are compiled with the given compiler,
+// resolving anchors of the URI if any.
+func (v *validator) fetch(compiler *jsonschema.Compiler, uri string) (fetched, error) {
+	base, _, _ := strings.Cut(uri, "#")
+
+	doc, err := v.loader.fetch(base)
+	if err != nil {
+		return fetched{}, err
+	}
 
+	if doc.schemaType != SchemaTypeJSONSchema {
+		newSchema, ok := engines[doc.schemaType]
+		if !ok {
+			return fetched{}, fmt.Errorf("%w: %s", ErrUnsupportedSchemaType, doc.schemaType)
+		}
+		schema, err := newSchema(doc.schema)
 		if err != nil {
-			return nil, err
+			return fetched{}, err
 		}
+		return fetched{schema: schema, fingerprint: fingerprint(uri, doc.schema)}, nil
+	}
 
-		return did.(io.ReadCloser), nil
+	root, err := compiler.Compile(doc.schema, base)
+	if err != nil {
+		return fetched{}, err
 	}
+	if base != uri {
+		if root, err = compiler.GetSchema(uri); err != nil {
+			return fetched{}, err
+		}
+	}
+	keywords, err := compileKeywords(v.vocabulary.Keywords(), uri, doc.schema)
+	if err != nil {
+		return fetched{}, err
+	}
+	return fetched{schema: jsonSchema{schema: root, keywords: keywords}, fingerprint: fingerprint(uri, doc.schema)}, nil
+}
+
+// refresh fetches an expired schema again. A new compiler is needed, as compilers never replace a known schema. The
+// stale schema is dropped if it was deleted from the repository, and kept until the next attempt on other failures.
+func (v *validator) refresh(uri string, stale *entry) {
+	defer stale.refreshing.Store(false)
+
+	f, err := v.fetch(v.newCompiler(), uri)
+	switch {
+	case errors.Is(err, ErrSchemaNotFound):
+		v.mu.Lock()
+		delete(v.entries, uri)
+		v.mu.Unlock()
+	case err != nil:
+		v.store(uri, stale.schema, stale.fingerprint, v.expiry())
+	default:
+		v.store(uri, f.schema, f.fingerprint, v.expiry())
+	}
+}
+
+func (v *validator) store(uri string, schema compiledSchema, fingerprint string, expires time.Time) *entry {
+	e := &entry{schema: schema, fingerprint: fingerprint, expires: expires}
 
-	v.compiler.RegisterLoader("http", sfHTTPLoader)
-	v.compiler.RegisterLoader("https", sfHTTPLoader)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.entries[uri] = e
+	return e
+}
+
+func (v *validator) expiry() time.Time {
+	if v.ttl <= 0 {
+		return time.Time{}
+	}
+	return v.now().Add(v.ttl)
 }
@@ -91,7 +91,7 @@ func TestValidator_Validate(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
-				assert.Equal(t, tt.expectValid, result.IsValid())
+				assert.Equal(t, tt.expectValid, result.Valid)
 			}
 		})
 	}
@@ -114,6 +114,29 @@ func TestValidator_AddSchema(t *testing.T) {
 		err := validator.AddSchema("http://test", schema)
 		assert.Error(t, err)
 	})
+
+	t.Run("Replaced schema", func(t *testing.T) {
+		validator := New(config.Config{DefaultBaseURI: "http://localhost:8080", Offline: true, Cache: &config.Cache{Size: 16}})
+		const uri = "http://localhost:8080/schemas/user/1.0.0"
+		payload := []byte(`{"id": "1"}`)
+
+		require.NoError(t, validator.AddSchema(uri, json.RawMessage(`{"type": "object", "required": ["id"]}`)))
+		report, err := validator.Validate(uri, payload)
+		require.NoError(t, err)
+		assert.True(t, report.Valid)
+
+		require.NoError(t, validator.AddSchema(uri, json.RawMessage(`{"type": "object", "required": ["id", "name"]}`)))
+		report, err = validator.Validate(uri, payload)
+		require.NoError(t, err)
+		assert.False(t, report.Valid)
+
+		// Schemas added afterward reference the replacement
+		require.NoError(t, validator.AddSchema("http://localhost:8080/schemas/team/1.0.0",
+			json.RawMessage(`{"type": "object", "properties": {"lead": {"$ref": "`+uri+`"}}}`)))
+		report, err = validator.Validate("http://localhost:8080/schemas/team/1.0.0", []byte(`{"lead": {"id": "1"}}`))
+		require.NoError(t, err)
+		assert.False(t, report.Valid)
+	})
 }
 
 func TestValidator_WithHTTPLoader(t *testing.T) {
@@ -164,3 +187,18 @@ func TestNew(t *testing.T) {
 	assert.NotNil(t, validator)
 	assert.NotNil(t, validator.compiler)
 }
+
+func TestValidator_RawPayloads(t *testing.T) {
+	v := New(config.Config{DefaultBaseURI: "http://localhost:8080"})
+	require.NoError(t, v.AddSchema("http://test", json.RawMessage(`{"type": "object", "required": ["id"]}`)))
+
+	for _, obj := range []any{[]byte(`{"id": 1}`), json.RawMessage(`{"id": 1}`)} {
+		result, err := v.Validate("http://test", obj)
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "%T", obj)
+	}
+
+	result, err := v.Validate("http://test", json.RawMessage(`{"name": "John"}`))
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+}
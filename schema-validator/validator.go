@@ -1,18 +1,80 @@
 package schemavalidator
 
 import (
+	"context"
 	"encoding/json"
 
-	"github.com/kaptinlin/jsonschema"
-
 	"github.com/mfelipe/go-feijoada/schema-validator/config"
 	"github.com/mfelipe/go-feijoada/schema-validator/internal"
+	"github.com/mfelipe/go-feijoada/utils/vocabulary"
+)
+
+// ErrSchemaNotFound is returned when validating against a schema unknown to the validator and to schema-repository
+var ErrSchemaNotFound = internal.ErrSchemaNotFound
+
+// ErrOffline is returned by Preload when listing schema-repository with an offline validator
+var ErrOffline = internal.ErrOffline
+
+// Report is the outcome of a validation, listing every failed assertion
+type Report = internal.Report
+
+// ValidationError is a failed assertion: the JSON pointer of the value, the keyword and its schema location, a message,
+// and the expected and actual values when the keyword compares them
+type ValidationError = internal.ValidationError
+
+// Schema types, as stored by schema-repository, each validated by its own engine
+const (
+	SchemaTypeJSONSchema = internal.SchemaTypeJSONSchema
+	SchemaTypeAvro       = internal.SchemaTypeAvro
+	SchemaTypeProtobuf   = internal.SchemaTypeProtobuf
 )
 
-// SchemaValidator defines an interface for validating JSON data against a schema.
+// Content types of the payloads given to SchemaValidator.Decode. Parameters such as charset are ignored, and an empty
+// content type is JSON
+const (
+	ContentTypeJSON     = internal.ContentTypeJSON
+	ContentTypeAvro     = internal.ContentTypeAvro
+	ContentTypeProtobuf = internal.ContentTypeProtobuf
+)
+
+var (
+	// ErrUnsupportedSchemaType is returned for schemas of a type the validator has no engine for
+	ErrUnsupportedSchemaType = internal.ErrUnsupportedSchemaType
+	// ErrUnsupportedContentType is returned when decoding a payload the engine of its schema can't read, such as
+	// protobuf payloads of an Avro schema
+	ErrUnsupportedContentType = internal.ErrUnsupportedContentType
+)
+
+// Format reports whether a value matches a custom format. Values of a type the format doesn't apply to must match
+type Format = vocabulary.Format
+
+// Keyword compiles the value of a custom keyword into the Check of the values of its schema, an error rejecting the
+// schema. Numbers are given as json.Number
+type Keyword = vocabulary.Keyword
+
+// Check validates a value against a compiled Keyword, the error message being reported as the validation failure
+type Check = vocabulary.Check
+
+// SchemaValidator defines an interface for validating data against a schema, whatever the schema type.
 type SchemaValidator interface {
-	Validate(schemaURI string, obj any) (*jsonschema.EvaluationResult, error)
+	// Validate checks a JSON payload, raw or decoded, against the schema. Avro schemas read standard JSON, and protobuf
+	// schemas the protobuf JSON mapping
+	Validate(schemaURI string, obj any) (*Report, error)
+	// Decode validates a payload encoded as contentType, returning its canonical JSON when valid. JSON payloads are
+	// returned as they are, Avro binary payloads as standard JSON, and protobuf ones with the protobuf JSON mapping
+	Decode(schemaURI, contentType string, payload []byte) (json.RawMessage, *Report, error)
 	AddSchema(uri string, schema json.RawMessage) error
+	// Preload compiles the schemas selected by config.Preload, from a local directory or listed from schema-repository,
+	// before they are first used. Schemas failing to load are reported, without preventing the others from loading
+	Preload(ctx context.Context) error
+	// RegisterFormat adds a format, used by every schema. Formats are only asserted when SetAssertFormat is on
+	RegisterFormat(name string, format Format)
+	// RegisterKeyword adds a keyword, used by the schemas compiled afterward, so keywords must be registered before the
+	// first validation or Preload. Keywords are evaluated in the subschemas applying unconditionally, see README
+	RegisterKeyword(name string, keyword Keyword)
+	// SetAssertFormat fails values not matching the format of their schema, and unknown formats. Formats are only
+	// annotations otherwise
+	SetAssertFormat(assert bool)
 }
 
 func New(config config.Config) SchemaValidator {
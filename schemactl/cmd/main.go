@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mfelipe/go-feijoada/schemactl/internal"
+)
+
+// Exit codes: problems found in the schemas or payloads, and wrong usage or unreadable files
+const (
+	exitProblems = 1
+	exitError    = 2
+)
+
+const usage = `Usage: schemactl <command> [flags] <arguments>
+
+Commands:
+  lint [flags] <schema>...                  check schemas meant for schemas/schemas
+  diff <old schema> <new schema>            classify the compatibility of a new schema version
+  validate [flags] <schema> [<payloads>]    validate the NDJSON payloads of a file, or of stdin
+
+Run schemactl <command> -h for the flags of a command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(stderr, usage)
+		return exitError
+	}
+
+	switch args[0] {
+	case "lint":
+		return lint(args[1:], stdout, stderr)
+	case "diff":
+		return diff(args[1:], stdout, stderr)
+	case "validate":
+		return validate(args[1:], stdin, stdout, stderr)
+	case "-h", "-help", "--help", "help":
+		_, _ = fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return exitError
+	}
+}
+
+// validatorFlags registers the flags of the offline validator schemas are compiled with
+func validatorFlags(fs *flag.FlagSet) *internal.Options {
+	opts := &internal.Options{}
+	fs.StringVar(&opts.BaseURI, "base-uri", "http://schema-repository:8080", "schema-repository URI, for schemas without $id")
+	fs.StringVar(&opts.Dir, "dir", "", "directory of the schemas references are resolved with (default: the directory of each schema)")
+	fs.BoolVar(&opts.AssertFormat, "assert-format", false, "fail values not matching their format")
+	return opts
+}
+
+func lint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts := validatorFlags(fs)
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 {
+		return usageError(fs, err, "at least one schema is required")
+	}
+
+	code := 0
+	for _, file := range fs.Args() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return exitError
+		}
+
+		fileOpts := *opts
+		if fileOpts.Dir == "" {
+			fileOpts.Dir = filepath.Dir(file)
+		}
+		// Schemas of the directory failing to compile only matter when referenced, which Lint reports
+		v, _ := internal.NewValidator(fileOpts)
+
+		for _, f := range internal.Lint(v, fileOpts, file, data) {
+			_, _ = fmt.Fprintf(stdout, "%s: %s\n", file, f)
+			code = exitProblems
+		}
+	}
+	return code
+}
+
+func diff(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil || fs.NArg() != 2 {
+		return usageError(fs, err, "the old and new schemas are required")
+	}
+
+	oldData, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	newData, err := os.ReadFile(fs.Arg(1))
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	d, err := internal.DiffSchemas(oldData, newData)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	for _, c := range d.Changes {
+		_, _ = fmt.Fprintln(stdout, c)
+	}
+	_, _ = fmt.Fprintf(stdout, "compatibility: %s, requires a %s version\n", d.Compatibility, d.Bump)
+
+	if msg := d.VersionError(); msg != "" {
+		_, _ = fmt.Fprintln(stdout, msg)
+		return exitProblems
+	}
+	return 0
+}
+
+func validate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts := validatorFlags(fs)
+	if err := fs.Parse(args); err != nil || fs.NArg() < 1 || fs.NArg() > 2 {
+		return usageError(fs, err, "a schema and at most one payloads file are required")
+	}
+
+	file := fs.Arg(0)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	if opts.Dir == "" {
+		opts.Dir = filepath.Dir(file)
+	}
+
+	payloads := stdin
+	if name := fs.Arg(1); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		defer func() { _ = f.Close() }()
+		payloads = f
+	}
+
+	v, err := internal.NewValidator(*opts)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "warning: %s\n", err)
+	}
+	uri, err := internal.AddSchema(v, *opts, file, data)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "%s doesn't compile: %s\n", file, err)
+		return exitError
+	}
+
+	valid, invalid, err := internal.ValidateNDJSON(v, uri, payloads, func(r internal.LineReport) {
+		for _, e := range r.Report.Errors {
+			_, _ = fmt.Fprintf(stdout, "line %d: %s: %s: %s\n", r.Line, location(e.InstanceLocation), e.Keyword, e.Message)
+		}
+	})
+	_, _ = fmt.Fprintf(stdout, "%d valid, %d invalid\n", valid, invalid)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	if invalid > 0 {
+		return exitProblems
+	}
+	return 0
+}
+
+func location(instanceLocation string) string {
+	if instanceLocation == "" {
+		return "/"
+	}
+	return instanceLocation
+}
+
+func usageError(fs *flag.FlagSet, err error, msg string) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err == nil {
+		_, _ = fmt.Fprintln(fs.Output(), msg)
+		fs.Usage()
+	}
+	return exitError
+}
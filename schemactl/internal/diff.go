@@ -0,0 +1,409 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Effect is how a change affects the payloads a schema accepts
+type Effect string
+
+const (
+	// Annotation changes don't change the accepted payloads, such as a new description
+	Annotation Effect = "annotation"
+	// Relaxes changes accept payloads that were rejected, such as a removed required property
+	Relaxes Effect = "relaxes"
+	// Restricts changes reject payloads that were accepted, such as a new required property
+	Restricts Effect = "restricts"
+	// Breaks changes may do both, such as a changed pattern
+	Breaks Effect = "breaks"
+)
+
+// Compatibility classifies a new schema version against the previous one
+type Compatibility string
+
+const (
+	// Full versions accept the same payloads
+	Full Compatibility = "FULL"
+	// Backward versions accept every payload the previous version accepted, so consumers can upgrade first
+	Backward Compatibility = "BACKWARD"
+	// Forward versions only accept payloads the previous version accepted, so producers can upgrade first
+	Forward Compatibility = "FORWARD"
+	// None versions break both consumers and producers
+	None Compatibility = "NONE"
+)
+
+// Change is a difference between two schema versions, at the JSON pointer of the changed keyword
+type Change struct {
+	Location    string `json:"location"`
+	Description string `json:"description"`
+	Effect      Effect `json:"effect"`
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s: %s", c.Effect, c.Location, c.Description)
+}
+
+// Diff is the comparison of two schema versions
+type Diff struct {
+	Changes       []Change      `json:"changes"`
+	Compatibility Compatibility `json:"compatibility"`
+	// Bump is the part of the version the compatibility requires to be increased: patch, minor or major
+	Bump string `json:"bump"`
+	// OldVersion and NewVersion are read from the $id of the schemas, when they follow /schemas/<name>/<version>
+	OldVersion *Version `json:"oldVersion,omitempty"`
+	NewVersion *Version `json:"newVersion,omitempty"`
+}
+
+// VersionError tells why the versions of the schemas don't match their compatibility, empty when they do or are unknown
+func (d *Diff) VersionError() string {
+	if d.OldVersion == nil || d.NewVersion == nil {
+		return ""
+	}
+	o, n := *d.OldVersion, *d.NewVersion
+	var bumped string
+	switch {
+	case n.Major > o.Major:
+		return ""
+	case n.Major == o.Major && n.Minor > o.Minor:
+		bumped = "minor"
+	case n.Major == o.Major && n.Minor == o.Minor && n.Patch > o.Patch:
+		bumped = "patch"
+	default:
+		return fmt.Sprintf("version %s is not greater than %s", n, o)
+	}
+	if bumped == "patch" && d.Bump != "patch" || bumped == "minor" && d.Bump == "major" {
+		return fmt.Sprintf("%s compatibility requires a %s version, but %s is a %s version of %s", d.Compatibility,
+			d.Bump, n, bumped, o)
+	}
+	return ""
+}
+
+// DiffSchemas compares two versions of a schema, classifying their compatibility from the effect of each change.
+// References and composition keywords, such as $ref or anyOf, are not resolved: any change to them breaks
+func DiffSchemas(oldData, newData []byte) (*Diff, error) {
+	oldSchema, err := decode(oldData)
+	if err != nil {
+		return nil, fmt.Errorf("old schema: %w", err)
+	}
+	newSchema, err := decode(newData)
+	if err != nil {
+		return nil, fmt.Errorf("new schema: %w", err)
+	}
+
+	d := &Diff{}
+	if _, v, err := schemaID(oldSchema); err == nil {
+		d.OldVersion = &v
+	}
+	if _, v, err := schemaID(newSchema); err == nil {
+		d.NewVersion = &v
+	}
+	// The $id of each version is different by design
+	delete(oldSchema, "$id")
+	delete(newSchema, "$id")
+
+	d.compare("", oldSchema, newSchema)
+	d.classify()
+	return d, nil
+}
+
+func (d *Diff) add(location string, effect Effect, format string, a ...any) {
+	d.Changes = append(d.Changes, Change{Location: location, Description: fmt.Sprintf(format, a...), Effect: effect})
+}
+
+func (d *Diff) classify() {
+	var relaxes, restricts bool
+	for _, c := range d.Changes {
+		relaxes = relaxes || c.Effect == Relaxes || c.Effect == Breaks
+		restricts = restricts || c.Effect == Restricts || c.Effect == Breaks
+	}
+	switch {
+	case relaxes && restricts:
+		d.Compatibility, d.Bump = None, "major"
+	case restricts:
+		d.Compatibility, d.Bump = Forward, "major"
+	case relaxes:
+		d.Compatibility, d.Bump = Backward, "minor"
+	default:
+		d.Compatibility, d.Bump = Full, "patch"
+	}
+}
+
+// annotationKeywords don't change the accepted payloads
+var annotationKeywords = map[string]bool{
+	"$schema": true, "$comment": true, "title": true, "description": true, "examples": true, "default": true,
+	"deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// Bounds, the lower ones restricting payloads when increased and the upper ones when decreased
+var (
+	lowerBounds = map[string]bool{"minimum": true, "exclusiveMinimum": true, "minLength": true, "minItems": true,
+		"minProperties": true, "minContains": true}
+	upperBounds = map[string]bool{"maximum": true, "exclusiveMaximum": true, "maxLength": true, "maxItems": true,
+		"maxProperties": true, "maxContains": true, "maxDecimals": true}
+)
+
+// compare records the changes between two subschemas. Boolean schemas are compared as the empty schema, for true, and
+// as a schema rejecting everything, for false
+func (d *Diff) compare(pointer string, oldSchema, newSchema any) {
+	if reflect.DeepEqual(oldSchema, newSchema) {
+		return
+	}
+	oldMap, oldAccepts, oldOK := asSchema(oldSchema)
+	newMap, newAccepts, newOK := asSchema(newSchema)
+	switch {
+	case !oldOK || !newOK:
+		d.add(pointer, Breaks, "changed from %s to %s", compact(oldSchema), compact(newSchema))
+		return
+	case !oldAccepts && !newAccepts:
+		return
+	case !oldAccepts:
+		d.add(pointer, Relaxes, "accepts values, instead of none")
+		return
+	case !newAccepts:
+		d.add(pointer, Restricts, "accepts no value")
+		return
+	}
+
+	for _, keyword := range slices.Sorted(maps.Keys(union(oldMap, newMap))) {
+		o, oldOK := oldMap[keyword]
+		n, newOK := newMap[keyword]
+		if reflect.DeepEqual(o, n) {
+			continue
+		}
+		location := pointer + "/" + escapePointer(keyword)
+
+		switch {
+		case annotationKeywords[keyword]:
+			d.add(location, Annotation, "%s changed", keyword)
+		case keyword == "type":
+			d.compareTypes(location, o, n)
+		case keyword == "enum":
+			d.compareEnum(location, o, n)
+		case keyword == "required":
+			d.compareRequired(location, o, n)
+		case keyword == "properties":
+			d.compareProperties(pointer, oldMap, newMap)
+		case keyword == "additionalProperties" || keyword == "items":
+			d.compare(location, orTrue(o, oldOK), orTrue(n, newOK))
+		case keyword == "$defs" || keyword == "definitions":
+			d.compareDefs(location, o, n)
+		case keyword == "uniqueItems":
+			switch oldUnique, newUnique := o == true, n == true; {
+			case newUnique && !oldUnique:
+				d.add(location, Restricts, "items must be unique")
+			case oldUnique && !newUnique:
+				d.add(location, Relaxes, "items may repeat")
+			}
+		case lowerBounds[keyword] || upperBounds[keyword]:
+			d.compareBound(location, keyword, o, oldOK, n, newOK)
+		case !oldOK:
+			d.add(location, Restricts, "%s added", keyword)
+		case !newOK:
+			d.add(location, Relaxes, "%s removed", keyword)
+		default:
+			d.add(location, Breaks, "%s changed from %s to %s", keyword, compact(o), compact(n))
+		}
+	}
+}
+
+func (d *Diff) compareTypes(location string, o, n any) {
+	oldTypes, newTypes := types(o), types(n)
+	var removed, added []string
+	for _, t := range oldTypes {
+		if !acceptsType(newTypes, t) {
+			removed = append(removed, t)
+		}
+	}
+	for _, t := range newTypes {
+		if !acceptsType(oldTypes, t) {
+			added = append(added, t)
+		}
+	}
+	switch {
+	case len(removed) > 0 && len(added) > 0:
+		d.add(location, Breaks, "type changed from %s to %s", compact(o), compact(n))
+	case len(removed) > 0:
+		d.add(location, Restricts, "no longer accepts %s", strings.Join(removed, ", "))
+	case len(added) > 0:
+		d.add(location, Relaxes, "also accepts %s", strings.Join(added, ", "))
+	}
+}
+
+func (d *Diff) compareEnum(location string, o, n any) {
+	oldValues, oldOK := o.([]any)
+	newValues, newOK := n.([]any)
+	switch {
+	case !oldOK:
+		d.add(location, Restricts, "enum added")
+		return
+	case !newOK:
+		d.add(location, Relaxes, "enum removed")
+		return
+	}
+	removed, added := difference(oldValues, newValues), difference(newValues, oldValues)
+	if len(removed) > 0 {
+		d.add(location, Restricts, "values removed: %s", strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		d.add(location, Relaxes, "values added: %s", strings.Join(added, ", "))
+	}
+}
+
+func (d *Diff) compareRequired(location string, o, n any) {
+	oldRequired, _ := o.([]any)
+	newRequired, _ := n.([]any)
+	if removed := difference(oldRequired, newRequired); len(removed) > 0 {
+		d.add(location, Relaxes, "no longer required: %s", strings.Join(removed, ", "))
+	}
+	if added := difference(newRequired, oldRequired); len(added) > 0 {
+		d.add(location, Restricts, "now required: %s", strings.Join(added, ", "))
+	}
+}
+
+// compareProperties compares the properties of both versions. A property added to an object accepting additional
+// properties restricts their values, and a removed one relaxes them, unless additional properties are rejected
+func (d *Diff) compareProperties(pointer string, oldSchema, newSchema map[string]any) {
+	oldProperties, _ := oldSchema["properties"].(map[string]any)
+	newProperties, _ := newSchema["properties"].(map[string]any)
+	oldAdditional, oldOK := oldSchema["additionalProperties"]
+	newAdditional, newOK := newSchema["additionalProperties"]
+
+	for _, name := range slices.Sorted(maps.Keys(union(oldProperties, newProperties))) {
+		location := pointer + "/properties/" + escapePointer(name)
+		o, inOld := oldProperties[name]
+		n, inNew := newProperties[name]
+		switch {
+		case inOld && inNew:
+			d.compare(location, o, n)
+		case inNew:
+			d.compare(location, orTrue(oldAdditional, oldOK), n)
+		default:
+			d.compare(location, o, orTrue(newAdditional, newOK))
+		}
+	}
+}
+
+// compareDefs compares the definitions both versions have. Definitions only matter through references, so adding or
+// removing one is an annotation
+func (d *Diff) compareDefs(location string, o, n any) {
+	oldDefs, _ := o.(map[string]any)
+	newDefs, _ := n.(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(union(oldDefs, newDefs))) {
+		defLocation := location + "/" + escapePointer(name)
+		oldDef, inOld := oldDefs[name]
+		newDef, inNew := newDefs[name]
+		switch {
+		case inOld && inNew:
+			d.compare(defLocation, oldDef, newDef)
+		case inNew:
+			d.add(defLocation, Annotation, "definition added")
+		default:
+			d.add(defLocation, Annotation, "definition removed")
+		}
+	}
+}
+
+func (d *Diff) compareBound(location, keyword string, o any, oldOK bool, n any, newOK bool) {
+	oldBound, oldNumber := number(o)
+	newBound, newNumber := number(n)
+	if oldOK && !oldNumber || newOK && !newNumber {
+		d.add(location, Breaks, "%s changed from %s to %s", keyword, compact(o), compact(n))
+		return
+	}
+
+	tighter := lowerBounds[keyword]
+	switch {
+	case !oldOK:
+		d.add(location, Restricts, "%s %s added", keyword, compact(n))
+	case !newOK:
+		d.add(location, Relaxes, "%s %s removed", keyword, compact(o))
+	case (newBound.Cmp(oldBound) > 0) == tighter:
+		d.add(location, Restricts, "%s changed from %s to %s", keyword, compact(o), compact(n))
+	default:
+		d.add(location, Relaxes, "%s changed from %s to %s", keyword, compact(o), compact(n))
+	}
+}
+
+// asSchema returns the keywords of a subschema, the empty schema for true, and false for the false schema. Values that
+// aren't schemas, such as the items arrays of older drafts, are not ok
+func asSchema(schema any) (keywords map[string]any, acceptsValues, ok bool) {
+	switch s := schema.(type) {
+	case map[string]any:
+		return s, true, true
+	case bool:
+		return map[string]any{}, s, true
+	default:
+		return nil, false, false
+	}
+}
+
+// orTrue is the subschema, or true when absent
+func orTrue(schema any, ok bool) any {
+	if !ok {
+		return true
+	}
+	return schema
+}
+
+// types lists the types of a type keyword, every type when absent
+func types(t any) []string {
+	switch v := t.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, e := range v {
+			list = append(list, fmt.Sprint(e))
+		}
+		return list
+	default:
+		return []string{"array", "boolean", "null", "number", "object", "string"}
+	}
+}
+
+// acceptsType tells whether values of type t are accepted by one of the types, integers being numbers
+func acceptsType(types []string, t string) bool {
+	return slices.Contains(types, t) || t == "integer" && slices.Contains(types, "number")
+}
+
+// difference lists the JSON of the values of a missing from b
+func difference(a, b []any) []string {
+	var missing []string
+	for _, v := range a {
+		if !slices.ContainsFunc(b, func(w any) bool { return reflect.DeepEqual(v, w) }) {
+			missing = append(missing, compact(v))
+		}
+	}
+	return missing
+}
+
+func number(v any) (*big.Rat, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Rat).SetString(n.String())
+}
+
+func union(a, b map[string]any) map[string]any {
+	all := maps.Clone(a)
+	if all == nil {
+		all = make(map[string]any, len(b))
+	}
+	maps.Copy(all, b)
+	return all
+}
+
+func compact(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	const base = `{
+		"$id": "http://localhost:8080/schemas/item/1.0.0",
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"name": {"type": "string", "maxLength": 10}
+		},
+		"required": ["id"],
+		"additionalProperties": false
+	}`
+
+	tests := []struct {
+		name          string
+		newSchema     string
+		compatibility Compatibility
+		bump          string
+		changes       []Change
+		versionError  string
+	}{
+		{
+			name: "annotation",
+			newSchema: `{
+				"$id": "http://localhost:8080/schemas/item/1.0.1",
+				"description": "An item",
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer"},
+					"name": {"type": "string", "maxLength": 10}
+				},
+				"required": ["id"],
+				"additionalProperties": false
+			}`,
+			compatibility: Full,
+			bump:          "patch",
+			changes:       []Change{{Location: "/description", Description: "description changed", Effect: Annotation}},
+		},
+		{
+			name: "optional property added",
+			newSchema: `{
+				"$id": "http://localhost:8080/schemas/item/1.1.0",
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer"},
+					"name": {"type": "string", "maxLength": 20},
+					"tags": {"type": "array"}
+				},
+				"required": ["id"],
+				"additionalProperties": false
+			}`,
+			compatibility: Backward,
+			bump:          "minor",
+		},
+		{
+			name: "required property added under a minor version",
+			newSchema: `{
+				"$id": "http://localhost:8080/schemas/item/1.1.0",
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer"},
+					"name": {"type": "string", "maxLength": 10}
+				},
+				"required": ["id", "name"],
+				"additionalProperties": false
+			}`,
+			compatibility: Forward,
+			bump:          "major",
+			changes:       []Change{{Location: "/required", Description: `now required: "name"`, Effect: Restricts}},
+			versionError:  "FORWARD compatibility requires a major version, but 1.1.0 is a minor version of 1.0.0",
+		},
+		{
+			name: "type changed",
+			newSchema: `{
+				"$id": "http://localhost:8080/schemas/item/2.0.0",
+				"type": "object",
+				"properties": {
+					"id": {"type": "string"},
+					"name": {"type": "string", "maxLength": 10}
+				},
+				"required": ["id"],
+				"additionalProperties": false
+			}`,
+			compatibility: None,
+			bump:          "major",
+		},
+		{
+			name:          "same version",
+			newSchema:     base,
+			compatibility: Full,
+			bump:          "patch",
+			versionError:  "version 1.0.0 is not greater than 1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := DiffSchemas([]byte(base), []byte(tt.newSchema))
+			require.NoError(t, err)
+			assert.Equal(t, tt.compatibility, d.Compatibility)
+			assert.Equal(t, tt.bump, d.Bump)
+			if tt.changes != nil {
+				assert.Equal(t, tt.changes, d.Changes)
+			}
+			assert.Equal(t, tt.versionError, d.VersionError())
+		})
+	}
+}
+
+func TestDiffSchemas_Repository(t *testing.T) {
+	oldData, err := os.ReadFile("../../schemas/schemas/order-1.0.0.json")
+	require.NoError(t, err)
+	newData, err := os.ReadFile("../../schemas/schemas/order-2.0.0.json")
+	require.NoError(t, err)
+
+	d, err := DiffSchemas(oldData, newData)
+	require.NoError(t, err)
+	assert.Equal(t, Forward, d.Compatibility)
+	assert.Equal(t, "major", d.Bump)
+	assert.Empty(t, d.VersionError())
+}
+
+func TestDiffSchemas_Invalid(t *testing.T) {
+	_, err := DiffSchemas([]byte(`{`), []byte(`{}`))
+	assert.ErrorContains(t, err, "old schema")
+}
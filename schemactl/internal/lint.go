@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	schemavalidator "github.com/mfelipe/go-feijoada/schema-validator"
+)
+
+// Finding is a problem found in a schema, at the JSON pointer of the offending keyword
+type Finding struct {
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+func (f Finding) String() string {
+	if f.Location == "" {
+		return f.Message
+	}
+	return f.Location + ": " + f.Message
+}
+
+// Lint checks a schema file meant for schemas/schemas: the draft, the $id path and its match with the file name, the
+// title, the required properties of each object, and that the schema compiles with the validator
+func Lint(v schemavalidator.SchemaValidator, opts Options, file string, data []byte) []Finding {
+	schema, err := decode(data)
+	if err != nil {
+		return []Finding{{Message: err.Error()}}
+	}
+
+	var findings []Finding
+	add := func(location, format string, a ...any) {
+		findings = append(findings, Finding{Location: location, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if draft, _ := schema["$schema"].(string); draft != Draft {
+		add("/$schema", "must be %s", Draft)
+	}
+
+	name, version, err := schemaID(schema)
+	switch {
+	case err != nil:
+		add("/$id", "%s", err)
+	default:
+		if fileName, fileVersion, ok := parseFileName(file); ok && (fileName != name || fileVersion != version.String()) {
+			add("/$id", "is %s version %s, but the file is named for %s version %s", name, version, fileName, fileVersion)
+		}
+	}
+
+	if title, _ := schema["title"].(string); strings.TrimSpace(title) == "" {
+		add("/title", "is required")
+	}
+
+	walk("", schema, func(pointer string, sub map[string]any) {
+		required, _ := sub["required"].([]any)
+		properties, hasProperties := sub["properties"].(map[string]any)
+		if !hasProperties {
+			return
+		}
+		for i, r := range required {
+			if _, ok := properties[fmt.Sprint(r)]; !ok {
+				add(fmt.Sprintf("%s/required/%d", pointer, i), "%q is not defined in properties", r)
+			}
+		}
+	})
+
+	if _, err = AddSchema(v, opts, file, data); err != nil {
+		add("", "doesn't compile: %s", err)
+	}
+
+	slices.SortStableFunc(findings, func(a, b Finding) int { return strings.Compare(a.Location, b.Location) })
+	return findings
+}
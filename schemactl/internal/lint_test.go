@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	opts := Options{BaseURI: "http://localhost:8080"}
+
+	tests := []struct {
+		name     string
+		file     string
+		schema   string
+		findings []Finding
+	}{
+		{
+			name: "valid",
+			file: "item-1.0.0.json",
+			schema: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"$id": "http://localhost:8080/schemas/item/1.0.0",
+				"title": "Item",
+				"type": "object",
+				"properties": {"id": {"type": "integer"}},
+				"required": ["id"]
+			}`,
+		},
+		{
+			name: "invalid",
+			file: "item-1.0.1.json",
+			schema: `{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"$id": "http://localhost:8080/schemas/item/1.0.0",
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer"},
+					"tags": {"type": "array", "items": {"type": "object", "properties": {}, "required": ["name"]}}
+				},
+				"required": ["id", "name"]
+			}`,
+			findings: []Finding{
+				{Location: "/$id", Message: "is item version 1.0.0, but the file is named for item version 1.0.1"},
+				{Location: "/$schema", Message: "must be " + Draft},
+				{Location: "/properties/tags/items/required/0", Message: `"name" is not defined in properties`},
+				{Location: "/required/1", Message: `"name" is not defined in properties`},
+				{Location: "/title", Message: "is required"},
+			},
+		},
+		{
+			name: "malformed $id",
+			file: "item-1.0.0.json",
+			schema: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"$id": "http://localhost:8080/item.json",
+				"title": "Item"
+			}`,
+			findings: []Finding{{Location: "/$id", Message: `$id "http://localhost:8080/item.json" doesn't match /schemas/<name>/<version>`}},
+		},
+		{
+			name: "unresolved reference",
+			file: "item-1.0.0.json",
+			schema: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"$id": "http://localhost:8080/schemas/item/1.0.0",
+				"title": "Item",
+				"$ref": "http://localhost:8080/schemas/missing/1.0.0"
+			}`,
+			findings: []Finding{{Message: "doesn't compile: schema not found: references http://localhost:8080/schemas/missing/1.0.0"}},
+		},
+		{
+			name:     "not JSON",
+			file:     "item-1.0.0.json",
+			schema:   `{`,
+			findings: []Finding{{Message: "unexpected EOF"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewValidator(opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.findings, Lint(v, opts, tt.file, []byte(tt.schema)))
+		})
+	}
+}
+
+func TestLint_Repository(t *testing.T) {
+	opts := Options{BaseURI: "http://schema-repository:8080", Dir: "../../schemas/schemas"}
+	v, err := NewValidator(opts)
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(opts.Dir, "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Empty(t, Lint(v, opts, file, data), file)
+	}
+}
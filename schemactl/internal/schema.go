@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Draft is the JSON Schema draft the schemas of schemas/schemas are written in
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	// idPath is the path of the $id of schemas, where schema-repository serves them
+	idPath = regexp.MustCompile(`^/schemas/([a-z0-9]+(?:-[a-z0-9]+)*)/((?:0|[1-9][0-9]*)\.(?:0|[1-9][0-9]*)\.(?:0|[1-9][0-9]*))$`)
+	// fileName is how schema files are named in schemas/schemas
+	fileName = regexp.MustCompile(`^(.+)-([0-9]+\.[0-9]+\.[0-9]+)\.json$`)
+)
+
+// Version is the semantic version of a schema
+type Version struct {
+	Major, Minor, Patch int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// ParseVersion reads a MAJOR.MINOR.PATCH version
+func ParseVersion(s string) (Version, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("version %q is not MAJOR.MINOR.PATCH", s)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("version %q is not MAJOR.MINOR.PATCH", s)
+		}
+		numbers[i] = n
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// decode reads a schema document, with numbers kept as json.Number so they are compared exactly
+func decode(data []byte) (map[string]any, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var doc any
+	if err := d.Decode(&doc); err != nil {
+		return nil, err
+	}
+	schema, ok := doc.(map[string]any)
+	if !ok {
+		return nil, errors.New("schema is not a JSON object")
+	}
+	return schema, nil
+}
+
+// schemaID splits the $id of a schema into the name and version of its /schemas/<name>/<version> path
+func schemaID(schema map[string]any) (name string, version Version, err error) {
+	id, ok := schema["$id"].(string)
+	if !ok || id == "" {
+		return "", Version{}, errors.New("$id is required")
+	}
+	u, err := url.Parse(id)
+	if err != nil || !u.IsAbs() {
+		return "", Version{}, fmt.Errorf("$id %q is not an absolute URI", id)
+	}
+	m := idPath.FindStringSubmatch(u.EscapedPath())
+	if m == nil {
+		return "", Version{}, fmt.Errorf("$id %q doesn't match /schemas/<name>/<version>", id)
+	}
+	version, err = ParseVersion(m[2])
+	return m[1], version, err
+}
+
+// parseFileName splits <name>-<version>.json file names
+func parseFileName(path string) (name, version string, ok bool) {
+	m := fileName.FindStringSubmatch(filepath.Base(path))
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// subschemaKeywords are the keywords holding a subschema, a list of subschemas, or an object of subschemas
+var (
+	subschemaKeywords = []string{"additionalProperties", "items", "contains", "propertyNames", "not", "if", "then",
+		"else", "unevaluatedItems", "unevaluatedProperties", "contentSchema"}
+	subschemaListKeywords   = []string{"prefixItems", "allOf", "anyOf", "oneOf"}
+	subschemaObjectKeywords = []string{"properties", "patternProperties", "dependentSchemas", "$defs", "definitions"}
+)
+
+// walk calls fn with every subschema of schema, and its JSON pointer, starting with schema itself
+func walk(pointer string, schema any, fn func(pointer string, schema map[string]any)) {
+	m, ok := schema.(map[string]any)
+	if !ok {
+		return
+	}
+	fn(pointer, m)
+
+	for _, keyword := range subschemaKeywords {
+		walk(pointer+"/"+keyword, m[keyword], fn)
+	}
+	for _, keyword := range subschemaListKeywords {
+		list, _ := m[keyword].([]any)
+		for i, sub := range list {
+			walk(pointer+"/"+keyword+"/"+strconv.Itoa(i), sub, fn)
+		}
+	}
+	for _, keyword := range subschemaObjectKeywords {
+		subs, _ := m[keyword].(map[string]any)
+		for _, name := range slices.Sorted(maps.Keys(subs)) {
+			walk(pointer+"/"+keyword+"/"+escapePointer(name), subs[name], fn)
+		}
+	}
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
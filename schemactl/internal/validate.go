@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	schemavalidator "github.com/mfelipe/go-feijoada/schema-validator"
+)
+
+// maxLineSize bounds the size of a payload of an NDJSON file
+const maxLineSize = 16 * 1024 * 1024
+
+// LineReport is the validation of a line of an NDJSON file, numbered from 1
+type LineReport struct {
+	Line   int
+	Report *schemavalidator.Report
+}
+
+// ValidateNDJSON validates each payload of an NDJSON stream against the schema, calling fn with the report of each one.
+// Blank lines are skipped. It returns the count of valid and invalid payloads
+func ValidateNDJSON(v schemavalidator.SchemaValidator, schemaURI string, r io.Reader, fn func(LineReport)) (valid, invalid int, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		payload := bytes.TrimSpace(scanner.Bytes())
+		if len(payload) == 0 {
+			continue
+		}
+
+		report, err := v.Validate(schemaURI, bytes.Clone(payload))
+		if err != nil {
+			return valid, invalid, err
+		}
+		if report.Valid {
+			valid++
+		} else {
+			invalid++
+		}
+		fn(LineReport{Line: line, Report: report})
+	}
+	return valid, invalid, scanner.Err()
+}
@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNDJSON(t *testing.T) {
+	opts := Options{BaseURI: "http://localhost:8080"}
+	v, err := NewValidator(opts)
+	require.NoError(t, err)
+	uri, err := AddSchema(v, opts, "item-1.0.0.json", []byte(`{
+		"type": "object",
+		"properties": {"id": {"type": "integer"}},
+		"required": ["id"]
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/schemas/item/1.0.0", uri)
+
+	payloads := "{\"id\": 1}\n\n  {\"id\": \"2\"}\n{\"id\": 3}\n"
+	var lines []int
+	valid, invalid, err := ValidateNDJSON(v, uri, strings.NewReader(payloads), func(r LineReport) {
+		if !r.Report.Valid {
+			lines = append(lines, r.Line)
+		}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, valid)
+	assert.Equal(t, 1, invalid)
+	assert.Equal(t, []int{3}, lines)
+}
+
+func TestValidateNDJSON_UnknownSchema(t *testing.T) {
+	v, err := NewValidator(Options{BaseURI: "http://localhost:8080"})
+	require.NoError(t, err)
+
+	_, _, err = ValidateNDJSON(v, "http://localhost:8080/schemas/missing/1.0.0", strings.NewReader(`{}`), func(LineReport) {})
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+
+	schemavalidator "github.com/mfelipe/go-feijoada/schema-validator"
+	"github.com/mfelipe/go-feijoada/schema-validator/config"
+	"github.com/mfelipe/go-feijoada/utils/vocabulary"
+)
+
+// Options configure the offline validator schemas are compiled with
+type Options struct {
+	// BaseURI is where schema-repository would serve the schemas, for schemas without $id
+	BaseURI string
+	// Dir holds the <name>-<version>.json schemas references are resolved with, such as schemas/schemas
+	Dir string
+	// AssertFormat fails values not matching the format of their schema
+	AssertFormat bool
+}
+
+// NewValidator returns a validator never contacting schema-repository, knowing the schemas of opts.Dir. The validator
+// is returned even when some schemas of the directory failed to compile, along with the error
+func NewValidator(opts Options) (schemavalidator.SchemaValidator, error) {
+	cfg := config.Config{
+		DefaultBaseURI: opts.BaseURI,
+		Offline:        true,
+		Vocabulary:     &vocabulary.Config{AssertFormat: opts.AssertFormat},
+	}
+	if opts.Dir != "" {
+		cfg.Preload = &config.Preload{Dir: opts.Dir}
+	}
+	v := schemavalidator.New(cfg)
+	return v, v.Preload(context.Background())
+}
+
+// AddSchema compiles a schema under its $id, or under where schema-repository would serve it, returning the URI
+func AddSchema(v schemavalidator.SchemaValidator, opts Options, file string, data []byte) (string, error) {
+	var doc struct {
+		ID string `json:"$id"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", err
+	}
+	if doc.ID == "" {
+		doc.ID = opts.BaseURI + "/schemas/" + filepath.Base(file)
+		if name, version, ok := parseFileName(file); ok {
+			doc.ID = opts.BaseURI + "/schemas/" + name + "/" + version
+		}
+	}
+	return doc.ID, v.AddSchema(doc.ID, data)
+}
@@ -1,122 +1,91 @@
+//go:build generate
+
 package main
 
 import (
-	"embed"
+	"flag"
 	"fmt"
-	"io/fs"
-	"net/url"
 	"os"
-	"path/filepath"
 	"strings"
 
-	"github.com/atombender/go-jsonschema/pkg/generator"
-	"github.com/mfelipe/go-feijoada/schemas/internal/loaders"
+	"github.com/mfelipe/go-feijoada/schemas/internal/generator"
 )
 
-//go:embed schemas/*.json
-var schemasFS embed.FS
-
-// This is an exercise on running the generator programmatically, as the original module doesn't offer much configuration on input and output.
-// It generates Go models from any JSON Schema files located in the `schemas` directory, using values dynamically extracted from the JSON schemas
-// for naming the output files, packages and structs. But is not worth it, would be better to rewrite the generator to support this directly.
+// Generates the Go models of the JSON schemas, along with their constants, registry and round-trip tests, and their
+// types in the extra targets, as configured by gen.yaml. Flags override the configuration. Run it from the module root
+// with `go run generate.go`.
 func main() {
-	loader := loaders.NewCachedFileLoader()
-
-	var files []string
-	var schemaMappings = make([]generator.SchemaMapping, 0)
-	if err := fs.WalkDir(schemasFS, ".", func(path string, d fs.DirEntry, err error) error {
-		if d.IsDir() {
-			return nil
-		}
-
-		absPath, err := filepath.Abs(path)
-		if err != nil {
-			return err
-		}
-		loadedSchema, err := loader.Load(absPath, "")
-		if err != nil {
-			return err
-		}
-		schemaIdURL, err := url.Parse(loadedSchema.ID)
-		if err != nil {
-			return err
-		}
-
-		sPath := schemaIdURL.EscapedPath()
-		preffix := "/schemas/"
-		if strings.HasPrefix(sPath, preffix) {
-			splitParams := strings.Split(strings.TrimPrefix(sPath, preffix), "/")
-			if len(splitParams) < 2 {
-				return fmt.Errorf("invalid schema path %s, expected at least 2 segments after %s", sPath, preffix)
-			}
-			name := splitParams[0]
-			version := strings.ReplaceAll(splitParams[1], ".", "_")
-
-			schemaMappings = append(schemaMappings, generator.SchemaMapping{
-				SchemaID:    loadedSchema.ID,
-				PackageName: fmt.Sprintf("github.com/mfelipe/go-feijoada/schemas/models/%s/v%s", name, version),
-				OutputName:  fmt.Sprintf("models/%s/%s/%s.go", name, version, name),
-			})
-
-		}
-
-		files = append(files, absPath)
-		return nil
-	}); err != nil {
-		panic(err)
-	}
+	configFile := flag.String("config", "gen.yaml", "configuration file")
+	inputs := flag.String("inputs", "", "comma-separated globs of the schema files")
+	id := flag.String("id", "", "pattern of the schema IDs, capturing the name and version groups")
+	output := flag.String("output", "", "root directory of the generated files")
+	goPackage := flag.String("go-package", "", "template of the import path of the package of each model")
+	goFile := flag.String("go-file", "", "template of the file of each model")
+	tags := flag.String("tags", "", "comma-separated struct tags of the fields, such as json,yaml,mapstructure")
+	targets := flag.String("targets", "", "comma-separated extra targets: typescript, python")
+	validate := flag.Bool("validate", false, "generate validating unmarshalers, Validate methods and round-trip tests")
+	flag.Parse()
 
-	cfg := generator.Config{
-		Warner: func(message string) {
-			fmt.Printf("Warning: %s\n", message)
-		},
-		ExtraImports:              true,
-		SchemaMappings:            schemaMappings,
-		StructNameFromTitle:       true,
-		Tags:                      []string{"json"},
-		OnlyModels:                true,
-		MinSizedInts:              true,
-		MinimalNames:              false,
-		DisableReadOnlyValidation: false,
-		DisableCustomTypesForMaps: true,
-		Loader:                    loader,
-		DefaultPackageName:        "models",
-		ResolveExtensions:         []string{"json"},
-	}
-
-	gen, err := generator.New(cfg)
+	cfg, err := generator.LoadConfig(*configFile)
 	if err != nil {
-		panic(err)
+		fail(err)
 	}
-
-	for _, fileName := range files {
-		if err = gen.DoFile(fileName); err != nil {
-			panic(err)
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "inputs":
+			cfg.Inputs = split(*inputs)
+		case "id":
+			cfg.ID = *id
+		case "output":
+			cfg.Output = *output
+		case "go-package":
+			cfg.Go.Package = *goPackage
+		case "go-file":
+			cfg.Go.File = *goFile
+		case "tags":
+			cfg.Go.Tags = split(*tags)
+		case "targets":
+			cfg.Targets = selectTargets(cfg.Targets, split(*targets))
+		case "validate":
+			cfg.Go.Validate = *validate
 		}
-	}
+	})
 
-	sources, err := gen.Sources()
+	files, err := generator.Generate(cfg)
 	if err != nil {
-		panic(err)
+		fail(err)
 	}
+	if err = generator.Write(files); err != nil {
+		fail(err)
+	}
+}
 
-	for fileName, source := range sources {
-		if fileName == "-" {
-			if _, err = os.Stdout.Write(source); err != nil {
-				panic(err)
-			}
-		} else {
-			if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
-				panic(err)
-			}
-			w, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-			if err != nil {
-				panic(err)
+// selectTargets keeps the configured targets of languages, adding the ones not configured with their default file
+func selectTargets(configured []generator.Target, languages []string) []generator.Target {
+	selected := make([]generator.Target, 0, len(languages))
+	for _, language := range languages {
+		target := generator.Target{Language: language, File: generator.DefaultTargetFiles[language]}
+		for _, t := range configured {
+			if t.Language == language {
+				target = t
 			}
-			if _, err = w.Write(source); err != nil {
-				panic(err)
-			}
-			_ = w.Close()
 		}
+		selected = append(selected, target)
 	}
+	return selected
+}
+
+func split(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
 }
@@ -0,0 +1,181 @@
+package generator
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	pkgschemas "github.com/atombender/go-jsonschema/pkg/schemas"
+)
+
+// Kinds of the checks of a struct
+const (
+	// checkNil fails when a required array, map or object is nil, which marshals to null and round-trips unnoticed
+	checkNil = "nil"
+	// checkStruct, checkPointer and checkSlice check the nested structs of a field holding them
+	checkStruct  = "struct"
+	checkPointer = "pointer"
+	checkSlice   = "slice"
+)
+
+// checkedStruct is a struct of the models that Validate checks besides round-tripping it
+type checkedStruct struct {
+	Type   string
+	Checks []fieldCheck
+}
+
+type fieldCheck struct {
+	Kind string
+	// Field is the Go name of the field, and Property its JSON name
+	Field    string
+	Property string
+}
+
+// goField is a field of a generated struct
+type goField struct {
+	name     string
+	property string
+	typ      ast.Expr
+}
+
+// requiredChecks finds the checks of the structs of a model. The unmarshalers only check that required properties are
+// present, so they accept null for the required arrays, maps and objects, which nil fields marshal to
+func requiredChecks(m model, source []byte) ([]checkedStruct, error) {
+	structs, order, err := goStructs(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// The declarations don't name the structs as go-jsonschema does, so their structs are found through the fields
+	// holding them, the declarations of objects preceding the ones of their properties
+	checks := make(map[string][]fieldCheck)
+	goNames := map[string]string{m.Type: m.Type}
+	for _, d := range collect(m.Type, (*pkgschemas.Type)(m.schema.ObjectAsType)) {
+		name, ok := goNames[d.name]
+		if !ok || d.enum != nil {
+			continue
+		}
+		fields := structs[name]
+		for _, f := range d.fields {
+			i := slices.IndexFunc(fields, func(gf goField) bool { return gf.property == f.name })
+			if i < 0 {
+				continue
+			}
+			if ref, ok := namedElem(f.typ); ok {
+				if _, nested := nestedStruct(fields[i].typ); nested != "" {
+					goNames[ref] = nested
+				}
+			}
+			if !f.required || nullable(f.typ) {
+				continue
+			}
+			switch fields[i].typ.(type) {
+			case *ast.ArrayType, *ast.MapType, *ast.StarExpr:
+				checks[name] = append(checks[name], fieldCheck{Kind: checkNil, Field: fields[i].name, Property: f.name})
+			}
+		}
+	}
+
+	// Structs holding checked structs are checked too, until no more are found
+	for changed := true; changed; {
+		changed = false
+		for _, name := range order {
+			for _, f := range structs[name] {
+				kind, nested := nestedStruct(f.typ)
+				if _, checked := checks[nested]; !checked || hasCheck(checks[name], f.name, kind) {
+					continue
+				}
+				checks[name] = append(checks[name], fieldCheck{Kind: kind, Field: f.name, Property: f.property})
+				changed = true
+			}
+		}
+	}
+
+	var checked []checkedStruct
+	for _, name := range order {
+		if len(checks[name]) > 0 {
+			checked = append(checked, checkedStruct{Type: name, Checks: checks[name]})
+		}
+	}
+	return checked, nil
+}
+
+// goStructs reads the fields of the structs declared in a source, by struct name, along with the names in order
+func goStructs(source []byte) (map[string][]goField, []string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", source, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	structs := make(map[string][]goField)
+	var order []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+		var fields []goField
+		for _, f := range st.Fields.List {
+			if f.Tag == nil || len(f.Names) != 1 {
+				continue
+			}
+			tag, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				continue
+			}
+			property, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+			if property != "" && property != "-" {
+				fields = append(fields, goField{name: f.Names[0].Name, property: property, typ: f.Type})
+			}
+		}
+		structs[ts.Name.Name] = fields
+		order = append(order, ts.Name.Name)
+		return false
+	})
+	return structs, order, nil
+}
+
+// nestedStruct tells how a field holds a struct declared along with the model, and its name
+func nestedStruct(typ ast.Expr) (string, string) {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return checkStruct, t.Name
+	case *ast.StarExpr:
+		if ident, ok := t.X.(*ast.Ident); ok {
+			return checkPointer, ident.Name
+		}
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && t.Len == nil {
+			return checkSlice, ident.Name
+		}
+	}
+	return "", ""
+}
+
+// namedElem returns the name of the declaration of a type, or of its elements for arrays
+func namedElem(t typeRef) (string, bool) {
+	switch t.kind {
+	case "named":
+		return t.name, true
+	case "array":
+		return namedElem(*t.elem)
+	}
+	return "", false
+}
+
+func hasCheck(checks []fieldCheck, field, kind string) bool {
+	return slices.ContainsFunc(checks, func(c fieldCheck) bool { return c.Field == field && c.Kind == kind })
+}
+
+// nullable tells if null is a valid value of a type
+func nullable(t typeRef) bool {
+	return t.kind == "null" || t.kind == "any" || slices.ContainsFunc(t.union, nullable)
+}
@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_requiredChecks(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shipment.json"), []byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "http://example.com/schemas/shipment/1.0.0",
+  "title": "Shipment",
+  "type": "object",
+  "properties": {
+    "id": {"type": "string"},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "notes": {"type": ["array", "null"], "items": {"type": "string"}},
+    "carrier": {
+      "type": "object",
+      "properties": {"stops": {"type": "array", "items": {"type": "string"}}},
+      "required": ["stops"]
+    },
+    "returned": {
+      "type": "object",
+      "properties": {"reasons": {"type": "array", "items": {"type": "string"}}},
+      "required": ["reasons"]
+    },
+    "parcels": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {"labels": {"type": "array", "items": {"type": "string"}}},
+        "required": ["labels"]
+      }
+    }
+  },
+  "required": ["id", "tags", "notes", "carrier"]
+}`), 0644))
+
+	files, err := Generate(&Config{
+		Inputs: []string{filepath.Join(dir, "*.json")},
+		ID:     "/schemas/(?P<name>[^/]+)/(?P<version>[^/]+)$",
+		Output: dir,
+		Go:     Go{Package: "example.com/models/v{{ .Ident }}", File: "models/v{{ .Ident }}/{{ .Name }}.go", Tags: []string{"json"}, Validate: true},
+	})
+	require.NoError(t, err)
+
+	source := string(files[filepath.Join(dir, "models/v1_0_0/schemas.go")])
+	assert.Contains(t, source, `func (j Shipment) Validate() error {
+	if err := j.checkRequired(); err != nil {
+		return err
+	}`)
+	assert.Contains(t, source, `func (j Shipment) checkRequired() error {
+	if j.Tags == nil {
+		return errors.New("field tags in Shipment: required")
+	}
+	if err := j.Carrier.checkRequired(); err != nil {
+		return err
+	}
+	for _, item := range j.Parcels {
+		if err := item.checkRequired(); err != nil {
+			return err
+		}
+	}
+	if j.Returned != nil {
+		if err := j.Returned.checkRequired(); err != nil {
+			return err
+		}
+	}
+	return nil
+}`)
+	for _, nested := range []string{"ShipmentCarrier", "ShipmentParcelsElem", "ShipmentReturned"} {
+		assert.Contains(t, source, "func (j "+nested+") checkRequired() error {")
+	}
+	// null is a valid value of notes
+	assert.NotContains(t, source, "j.Notes")
+}
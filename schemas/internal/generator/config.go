@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+)
+
+// Languages of the extra targets
+const (
+	LanguageTypeScript = "typescript"
+	LanguagePython     = "python"
+)
+
+// Config of the generator, read from gen.yaml. Paths are relative to the working directory, and the templates of
+// packages and files are given the Name, Version (2.0.0), Ident (2_0_0) and ID of each schema
+type Config struct {
+	// Inputs are globs of the schema files
+	Inputs []string `json:"inputs" koanf:"inputs"`
+	// ID matches the $id of the schemas, capturing their name and version in the name and version named groups
+	ID string `json:"id" koanf:"id"`
+	// Output is the root directory of the generated files
+	Output  string   `json:"output" koanf:"output"`
+	Go      Go       `json:"go" koanf:"go"`
+	Targets []Target `json:"targets" koanf:"targets"`
+}
+
+// Go configures the generated Go models
+type Go struct {
+	// Package is the template of the import path of the package of each model
+	Package string `json:"package" koanf:"package"`
+	// File is the template of the file of each model
+	File string `json:"file" koanf:"file"`
+	// Tags are the struct tags of the fields, such as json, yaml and mapstructure
+	Tags []string `json:"tags" koanf:"tags"`
+	// Registry is the file of the registry mapping schema IDs to their model, in package RegistryPackage. It is not
+	// generated when empty
+	Registry        string `json:"registry" koanf:"registry"`
+	RegistryPackage string `json:"registryPackage" koanf:"registryPackage"`
+	// Validate generates unmarshalers enforcing required properties and enums, a Validate method per model, and tests
+	// round-tripping the models through their schema
+	Validate bool `json:"validate" koanf:"validate"`
+	// Conversions configures the converters between the versions of the models
+	Conversions Conversions `json:"conversions" koanf:"conversions"`
+}
+
+// Conversions configures the converters between the versions of the models
+type Conversions struct {
+	// Rules is the file declaring the conversions. Converters are not generated when empty
+	Rules string `json:"rules" koanf:"rules"`
+	// File is the file of the converters, in the package of the runtime of the conversions, such as models/convert
+	File string `json:"file" koanf:"file"`
+}
+
+// Target is an extra language the schemas are generated in
+type Target struct {
+	// Language is LanguageTypeScript or LanguagePython
+	Language string `json:"language" koanf:"language"`
+	// File is the template of the file of each schema
+	File string `json:"file" koanf:"file"`
+}
+
+// DefaultTargetFiles are the files of the targets selected by flag without being configured
+var DefaultTargetFiles = map[string]string{
+	LanguageTypeScript: "typescript/v{{ .Ident }}/{{ .Name }}.ts",
+	LanguagePython:     "python/v{{ .Ident }}/{{ .Name }}.py",
+}
+
+// LoadConfig reads a gen.yaml file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	k := koanf.New(".")
+	if err = k.Load(rawbytes.Provider(data), yaml.Parser()); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg := &Config{Output: ".", Go: Go{RegistryPackage: "schemas"}}
+	if err = k.Unmarshal("", cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the inputs, the ID pattern and the templates of the configuration
+func (c *Config) Validate() error {
+	if len(c.Inputs) == 0 {
+		return fmt.Errorf("no inputs")
+	}
+	id, err := regexp.Compile(c.ID)
+	if err != nil {
+		return fmt.Errorf("id: %w", err)
+	}
+	if id.SubexpIndex("name") < 0 || id.SubexpIndex("version") < 0 {
+		return fmt.Errorf("id must capture the name and version groups, got %q", c.ID)
+	}
+	if c.Go.Package == "" || c.Go.File == "" {
+		return fmt.Errorf("go package and file templates are required")
+	}
+	if c.Go.Conversions.Rules != "" && c.Go.Conversions.File == "" {
+		return fmt.Errorf("go conversions file is required along with their rules")
+	}
+	for i, t := range c.Targets {
+		if t.Language != LanguageTypeScript && t.Language != LanguagePython {
+			return fmt.Errorf("target %d: unknown language %q", i, t.Language)
+		}
+		if t.File == "" {
+			return fmt.Errorf("target %d: file template is required", i)
+		}
+	}
+	return nil
+}
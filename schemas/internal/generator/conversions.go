@@ -0,0 +1,322 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+)
+
+// Conversion declares the conversion between two versions of a schema, up from From to To and down back
+type Conversion struct {
+	Name string `json:"name" koanf:"name"`
+	From string `json:"from" koanf:"from"`
+	To   string `json:"to" koanf:"to"`
+	// Rename maps properties of From to their name in To, and the other way round when converting down
+	Rename map[string]string `json:"rename" koanf:"rename"`
+	Up     Direction         `json:"up" koanf:"up"`
+	Down   Direction         `json:"down" koanf:"down"`
+}
+
+// Direction declares the properties dropped converting a schema up or down, and the defaults of the ones missing
+type Direction struct {
+	Drop     []string       `json:"drop" koanf:"drop"`
+	Defaults map[string]any `json:"defaults" koanf:"defaults"`
+}
+
+// LoadConversions reads a file of conversion rules
+func LoadConversions(path string) ([]Conversion, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	k := koanf.New(".")
+	if err = k.Load(rawbytes.Provider(data), yaml.Parser()); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var conversions []Conversion
+	if err = k.Unmarshal("conversions", &conversions); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return conversions, nil
+}
+
+// converter is a generated converter, from a model to another
+type converter struct {
+	From model
+	To   model
+	Up   bool
+	// Func is the name of the function, such as OrderV1ToV2
+	Func string
+	// SchemaFile is the path of the schema of From from the directory of the converters
+	SchemaFile string
+	Rename     map[string]string
+	Drop       []string
+	// Defaults are the Go literals of the JSON values of the defaults
+	Defaults map[string]string
+}
+
+// conversions generates the converters between the versions of the models, and their tests
+func conversions(cfg *Config, models []model, files map[string][]byte) error {
+	if cfg.Go.Conversions.Rules == "" {
+		return nil
+	}
+	declared, err := LoadConversions(cfg.Go.Conversions.Rules)
+	if err != nil {
+		return err
+	}
+
+	byVersion := make(map[string]model, len(models))
+	for _, m := range models {
+		byVersion[m.Name+"@"+m.Version] = m
+	}
+	file := filepath.Join(cfg.Output, cfg.Go.Conversions.File)
+	dir := filepath.Dir(file)
+
+	var converters []converter
+	for _, c := range declared {
+		from, ok := byVersion[c.Name+"@"+c.From]
+		if !ok {
+			return fmt.Errorf("conversion of %s: no version %s", c.Name, c.From)
+		}
+		to, ok := byVersion[c.Name+"@"+c.To]
+		if !ok {
+			return fmt.Errorf("conversion of %s: no version %s", c.Name, c.To)
+		}
+		if compareVersions(c.From, c.To) >= 0 {
+			return fmt.Errorf("conversion of %s: %s must precede %s", c.Name, c.From, c.To)
+		}
+
+		down := make(map[string]string, len(c.Rename))
+		for property, renamed := range c.Rename {
+			down[renamed] = property
+		}
+		for _, cv := range []converter{
+			{From: from, To: to, Up: true, Rename: c.Rename, Drop: c.Up.Drop},
+			{From: to, To: from, Rename: down, Drop: c.Down.Drop},
+		} {
+			defaults := c.Up.Defaults
+			if !cv.Up {
+				defaults = c.Down.Defaults
+			}
+			if err = check(cv, defaults); err != nil {
+				return fmt.Errorf("conversion of %s from %s to %s: %w", c.Name, cv.From.Version, cv.To.Version, err)
+			}
+
+			cv.Func = cv.From.Type + versionName(cv.From.Version) + "To" + versionName(cv.To.Version)
+			if cv.SchemaFile, err = relative(dir, cv.From.input); err != nil {
+				return err
+			}
+			cv.Defaults = make(map[string]string, len(defaults))
+			for property, value := range defaults {
+				literal, err := json.Marshal(value)
+				if err != nil {
+					return fmt.Errorf("default of %s: %w", property, err)
+				}
+				cv.Defaults[property] = strconv.Quote(string(literal))
+			}
+			converters = append(converters, cv)
+		}
+	}
+	// upcasts skipping the most versions come first, as convert.Upcast takes the first one of a schema
+	slices.SortFunc(converters, func(a, b converter) int {
+		switch {
+		case a.From.Name != b.From.Name:
+			return strings.Compare(a.From.Name, b.From.Name)
+		case a.From.Version != b.From.Version:
+			return compareVersions(a.From.Version, b.From.Version)
+		case a.Up != b.Up:
+			if a.Up {
+				return -1
+			}
+			return 1
+		default:
+			return compareVersions(b.To.Version, a.To.Version)
+		}
+	})
+
+	packages := make(map[string]bool)
+	for _, cv := range converters {
+		packages[cv.From.Package] = true
+		packages[cv.To.Package] = true
+	}
+	data := map[string]any{
+		"Package":    path.Base(filepath.ToSlash(dir)),
+		"Imports":    slices.Sorted(maps.Keys(packages)),
+		"Converters": converters,
+		"Defaults": slices.ContainsFunc(converters, func(cv converter) bool {
+			return len(cv.Defaults) > 0
+		}),
+	}
+	if files[file], err = render(conversionsTemplate, data); err != nil {
+		return err
+	}
+	if cfg.Go.Validate && len(converters) > 0 {
+		name := strings.TrimSuffix(file, filepath.Ext(file)) + "_test.go"
+		if files[name], err = render(conversionsTestsTemplate, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check verifies that the rules of a converter account for every property of its source, and every required property
+// of its target
+func check(cv converter, defaults map[string]any) error {
+	source, target := cv.From.schema.Properties, cv.To.schema.Properties
+	for property, renamed := range cv.Rename {
+		if _, ok := source[property]; !ok {
+			return fmt.Errorf("renamed property %q isn't in %s", property, cv.From.ID)
+		}
+		if _, ok := target[renamed]; !ok {
+			return fmt.Errorf("property %q is renamed to %q, which isn't in %s", property, renamed, cv.To.ID)
+		}
+	}
+	for _, property := range cv.Drop {
+		if _, ok := source[property]; !ok {
+			return fmt.Errorf("dropped property %q isn't in %s", property, cv.From.ID)
+		}
+	}
+	for property := range defaults {
+		if _, ok := target[property]; !ok {
+			return fmt.Errorf("defaulted property %q isn't in %s", property, cv.To.ID)
+		}
+	}
+
+	produced := make(map[string]bool, len(source))
+	for _, property := range slices.Sorted(maps.Keys(source)) {
+		if slices.Contains(cv.Drop, property) {
+			continue
+		}
+		name := property
+		if renamed, ok := cv.Rename[property]; ok {
+			name = renamed
+		}
+		if _, ok := target[name]; !ok {
+			return fmt.Errorf("property %q isn't in %s: rename or drop it", property, cv.To.ID)
+		}
+		// optional properties of the source may be missing, leaving the target without them
+		produced[name] = slices.Contains(cv.From.schema.Required, property)
+	}
+	for _, property := range cv.To.schema.Required {
+		if _, ok := defaults[property]; !produced[property] && !ok {
+			return fmt.Errorf("required property %q of %s needs a default", property, cv.To.ID)
+		}
+	}
+	return nil
+}
+
+// versionName names a version in the converters, dropping its trailing zero segments, such as V1 for 1.0.0 or V1_2 for
+// 1.2.0
+func versionName(version string) string {
+	segments := strings.Split(version, ".")
+	for len(segments) > 1 && segments[len(segments)-1] == "0" {
+		segments = segments[:len(segments)-1]
+	}
+	return "V" + strings.NewReplacer("-", "_").Replace(strings.Join(segments, "_"))
+}
+
+// compareVersions compares dotted versions segment by segment, numerically when both segments are numbers
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(as), len(bs)) {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xErr := strconv.Atoi(x)
+		yn, yErr := strconv.Atoi(y)
+		switch {
+		case xErr == nil && yErr == nil && xn != yn:
+			if xn < yn {
+				return -1
+			}
+			return 1
+		case (xErr != nil || yErr != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+var conversionsTemplate = template.Must(template.New("conversions").Parse(`// Code generated by generate.go, DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+{{- if .Defaults }}
+	"encoding/json"
+{{ end }}
+{{- range .Imports }}
+	"{{ . }}"
+{{- end }}
+)
+
+// Conversions between the versions of the schemas, the upcasts skipping the most versions first
+var Conversions = []Conversion{
+{{- range .Converters }}
+	{
+		From: {{ .From.PackageName }}.{{ .From.Type }}SchemaID,
+		To:   {{ .To.PackageName }}.{{ .To.Type }}SchemaID,
+		{{- if .Up }}
+		Up:   true,
+		{{- end }}
+		{{- if or .Rename .Drop .Defaults }}
+		Rules: Rules{
+		{{- if .Rename }}
+			Rename: map[string]string{
+			{{- range $from, $to := .Rename }}
+				"{{ $from }}": "{{ $to }}",
+			{{- end }}
+			},
+		{{- end }}
+		{{- if .Drop }}
+			Drop: []string{ {{- range $i, $p := .Drop }}{{ if $i }}, {{ end }}"{{ $p }}"{{ end -}} },
+		{{- end }}
+		{{- if .Defaults }}
+			Defaults: map[string]json.RawMessage{
+			{{- range $property, $value := .Defaults }}
+				"{{ $property }}": json.RawMessage({{ $value }}),
+			{{- end }}
+			},
+		{{- end }}
+		},
+		{{- end }}
+	},
+{{- end }}
+}
+{{ range .Converters }}
+// {{ .Func }} converts the {{ .From.Type }} model from {{ .From.Version }} to {{ .To.Version }}
+func {{ .Func }}(from {{ .From.PackageName }}.{{ .From.Type }}) ({{ .To.PackageName }}.{{ .To.Type }}, error) {
+	return model[{{ .To.PackageName }}.{{ .To.Type }}](from, {{ .From.PackageName }}.{{ .From.Type }}SchemaID, {{ .To.PackageName }}.{{ .To.Type }}SchemaID)
+}
+{{ end }}`))
+
+var conversionsTestsTemplate = template.Must(template.New("conversionsTests").Parse(`// Code generated by generate.go, DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"testing"
+
+	"github.com/mfelipe/go-feijoada/schemas/internal/modeltest"
+)
+{{ range .Converters }}
+func Test{{ .Func }}(t *testing.T) {
+	modeltest.Convert(t, "{{ .SchemaFile }}", {{ .Func }})
+}
+{{ end }}`))
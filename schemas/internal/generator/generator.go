@@ -0,0 +1,436 @@
+// Package generator generates the Go models of the JSON schemas of the module, along with their constants, registry,
+// round-trip tests and converters between versions, and the types of the extra targets, such as TypeScript and Python. It drives go-jsonschema
+// programmatically, as its command line doesn't offer much configuration on input and output, naming the files,
+// packages and structs from values extracted from the JSON schemas.
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	jsonschema "github.com/atombender/go-jsonschema/pkg/generator"
+	pkgschemas "github.com/atombender/go-jsonschema/pkg/schemas"
+
+	"github.com/mfelipe/go-feijoada/schemas/internal/loaders"
+)
+
+// Schema is given to the templates of packages and files
+type Schema struct {
+	ID      string
+	Name    string
+	Version string
+	// Ident is the version as an identifier, such as 2_0_0
+	Ident string
+}
+
+// model is the generated Go model of a schema
+type model struct {
+	Schema
+	// Package is the import path of the package of the model, and PackageName its name
+	Package     string
+	PackageName string
+	// Type is the name of the struct of the model
+	Type string
+	// SchemaFile is the path of the schema from the directory of the model
+	SchemaFile string
+	// Checks are the structs of the model Validate checks besides round-tripping it, Checked telling if the model is one
+	Checks  []checkedStruct
+	Checked bool
+
+	schema *pkgschemas.Schema
+	input  string
+	file   string
+}
+
+// Generate generates the files of the schemas matching the inputs of cfg, by path
+func Generate(cfg *Config) (map[string][]byte, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	id := regexp.MustCompile(cfg.ID)
+	packageTemplate, err := template.New("package").Parse(cfg.Go.Package)
+	if err != nil {
+		return nil, fmt.Errorf("go package: %w", err)
+	}
+	fileTemplate, err := template.New("file").Parse(cfg.Go.File)
+	if err != nil {
+		return nil, fmt.Errorf("go file: %w", err)
+	}
+
+	inputs, err := expand(cfg.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := loaders.NewCachedFileLoader()
+	mappings := make([]jsonschema.SchemaMapping, 0, len(inputs))
+	models := make([]model, 0, len(inputs))
+	for _, input := range inputs {
+		schema, err := loader.Load(input, "")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", input, err)
+		}
+		match := id.FindStringSubmatch(schema.ID)
+		if match == nil {
+			return nil, fmt.Errorf("%s: $id %q doesn't match %s", input, schema.ID, cfg.ID)
+		}
+
+		m := model{
+			Schema: Schema{
+				ID:      schema.ID,
+				Name:    match[id.SubexpIndex("name")],
+				Version: match[id.SubexpIndex("version")],
+			},
+			schema: schema,
+			input:  input,
+		}
+		m.Ident = strings.NewReplacer(".", "_", "-", "_").Replace(m.Version)
+		if m.Package, err = execute(packageTemplate, m.Schema); err != nil {
+			return nil, err
+		}
+		if m.file, err = execute(fileTemplate, m.Schema); err != nil {
+			return nil, err
+		}
+		m.PackageName = path.Base(m.Package)
+		m.file = filepath.Join(cfg.Output, m.file)
+		if m.SchemaFile, err = relative(filepath.Dir(m.file), input); err != nil {
+			return nil, err
+		}
+
+		mappings = append(mappings, jsonschema.SchemaMapping{SchemaID: schema.ID, PackageName: m.Package, OutputName: m.file})
+		models = append(models, m)
+	}
+
+	files, err := goModels(cfg.Go, loader, mappings, inputs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range models {
+		if models[i].Type, err = structName(files[models[i].file], models[i].schema.Title); err != nil {
+			return nil, fmt.Errorf("finding the model of %s: %w", models[i].ID, err)
+		}
+	}
+	slices.SortFunc(models, func(a, b model) int {
+		return strings.Compare(a.Package+"/"+a.Name, b.Package+"/"+b.Name)
+	})
+
+	if err = goPackages(cfg, models, files); err != nil {
+		return nil, err
+	}
+	if err = conversions(cfg, models, files); err != nil {
+		return nil, err
+	}
+	if err = targets(cfg, models, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Write writes the generated files, creating their directories
+func Write(files map[string][]byte) error {
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(name, files[name], 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expand returns the absolute paths of the files matching globs, sorted
+func expand(globs []string) ([]string, error) {
+	var inputs []string
+	for _, glob := range globs {
+		matches, err := filepath.Glob(glob)
+		if err != nil {
+			return nil, fmt.Errorf("input %q: %w", glob, err)
+		}
+		for _, match := range matches {
+			abs, err := filepath.Abs(match)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, abs)
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no schema matches %s", strings.Join(globs, ", "))
+	}
+	slices.Sort(inputs)
+	return slices.Compact(inputs), nil
+}
+
+func relative(dir, file string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absDir, file)
+	return filepath.ToSlash(rel), err
+}
+
+func execute(t *template.Template, data any) (string, error) {
+	var buf strings.Builder
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// goModels runs go-jsonschema on the inputs
+func goModels(cfg Go, loader *loaders.CachedFileLoader, mappings []jsonschema.SchemaMapping, inputs []string) (map[string][]byte, error) {
+	gen, err := jsonschema.New(jsonschema.Config{
+		Warner: func(message string) {
+			fmt.Printf("Warning: %s\n", message)
+		},
+		ExtraImports:              true,
+		SchemaMappings:            mappings,
+		StructNameFromTitle:       true,
+		Tags:                      cfg.Tags,
+		OnlyModels:                !cfg.Validate,
+		MinSizedInts:              false,
+		MinimalNames:              false,
+		DisableReadOnlyValidation: false,
+		DisableCustomTypesForMaps: true,
+		Loader:                    loader,
+		DefaultPackageName:        "models",
+		ResolveExtensions:         []string{"json"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, input := range inputs {
+		if err = gen.DoFile(input); err != nil {
+			return nil, err
+		}
+	}
+	return gen.Sources()
+}
+
+// goPackages generates the constants, Validate methods and round-trip tests of the packages of the models, and the
+// registry
+func goPackages(cfg *Config, models []model, files map[string][]byte) error {
+	byDir := make(map[string][]model)
+	for _, m := range models {
+		dir := filepath.Dir(m.file)
+		if others := byDir[dir]; len(others) > 0 && others[0].Package != m.Package {
+			return fmt.Errorf("packages %s and %s share directory %s", others[0].Package, m.Package, dir)
+		}
+		byDir[dir] = append(byDir[dir], m)
+	}
+
+	var err error
+	for dir, dirModels := range byDir {
+		var checked bool
+		for i, m := range dirModels {
+			if !cfg.Go.Validate {
+				break
+			}
+			if m.Checks, err = requiredChecks(m, files[m.file]); err != nil {
+				return fmt.Errorf("checking the model of %s: %w", m.ID, err)
+			}
+			m.Checked = slices.ContainsFunc(m.Checks, func(c checkedStruct) bool { return c.Type == m.Type })
+			checked = checked || len(m.Checks) > 0
+			dirModels[i] = m
+		}
+
+		data := map[string]any{
+			"Package":  dirModels[0].PackageName,
+			"Models":   dirModels,
+			"Validate": cfg.Go.Validate,
+			"Checked":  checked,
+		}
+		if files[filepath.Join(dir, "schemas.go")], err = render(constantsTemplate, data); err != nil {
+			return err
+		}
+		if !cfg.Go.Validate {
+			continue
+		}
+		if files[filepath.Join(dir, "schemas_test.go")], err = render(testsTemplate, data); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Go.Registry == "" {
+		return nil
+	}
+	packages := make(map[string]string)
+	for _, m := range models {
+		packages[m.Package] = m.PackageName
+	}
+	data := map[string]any{
+		"Package": cfg.Go.RegistryPackage,
+		"Imports": slices.Sorted(maps.Keys(packages)),
+		"Models":  models,
+	}
+	files[filepath.Join(cfg.Output, cfg.Go.Registry)], err = render(registryTemplate, data)
+	return err
+}
+
+// structName finds the struct generated for the root of a schema in its source: the one named after its title, or the
+// first one declared
+func structName(source []byte, title string) (string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", source, parser.SkipObjectResolution)
+	if err != nil {
+		return "", err
+	}
+
+	normalized := strings.ToLower(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, title))
+	var first string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if _, ok = ts.Type.(*ast.StructType); !ok {
+				continue
+			}
+			if strings.ToLower(ts.Name.Name) == normalized {
+				return ts.Name.Name, nil
+			}
+			if first == "" {
+				first = ts.Name.Name
+			}
+		}
+	}
+	if first == "" {
+		return "", fmt.Errorf("no struct generated")
+	}
+	return first, nil
+}
+
+func render(t *template.Template, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+var constantsTemplate = template.Must(template.New("constants").Parse(`// Code generated by generate.go, DO NOT EDIT.
+
+package {{ .Package }}
+{{ if .Validate }}
+import (
+	"encoding/json"
+{{- if .Checked }}
+	"errors"
+{{- end }}
+)
+{{ end }}
+// Schemas of the models of the package: their ID, name and version
+const (
+{{- range .Models }}
+	{{ .Type }}SchemaID = "{{ .ID }}"
+	{{ .Type }}SchemaName = "{{ .Name }}"
+	{{ .Type }}SchemaVersion = "{{ .Version }}"
+{{- end }}
+)
+{{- if .Validate }}
+{{ range .Models }}
+// Validate checks the {{ .Type }} against the constraints its unmarshaler enforces, such as its required properties and enums
+{{- if .Checks }},
+// and that its required arrays, maps and objects aren't nil
+{{- end }}
+func (j {{ .Type }}) Validate() error {
+{{- if .Checked }}
+	if err := j.checkRequired(); err != nil {
+		return err
+	}
+{{- end }}
+	value, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(value, new({{ .Type }}))
+}
+{{ range $s := .Checks }}
+// checkRequired checks that the required arrays, maps and objects of the {{ $s.Type }} aren't nil,
+// which its unmarshaler can't tell from null
+func (j {{ $s.Type }}) checkRequired() error {
+{{- range $s.Checks }}
+{{- if eq .Kind "nil" }}
+	if j.{{ .Field }} == nil {
+		return errors.New("field {{ .Property }} in {{ $s.Type }}: required")
+	}
+{{- else if eq .Kind "struct" }}
+	if err := j.{{ .Field }}.checkRequired(); err != nil {
+		return err
+	}
+{{- else if eq .Kind "pointer" }}
+	if j.{{ .Field }} != nil {
+		if err := j.{{ .Field }}.checkRequired(); err != nil {
+			return err
+		}
+	}
+{{- else }}
+	for _, item := range j.{{ .Field }} {
+		if err := item.checkRequired(); err != nil {
+			return err
+		}
+	}
+{{- end }}
+{{- end }}
+	return nil
+}
+{{ end }}
+{{- end }}
+{{- end }}
+`))
+
+var testsTemplate = template.Must(template.New("tests").Parse(`// Code generated by generate.go, DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"testing"
+
+	"github.com/mfelipe/go-feijoada/schemas/internal/modeltest"
+)
+{{ range .Models }}
+func Test{{ .Type }}_RoundTrip(t *testing.T) {
+	modeltest.RoundTrip[{{ .Type }}](t, {{ .Type }}SchemaID, "{{ .SchemaFile }}")
+}
+{{ end }}`))
+
+var registryTemplate = template.Must(template.New("registry").Parse(`// Code generated by generate.go, DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"reflect"
+{{ range .Imports }}
+	"{{ . }}"
+{{- end }}
+)
+
+// Types maps the ID of each schema to the type of its model
+var Types = map[string]reflect.Type{
+{{- range .Models }}
+	{{ .PackageName }}.{{ .Type }}SchemaID: reflect.TypeFor[{{ .PackageName }}.{{ .Type }}](),
+{{- end }}
+}
+`))
@@ -0,0 +1,343 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig("../../gen.yaml")
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	assert.Equal(t, []string{"schemas/*.json"}, cfg.Inputs)
+	assert.Equal(t, ".", cfg.Output)
+	assert.Equal(t, []string{"json", "yaml", "mapstructure"}, cfg.Go.Tags)
+	assert.Equal(t, "schemas", cfg.Go.RegistryPackage)
+	assert.True(t, cfg.Go.Validate)
+	assert.Equal(t, []Target{
+		{Language: LanguageTypeScript, File: DefaultTargetFiles[LanguageTypeScript]},
+		{Language: LanguagePython, File: DefaultTargetFiles[LanguagePython]},
+	}, cfg.Targets)
+
+	_, err = LoadConfig("missing.yaml")
+	assert.Error(t, err)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() Config {
+		return Config{
+			Inputs: []string{"schemas/*.json"},
+			ID:     "/schemas/(?P<name>[^/]+)/(?P<version>[^/]+)$",
+			Go:     Go{Package: "example.com/models/v{{ .Ident }}", File: "models/v{{ .Ident }}/{{ .Name }}.go"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		err    string
+	}{
+		{name: "valid", modify: func(c *Config) {}},
+		{name: "no inputs", modify: func(c *Config) { c.Inputs = nil }, err: "no inputs"},
+		{name: "invalid id", modify: func(c *Config) { c.ID = "(" }, err: "id: error parsing regexp"},
+		{
+			name:   "id without version",
+			modify: func(c *Config) { c.ID = "/schemas/(?P<name>[^/]+)" },
+			err:    `id must capture the name and version groups, got "/schemas/(?P<name>[^/]+)"`,
+		},
+		{name: "no go file", modify: func(c *Config) { c.Go.File = "" }, err: "go package and file templates are required"},
+		{
+			name:   "unknown language",
+			modify: func(c *Config) { c.Targets = []Target{{Language: "rust", File: "{{ .Name }}.rs"}} },
+			err:    `target 0: unknown language "rust"`,
+		},
+		{
+			name:   "target without file",
+			modify: func(c *Config) { c.Targets = []Target{{Language: LanguagePython}} },
+			err:    "target 0: file template is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+// TestGenerate checks that the generator reproduces the committed files of the module
+func TestGenerate(t *testing.T) {
+	cfg, err := LoadConfig("../../gen.yaml")
+	require.NoError(t, err)
+	cfg.Inputs = []string{"../../schemas/*.json"}
+	cfg.Output = t.TempDir()
+	cfg.Go.Conversions.Rules = "../../conversions.yaml"
+
+	files, err := Generate(cfg)
+	require.NoError(t, err)
+	require.NoError(t, Write(files))
+
+	for name, source := range files {
+		rel, err := filepath.Rel(cfg.Output, name)
+		require.NoError(t, err)
+		if strings.HasSuffix(rel, "_test.go") {
+			// the path of the schemas depends on the output
+			continue
+		}
+		committed, err := os.ReadFile(filepath.Join("../..", rel))
+		require.NoError(t, err, rel)
+		assert.Equal(t, string(committed), string(source), rel)
+	}
+	assert.Contains(t, files, filepath.Join(cfg.Output, "typescript/v2_0_0/order.ts"))
+	assert.Contains(t, files, filepath.Join(cfg.Output, "python/v1_0_0/user.py"))
+}
+
+func TestGenerate_targets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shipment.json"), []byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "http://example.com/schemas/shipment/1.0.0",
+  "title": "Shipment",
+  "type": "object",
+  "properties": {
+    "id": {"type": "string"},
+    "priority": {"enum": [1, 2, 3]},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "carrier": {
+      "type": "object",
+      "description": "Carrier of the shipment",
+      "properties": {"name": {"type": "string"}},
+      "required": ["name"]
+    },
+    "metadata": {"type": "object"},
+    "note": {"type": ["string", "null"]}
+  },
+  "required": ["id", "carrier"]
+}`), 0644))
+
+	files, err := Generate(&Config{
+		Inputs: []string{filepath.Join(dir, "*.json")},
+		ID:     "/schemas/(?P<name>[^/]+)/(?P<version>[^/]+)$",
+		Output: dir,
+		Go:     Go{Package: "example.com/models/v{{ .Ident }}", File: "models/v{{ .Ident }}/{{ .Name }}.go", Tags: []string{"json"}},
+		Targets: []Target{
+			{Language: LanguageTypeScript, File: "ts/{{ .Name }}_{{ .Ident }}.ts"},
+			{Language: LanguagePython, File: "py/{{ .Name }}_{{ .Ident }}.py"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `// Code generated by generate.go, DO NOT EDIT.
+
+export const ShipmentSchemaID = "http://example.com/schemas/shipment/1.0.0";
+
+export interface Shipment {
+  /** Carrier of the shipment */
+  carrier: ShipmentCarrier;
+  id: string;
+  metadata?: Record<string, unknown>;
+  note?: string | null;
+  priority?: ShipmentPriority;
+  tags?: string[];
+}
+
+/** Carrier of the shipment */
+export interface ShipmentCarrier {
+  name: string;
+}
+
+export type ShipmentPriority = 1 | 2 | 3;
+`, string(files[filepath.Join(dir, "ts/shipment_1_0_0.ts")]))
+
+	assert.Equal(t, `# Code generated by generate.go, DO NOT EDIT.
+
+from __future__ import annotations
+
+from dataclasses import dataclass
+from typing import Any, Literal, Optional
+
+SHIPMENT_SCHEMA_ID = "http://example.com/schemas/shipment/1.0.0"
+
+
+@dataclass
+class Shipment:
+    #: Carrier of the shipment
+    carrier: ShipmentCarrier
+    id: str
+    metadata: Optional[dict[str, Any]] = None
+    note: Optional[str | None] = None
+    priority: Optional[ShipmentPriority] = None
+    tags: Optional[list[str]] = None
+
+
+@dataclass
+class ShipmentCarrier:
+    """Carrier of the shipment"""
+
+    name: str
+
+
+ShipmentPriority = Literal[1, 2, 3]
+`, string(files[filepath.Join(dir, "py/shipment_1_0_0.py")]))
+	assert.NotContains(t, files, filepath.Join(dir, "registry.go"))
+}
+
+func TestGenerate_conversions(t *testing.T) {
+	dir := t.TempDir()
+	for name, schema := range map[string]string{
+		"customer-1.0.0.json": `{
+  "$id": "http://example.com/schemas/customer/1.0.0",
+  "title": "Customer",
+  "type": "object",
+  "properties": {"id": {"type": "integer"}, "name": {"type": "string"}, "fax": {"type": "string"}},
+  "required": ["id", "name"]
+}`,
+		"customer-2.0.0.json": `{
+  "$id": "http://example.com/schemas/customer/2.0.0",
+  "title": "Customer",
+  "type": "object",
+  "properties": {"id": {"type": "integer"}, "fullName": {"type": "string"}, "country": {"type": "string"}},
+  "required": ["id", "fullName", "country"]
+}`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(schema), 0644))
+	}
+
+	tests := []struct {
+		name     string
+		rules    string
+		contains []string
+		err      string
+	}{
+		{
+			name: "valid",
+			rules: `
+conversions:
+  - name: customer
+    from: "1.0.0"
+    to: "2.0.0"
+    rename: {name: fullName}
+    up:
+      drop: [fax]
+      defaults: {country: "BR"}
+    down:
+      drop: [country]
+`,
+			contains: []string{
+				"Rename: map[string]string{\n\t\t\t\t\"name\": \"fullName\",\n\t\t\t},",
+				"Drop: []string{\"fax\"},",
+				"\"country\": json.RawMessage(\"\\\"BR\\\"\"),",
+				"Rename: map[string]string{\n\t\t\t\t\"fullName\": \"name\",\n\t\t\t},\n\t\t\tDrop: []string{\"country\"},",
+				"func CustomerV1ToV2(from v1_0_0.Customer) (v2_0_0.Customer, error) {",
+				"func CustomerV2ToV1(from v2_0_0.Customer) (v1_0_0.Customer, error) {",
+			},
+		},
+		{
+			name: "unknown version",
+			rules: `
+conversions:
+  - {name: customer, from: "1.0.0", to: "3.0.0"}
+`,
+			err: "conversion of customer: no version 3.0.0",
+		},
+		{
+			name: "versions out of order",
+			rules: `
+conversions:
+  - {name: customer, from: "2.0.0", to: "1.0.0"}
+`,
+			err: "conversion of customer: 2.0.0 must precede 1.0.0",
+		},
+		{
+			name: "property not accounted for",
+			rules: `
+conversions:
+  - name: customer
+    from: "1.0.0"
+    to: "2.0.0"
+    rename: {name: fullName}
+    up: {defaults: {country: "BR"}}
+    down: {drop: [country]}
+`,
+			err: `conversion of customer from 1.0.0 to 2.0.0: property "fax" isn't in http://example.com/schemas/customer/2.0.0: rename or drop it`,
+		},
+		{
+			name: "required property without default",
+			rules: `
+conversions:
+  - name: customer
+    from: "1.0.0"
+    to: "2.0.0"
+    rename: {name: fullName}
+    up: {drop: [fax]}
+    down: {drop: [country]}
+`,
+			err: `conversion of customer from 1.0.0 to 2.0.0: required property "country" of http://example.com/schemas/customer/2.0.0 needs a default`,
+		},
+		{
+			name: "renamed property missing",
+			rules: `
+conversions:
+  - {name: customer, from: "1.0.0", to: "2.0.0", rename: {surname: fullName}}
+`,
+			err: `renamed property "surname" isn't in http://example.com/schemas/customer/1.0.0`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := filepath.Join(t.TempDir(), "conversions.yaml")
+			require.NoError(t, os.WriteFile(rules, []byte(tt.rules), 0644))
+
+			files, err := Generate(&Config{
+				Inputs: []string{filepath.Join(dir, "*.json")},
+				ID:     "/schemas/(?P<name>[^/]+)/(?P<version>[^/]+)$",
+				Output: dir,
+				Go: Go{
+					Package:     "example.com/models/v{{ .Ident }}",
+					File:        "models/v{{ .Ident }}/{{ .Name }}.go",
+					Tags:        []string{"json"},
+					Validate:    true,
+					Conversions: Conversions{Rules: rules, File: "models/convert/conversions.go"},
+				},
+			})
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			source := string(files[filepath.Join(dir, "models/convert/conversions.go")])
+			for _, s := range tt.contains {
+				assert.Contains(t, source, s)
+			}
+			assert.Contains(t, string(files[filepath.Join(dir, "models/convert/conversions_test.go")]),
+				`modeltest.Convert(t, "../../customer-1.0.0.json", CustomerV1ToV2)`)
+		})
+	}
+}
+
+func TestVersions(t *testing.T) {
+	assert.Equal(t, "V1", versionName("1.0.0"))
+	assert.Equal(t, "V1_2", versionName("1.2.0"))
+	assert.Equal(t, "V2_0_1", versionName("2.0.1"))
+	assert.Equal(t, "V0", versionName("0"))
+
+	assert.Equal(t, -1, compareVersions("1.0.0", "2.0.0"))
+	assert.Equal(t, 1, compareVersions("1.10.0", "1.9.0"))
+	assert.Equal(t, 0, compareVersions("1.0.0", "1.0.0"))
+	assert.Equal(t, -1, compareVersions("1.0", "1.0.1"))
+}
@@ -0,0 +1,387 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	pkgschemas "github.com/atombender/go-jsonschema/pkg/schemas"
+)
+
+// typeRef is the type of a value in the extra targets: a JSON type, an array of elem, a declared type, or a union
+type typeRef struct {
+	// kind is a JSON type, "array", "map", "any" or "named"
+	kind  string
+	elem  *typeRef
+	name  string
+	union []typeRef
+}
+
+// decl is a type declared for a schema: an object with fields, or an enum
+type decl struct {
+	name        string
+	description string
+	fields      []field
+	enum        []any
+}
+
+type field struct {
+	name        string
+	description string
+	typ         typeRef
+	required    bool
+}
+
+// collect declares the types of the root of a schema, the nested objects and enums being named after their parent and
+// property, as the Go models are
+func collect(name string, schema *pkgschemas.Type) []decl {
+	var decls []decl
+	var walk func(name string, t *pkgschemas.Type) typeRef
+	walk = func(name string, t *pkgschemas.Type) typeRef {
+		switch {
+		case t == nil:
+			return typeRef{kind: "any"}
+		case len(t.Enum) > 0:
+			decls = append(decls, decl{name: name, description: t.Description, enum: t.Enum})
+			return typeRef{kind: "named", name: name}
+		case len(t.Type) > 1:
+			union := make([]typeRef, len(t.Type))
+			for i, jsonType := range t.Type {
+				single := *t
+				single.Type = pkgschemas.TypeList{jsonType}
+				union[i] = walk(name, &single)
+			}
+			return typeRef{kind: "union", union: union}
+		case len(t.Type) == 0 && len(t.Properties) == 0:
+			return typeRef{kind: "any"}
+		}
+
+		switch jsonType := "object"; {
+		case len(t.Type) == 1:
+			jsonType = t.Type[0]
+			fallthrough
+		default:
+			switch jsonType {
+			case "array":
+				elem := walk(name+"Item", t.Items)
+				return typeRef{kind: "array", elem: &elem}
+			case "object":
+				if len(t.Properties) == 0 {
+					return typeRef{kind: "map"}
+				}
+				d := decl{name: name, description: t.Description}
+				i := len(decls)
+				decls = append(decls, d)
+				for _, property := range slices.Sorted(func(yield func(string) bool) {
+					for p := range t.Properties {
+						if !yield(p) {
+							return
+						}
+					}
+				}) {
+					d.fields = append(d.fields, field{
+						name:        property,
+						description: t.Properties[property].Description,
+						typ:         walk(name+capitalize(property), t.Properties[property]),
+						required:    slices.Contains(t.Required, property),
+					})
+				}
+				decls[i] = d
+				return typeRef{kind: "named", name: name}
+			default:
+				return typeRef{kind: jsonType}
+			}
+		}
+	}
+	walk(name, schema)
+	return decls
+}
+
+func capitalize(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// targets generates the types of the extra targets
+func targets(cfg *Config, models []model, files map[string][]byte) error {
+	for _, target := range cfg.Targets {
+		fileTemplate, err := template.New("file").Parse(target.File)
+		if err != nil {
+			return fmt.Errorf("%s file: %w", target.Language, err)
+		}
+		render := typescript
+		if target.Language == LanguagePython {
+			render = python
+		}
+
+		for _, m := range models {
+			file, err := execute(fileTemplate, m.Schema)
+			if err != nil {
+				return err
+			}
+			files[filepath.Join(cfg.Output, file)] = render(m, collect(m.Type, (*pkgschemas.Type)(m.schema.ObjectAsType)))
+		}
+	}
+	return nil
+}
+
+func literal(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// typescript renders the interfaces and enums of a model
+func typescript(m model, decls []decl) []byte {
+	var b strings.Builder
+	b.WriteString("// Code generated by generate.go, DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "export const %sSchemaID = %s;\n", m.Type, literal(m.ID))
+
+	var typeOf func(t typeRef) string
+	typeOf = func(t typeRef) string {
+		switch t.kind {
+		case "string":
+			return "string"
+		case "integer", "number":
+			return "number"
+		case "boolean":
+			return "boolean"
+		case "null":
+			return "null"
+		case "array":
+			elem := typeOf(*t.elem)
+			if t.elem.kind == "union" {
+				elem = "(" + elem + ")"
+			}
+			return elem + "[]"
+		case "map":
+			return "Record<string, unknown>"
+		case "named":
+			return t.name
+		case "union":
+			types := make([]string, len(t.union))
+			for i, u := range t.union {
+				types[i] = typeOf(u)
+			}
+			return strings.Join(types, " | ")
+		default:
+			return "unknown"
+		}
+	}
+
+	for _, d := range decls {
+		b.WriteString("\n")
+		if d.description != "" {
+			fmt.Fprintf(&b, "/** %s */\n", d.description)
+		}
+		if d.enum != nil {
+			values := make([]string, len(d.enum))
+			for i, v := range d.enum {
+				values[i] = literal(v)
+			}
+			fmt.Fprintf(&b, "export type %s = %s;\n", d.name, strings.Join(values, " | "))
+			continue
+		}
+
+		fmt.Fprintf(&b, "export interface %s {\n", d.name)
+		for _, f := range d.fields {
+			if f.description != "" {
+				fmt.Fprintf(&b, "  /** %s */\n", f.description)
+			}
+			optional := "?"
+			if f.required {
+				optional = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", f.name, optional, typeOf(f.typ))
+		}
+		b.WriteString("}\n")
+	}
+	return []byte(b.String())
+}
+
+// python renders the dataclasses and enums of a model
+func python(m model, decls []decl) []byte {
+	typing := map[string]bool{}
+	usesEnum := false
+
+	var typeOf func(t typeRef) string
+	typeOf = func(t typeRef) string {
+		switch t.kind {
+		case "string":
+			return "str"
+		case "integer":
+			return "int"
+		case "number":
+			return "float"
+		case "boolean":
+			return "bool"
+		case "null":
+			return "None"
+		case "array":
+			return "list[" + typeOf(*t.elem) + "]"
+		case "map":
+			typing["Any"] = true
+			return "dict[str, Any]"
+		case "named":
+			return t.name
+		case "union":
+			types := make([]string, len(t.union))
+			for i, u := range t.union {
+				types[i] = typeOf(u)
+			}
+			return strings.Join(types, " | ")
+		default:
+			typing["Any"] = true
+			return "Any"
+		}
+	}
+
+	var body strings.Builder
+	for _, d := range decls {
+		body.WriteString("\n\n")
+		if d.enum != nil {
+			if !allStrings(d.enum) {
+				typing["Literal"] = true
+				values := make([]string, len(d.enum))
+				for i, v := range d.enum {
+					values[i] = pythonLiteral(v)
+				}
+				fmt.Fprintf(&body, "%s = Literal[%s]\n", d.name, strings.Join(values, ", "))
+				continue
+			}
+			usesEnum = true
+			fmt.Fprintf(&body, "class %s(str, Enum):\n", d.name)
+			if d.description != "" {
+				fmt.Fprintf(&body, "    %s\n\n", docstring(d.description))
+			}
+			for _, v := range d.enum {
+				fmt.Fprintf(&body, "    %s = %s\n", enumMember(v.(string)), pythonLiteral(v))
+			}
+			continue
+		}
+
+		fmt.Fprintf(&body, "@dataclass\nclass %s:\n", d.name)
+		if d.description != "" {
+			fmt.Fprintf(&body, "    %s\n", docstring(d.description))
+			if len(d.fields) > 0 {
+				body.WriteString("\n")
+			}
+		}
+		if len(d.fields) == 0 && d.description == "" {
+			body.WriteString("    pass\n")
+		}
+		// fields without a default come first, as dataclasses require
+		fields := slices.Clone(d.fields)
+		slices.SortStableFunc(fields, func(a, b field) int {
+			switch {
+			case a.required == b.required:
+				return 0
+			case a.required:
+				return -1
+			default:
+				return 1
+			}
+		})
+		for _, f := range fields {
+			if f.description != "" {
+				fmt.Fprintf(&body, "    #: %s\n", f.description)
+			}
+			if f.required {
+				fmt.Fprintf(&body, "    %s: %s\n", f.name, typeOf(f.typ))
+				continue
+			}
+			typing["Optional"] = true
+			fmt.Fprintf(&body, "    %s: Optional[%s] = None\n", f.name, typeOf(f.typ))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# Code generated by generate.go, DO NOT EDIT.\n\n")
+	b.WriteString("from __future__ import annotations\n\n")
+	b.WriteString("from dataclasses import dataclass\n")
+	if usesEnum {
+		b.WriteString("from enum import Enum\n")
+	}
+	if len(typing) > 0 {
+		fmt.Fprintf(&b, "from typing import %s\n", strings.Join(slices.Sorted(func(yield func(string) bool) {
+			for name := range typing {
+				if !yield(name) {
+					return
+				}
+			}
+		}), ", "))
+	}
+	fmt.Fprintf(&b, "\n%s_SCHEMA_ID = %s\n", snake(m.Type), pythonLiteral(m.ID))
+	b.WriteString(body.String())
+	return []byte(b.String())
+}
+
+func allStrings(values []any) bool {
+	for _, v := range values {
+		if _, ok := v.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// pythonLiteral renders a JSON value as Python
+func pythonLiteral(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	default:
+		return literal(v)
+	}
+}
+
+func docstring(s string) string {
+	return `"""` + strings.ReplaceAll(s, `"""`, `\"\"\"`) + `"""`
+}
+
+// enumMember names the member of an enum value, such as PENDING for pending
+func enumMember(value string) string {
+	member := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, value)
+	if member == "" || unicode.IsDigit(rune(member[0])) {
+		member = "V_" + member
+	}
+	return member
+}
+
+// snake converts a Go identifier to upper snake case, such as ORDER_STATUS for OrderStatus
+func snake(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,251 @@
+// Package modeltest checks that the generated models and their JSON schemas agree, by round-tripping fake models
+// through their schema. It is used by the tests generated along with the models.
+package modeltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	schemavalidator "github.com/mfelipe/go-feijoada/schema-validator"
+	"github.com/mfelipe/go-feijoada/schema-validator/config"
+)
+
+const (
+	// iterations is the count of fake models round-tripped for each model
+	iterations = 100
+	// maxItems bounds the length of the arrays of fake models
+	maxItems = 3
+)
+
+// Model is a generated model
+type Model interface {
+	Validate() error
+}
+
+// RoundTrip fakes models of type T from the schema of schemaID, read from schemaFile, and checks that each one:
+//   - passes Validate
+//   - marshals to JSON valid against the schema
+//   - unmarshals back to the same model
+//
+// It then checks that the model and the schema both reject payloads missing a required property, or holding a value
+// out of an enum, and that Validate and the schema both reject models with a nil required array, map or object
+func RoundTrip[T Model](t *testing.T, schemaID, schemaFile string) {
+	t.Helper()
+	schema, validator := load(t, schemaID, schemaFile)
+
+	seed := rand.Uint64()
+	r := rand.New(rand.NewPCG(seed, seed))
+	t.Logf("seed %d", seed)
+
+	var model T
+	for range iterations {
+		value := reflect.ValueOf(&model).Elem()
+		fake(r, value, schema, true)
+		require.NoError(t, model.Validate(), "fake model %+v", model)
+
+		data, err := json.Marshal(model)
+		require.NoError(t, err)
+		report, err := validator.Validate(schemaID, data)
+		require.NoError(t, err)
+		require.True(t, report.Valid, "schema rejects %s: %+v", data, report.Errors)
+
+		var decoded T
+		require.NoError(t, json.Unmarshal(data, &decoded), "model rejects %s", data)
+		require.Equal(t, model, decoded)
+
+		rejections(t, validator, schemaID, schema, data, reflect.TypeFor[T]())
+		nilRejections(t, validator, schemaID, schema, model)
+	}
+}
+
+// Convert fakes models of type From from the schema read from schemaFile, and checks that convert converts each one to
+// a To passing Validate
+func Convert[From, To Model](t *testing.T, schemaFile string, convert func(From) (To, error)) {
+	t.Helper()
+	data, err := os.ReadFile(schemaFile)
+	require.NoError(t, err)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	seed := rand.Uint64()
+	r := rand.New(rand.NewPCG(seed, seed))
+	t.Logf("seed %d", seed)
+
+	var model From
+	for range iterations {
+		fake(r, reflect.ValueOf(&model).Elem(), schema, true)
+		converted, err := convert(model)
+		require.NoError(t, err, "converting %+v", model)
+		require.NoError(t, converted.Validate(), "converted model %+v", converted)
+	}
+}
+
+// load reads the schema of schemaID from schemaFile, and a validator knowing it
+func load(t *testing.T, schemaID, schemaFile string) (map[string]any, schemavalidator.SchemaValidator) {
+	t.Helper()
+	data, err := os.ReadFile(schemaFile)
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	validator := schemavalidator.New(config.Config{DefaultBaseURI: "http://schema-repository:8080", Offline: true})
+	require.NoError(t, validator.AddSchema(schemaID, data))
+	return schema, validator
+}
+
+// rejections checks that payloads missing a required property, or holding a value out of an enum, are rejected by
+// both the model and the schema
+func rejections(t *testing.T, validator schemavalidator.SchemaValidator, schemaID string, schema map[string]any, data []byte, modelType reflect.Type) {
+	t.Helper()
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(data, &payload))
+
+	properties, _ := schema["properties"].(map[string]any)
+	check := func(invalid map[string]any, reason string) {
+		data, err := json.Marshal(invalid)
+		require.NoError(t, err)
+
+		report, err := validator.Validate(schemaID, data)
+		require.NoError(t, err)
+		assert.False(t, report.Valid, "schema accepts %s %s", reason, data)
+		assert.Error(t, json.Unmarshal(data, reflect.New(modelType).Interface()), "model accepts %s %s", reason, data)
+	}
+
+	required, _ := schema["required"].([]any)
+	for _, name := range required {
+		invalid := maps.Clone(payload)
+		delete(invalid, name.(string))
+		check(invalid, fmt.Sprintf("missing %q", name))
+	}
+	for name, property := range properties {
+		if enum, ok := property.(map[string]any)["enum"]; ok && len(enum.([]any)) > 0 {
+			invalid := maps.Clone(payload)
+			invalid[name] = "out-of-enum"
+			check(invalid, fmt.Sprintf("%q out of its enum", name))
+		}
+	}
+}
+
+// nilRejections checks that a model with a nil required array, map or object, which marshals to null, is rejected by
+// both Validate and the schema, unless the schema allows null
+func nilRejections[T Model](t *testing.T, validator schemavalidator.SchemaValidator, schemaID string, schema map[string]any, model T) {
+	t.Helper()
+	properties, _ := schema["properties"].(map[string]any)
+	required, _ := schema["required"].([]any)
+
+	value := reflect.ValueOf(model)
+	for i := range value.NumField() {
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+		property, _ := properties[name].(map[string]any)
+		switch value.Field(i).Kind() {
+		case reflect.Slice, reflect.Map, reflect.Pointer:
+		default:
+			continue
+		}
+		if !slices.Contains(required, any(name)) || allowsNull(property) {
+			continue
+		}
+
+		invalid := model
+		reflect.ValueOf(&invalid).Elem().Field(i).SetZero()
+		assert.Error(t, invalid.Validate(), "model accepts nil %q", name)
+
+		data, err := json.Marshal(invalid)
+		require.NoError(t, err)
+		report, err := validator.Validate(schemaID, data)
+		require.NoError(t, err)
+		assert.False(t, report.Valid, "schema accepts nil %q %s", name, data)
+	}
+}
+
+// allowsNull tells if a schema declares null among its types
+func allowsNull(schema map[string]any) bool {
+	switch types := schema["type"].(type) {
+	case string:
+		return types == "null"
+	case []any:
+		return slices.Contains(types, any("null"))
+	}
+	return false
+}
+
+// fake fills v with random values of its schema: enum values when it declares an enum, and every property of objects
+// when required, or half of the time otherwise
+func fake(r *rand.Rand, v reflect.Value, schema map[string]any, required bool) {
+	if v.Kind() == reflect.Pointer && !required && r.IntN(2) == 0 {
+		v.SetZero()
+		return
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		setEnum(v, enum[r.IntN(len(enum))])
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fake(r, v.Elem(), schema, true)
+	case reflect.Struct:
+		properties, _ := schema["properties"].(map[string]any)
+		requiredProperties, _ := schema["required"].([]any)
+		for i := range v.NumField() {
+			name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+			subschema, _ := properties[name].(map[string]any)
+			fake(r, v.Field(i), subschema, slices.Contains(requiredProperties, any(name)))
+		}
+	case reflect.Slice:
+		items, _ := schema["items"].(map[string]any)
+		n := r.IntN(maxItems + 1)
+		if n == 0 && !required {
+			// optional properties are omitted when empty, so they decode as nil
+			v.SetZero()
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), n, n)
+		for i := range n {
+			fake(r, s.Index(i), items, true)
+		}
+		v.Set(s)
+	case reflect.String:
+		v.SetString(fakeString(r))
+	case reflect.Bool:
+		v.SetBool(r.IntN(2) == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(r.Int64N(1 << 31))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(r.Uint64N(1 << 31))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(r.IntN(1_000_000)) / 100)
+	default:
+		v.SetZero()
+	}
+}
+
+// setEnum sets v, or the value it points to, to an enum value
+func setEnum(v reflect.Value, value any) {
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	v.Set(reflect.ValueOf(value).Convert(v.Type()))
+}
+
+func fakeString(r *rand.Rand) string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	b := make([]byte, 1+r.IntN(12))
+	for i := range b {
+		b[i] = letters[r.IntN(len(letters))]
+	}
+	return string(b)
+}
@@ -0,0 +1,41 @@
+// Code generated by generate.go, DO NOT EDIT.
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/mfelipe/go-feijoada/schemas/internal/modeltest"
+)
+
+func TestAddressV1ToV2(t *testing.T) {
+	modeltest.Convert(t, "../../schemas/address-1.0.0.json", AddressV1ToV2)
+}
+
+func TestAddressV2ToV1(t *testing.T) {
+	modeltest.Convert(t, "../../schemas/address-2.0.0.json", AddressV2ToV1)
+}
+
+func TestOrderV1ToV2(t *testing.T) {
+	modeltest.Convert(t, "../../schemas/order-1.0.0.json", OrderV1ToV2)
+}
+
+func TestOrderV2ToV1(t *testing.T) {
+	modeltest.Convert(t, "../../schemas/order-2.0.0.json", OrderV2ToV1)
+}
+
+func TestPaymentV1ToV2(t *testing.T) {
+	modeltest.Convert(t, "../../schemas/payment-1.0.0.json", PaymentV1ToV2)
+}
+
+func TestPaymentV2ToV1(t *testing.T) {
+	modeltest.Convert(t, "../../schemas/payment-2.0.0.json", PaymentV2ToV1)
+}
+
+func TestProductV1ToV2(t *testing.T) {
+	modeltest.Convert(t, "../../schemas/product-1.0.0.json", ProductV1ToV2)
+}
+
+func TestProductV2ToV1(t *testing.T) {
+	modeltest.Convert(t, "../../schemas/product-2.0.0.json", ProductV2ToV1)
+}
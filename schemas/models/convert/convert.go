@@ -0,0 +1,162 @@
+// Package convert converts payloads and models between the versions of their schema, following the rules declared in
+// conversions.yaml. The Conversions table and the typed functions, such as OrderV1ToV2, are generated from them by
+// generate.go.
+package convert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mfelipe/go-feijoada/schemas"
+)
+
+// ErrNoConversion is returned when no conversion, or chain of conversions, leads to the requested schema
+var ErrNoConversion = errors.New("no conversion")
+
+// Conversion converts the payloads of a schema to another version of it
+type Conversion struct {
+	// From and To are the IDs of the schemas
+	From string
+	To   string
+	// Up is set when To is a later version than From
+	Up    bool
+	Rules Rules
+}
+
+// Rules apply to the top-level properties of a payload. Properties they don't mention are copied as is
+type Rules struct {
+	// Rename maps properties of the source to their name in the target
+	Rename map[string]string
+	// Drop lists the properties of the source the target doesn't have
+	Drop []string
+	// Defaults are the JSON values of properties of the target the source lacks
+	Defaults map[string]json.RawMessage
+}
+
+// Apply converts a JSON object: properties are renamed, then dropped, then defaulted when missing
+func (r Rules) Apply(data []byte) ([]byte, error) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+
+	renamed := make(map[string]json.RawMessage, len(r.Rename))
+	for from, to := range r.Rename {
+		if value, ok := payload[from]; ok {
+			renamed[to] = value
+			delete(payload, from)
+		}
+	}
+	for name, value := range renamed {
+		payload[name] = value
+	}
+	for _, name := range r.Drop {
+		delete(payload, name)
+	}
+	for name, value := range r.Defaults {
+		if _, ok := payload[name]; !ok {
+			payload[name] = value
+		}
+	}
+	return json.Marshal(payload)
+}
+
+// Convert converts a payload of schemaURI to the schema toID, chaining the fewest conversions. The result is checked
+// against the model of toID
+func Convert(schemaURI string, data []byte, toID string) ([]byte, error) {
+	fromID, err := schemas.ID(schemaURI)
+	if err != nil {
+		return nil, err
+	}
+	path, err := route(fromID, toID)
+	if err != nil {
+		return nil, err
+	}
+	return apply(path, data)
+}
+
+// Upcast converts a payload of schemaURI to the latest version of its schema it converts up to, returning the ID of
+// that schema. Payloads of schemas without upcasts, such as the latest version, are returned as is
+func Upcast(schemaURI string, data []byte) (string, []byte, error) {
+	id, err := schemas.ID(schemaURI)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var path []Conversion
+	for visited := map[string]bool{id: true}; ; {
+		next, ok := upcast(id)
+		if !ok || visited[next.To] {
+			break
+		}
+		path = append(path, next)
+		id = next.To
+		visited[id] = true
+	}
+	if len(path) == 0 {
+		return schemaURI, data, nil
+	}
+
+	converted, err := apply(path, data)
+	if err != nil {
+		return "", nil, err
+	}
+	return id, converted, nil
+}
+
+// upcast returns the first upcast of the schema id in Conversions, the generator listing the ones skipping the most
+// versions first
+func upcast(id string) (Conversion, bool) {
+	for _, c := range Conversions {
+		if c.Up && c.From == id {
+			return c, true
+		}
+	}
+	return Conversion{}, false
+}
+
+// route finds the shortest chain of conversions from one schema to another
+func route(fromID, toID string) ([]Conversion, error) {
+	paths := map[string][]Conversion{fromID: {}}
+	for queue := []string{fromID}; len(queue) > 0; queue = queue[1:] {
+		id := queue[0]
+		if id == toID {
+			return paths[id], nil
+		}
+		for _, c := range Conversions {
+			if _, seen := paths[c.To]; c.From != id || seen {
+				continue
+			}
+			paths[c.To] = append(append([]Conversion{}, paths[id]...), c)
+			queue = append(queue, c.To)
+		}
+	}
+	return nil, fmt.Errorf("%w from %s to %s", ErrNoConversion, fromID, toID)
+}
+
+func apply(path []Conversion, data []byte) ([]byte, error) {
+	var err error
+	for _, c := range path {
+		if data, err = c.Rules.Apply(data); err != nil {
+			return nil, fmt.Errorf("converting %s to %s: %w", c.From, c.To, err)
+		}
+		if _, err = schemas.Decode(c.To, data); err != nil {
+			return nil, fmt.Errorf("converting %s to %s: %w", c.From, c.To, err)
+		}
+	}
+	return data, nil
+}
+
+// model converts a model of the schema fromID to the model of the schema toID
+func model[T any](from any, fromID, toID string) (T, error) {
+	var to T
+	data, err := json.Marshal(from)
+	if err != nil {
+		return to, err
+	}
+	if data, err = Convert(fromID, data, toID); err != nil {
+		return to, err
+	}
+	return to, json.Unmarshal(data, &to)
+}
@@ -0,0 +1,132 @@
+package convert
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schemas"
+	"github.com/mfelipe/go-feijoada/schemas/models/v1_0_0"
+	"github.com/mfelipe/go-feijoada/schemas/models/v2_0_0"
+)
+
+func TestRules_Apply(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		data  string
+		want  string
+		err   bool
+	}{
+		{name: "copies", data: `{"a": 1, "b": [2]}`, want: `{"a": 1, "b": [2]}`},
+		{
+			name:  "renames",
+			rules: Rules{Rename: map[string]string{"a": "b", "b": "a"}},
+			data:  `{"a": 1, "b": 2}`,
+			want:  `{"a": 2, "b": 1}`,
+		},
+		{name: "drops", rules: Rules{Drop: []string{"b"}}, data: `{"a": 1, "b": 2}`, want: `{"a": 1}`},
+		{
+			name:  "defaults missing",
+			rules: Rules{Defaults: map[string]json.RawMessage{"a": json.RawMessage(`0`), "b": json.RawMessage(`"x"`)}},
+			data:  `{"a": 1}`,
+			want:  `{"a": 1, "b": "x"}`,
+		},
+		{name: "not an object", data: `[1]`, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rules.Apply([]byte(tt.data))
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	got, err := Convert(v2_0_0.OrderSchemaID, []byte(`{"orderId": 1, "productIds": [2], "status": "shipped", "total": 9.5, "userId": 4}`), v1_0_0.OrderSchemaID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"orderId": 1, "productIds": [2], "total": 9.5, "userId": 4}`, string(got))
+
+	_, err = Convert(v1_0_0.OrderSchemaID, []byte(`{"orderId": 1, "productIds": [2], "total": 9.5, "userId": 4}`), v2_0_0.PaymentSchemaID)
+	assert.ErrorIs(t, err, ErrNoConversion)
+
+	_, err = Convert("http://localhost:8080/schemas/order/9.0.0", []byte(`{}`), v2_0_0.OrderSchemaID)
+	assert.ErrorIs(t, err, schemas.ErrUnknownSchema)
+
+	_, err = Convert(v1_0_0.OrderSchemaID, []byte(`{"orderId": 1}`), v2_0_0.OrderSchemaID)
+	assert.ErrorContains(t, err, "field productIds in Order: required")
+}
+
+func TestUpcast(t *testing.T) {
+	tests := []struct {
+		name      string
+		schemaURI string
+		data      string
+		wantID    string
+		want      string
+		err       error
+	}{
+		{
+			name:      "earlier version",
+			schemaURI: "http://localhost:8080/schemas/product/1.0.0",
+			data:      `{"id": 1, "name": "Feijão", "price": 9.5}`,
+			wantID:    v2_0_0.ProductSchemaID,
+			want:      `{"id": 1, "name": "Feijão", "price": 9.5}`,
+		},
+		{
+			name:      "latest version",
+			schemaURI: v2_0_0.ProductSchemaID,
+			data:      `{"id": 1, "name": "Feijão", "price": 9.5, "tags": ["grain"]}`,
+			wantID:    v2_0_0.ProductSchemaID,
+			want:      `{"id": 1, "name": "Feijão", "price": 9.5, "tags": ["grain"]}`,
+		},
+		{
+			name:      "without versions",
+			schemaURI: v1_0_0.UserSchemaID,
+			data:      `{"id": 1, "name": "Ana", "email": "ana@example.com"}`,
+			wantID:    v1_0_0.UserSchemaID,
+			want:      `{"id": 1, "name": "Ana", "email": "ana@example.com"}`,
+		},
+		{
+			name:      "unknown schema",
+			schemaURI: "http://localhost:8080/schemas/invoice/1.0.0",
+			data:      `{}`,
+			err:       schemas.ErrUnknownSchema,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, got, err := Upcast(tt.schemaURI, []byte(tt.data))
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, id)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestOrderConversions(t *testing.T) {
+	shipped := v2_0_0.OrderStatusShipped
+	order := v2_0_0.Order{OrderId: 1, ProductIds: []int{2}, Status: &shipped, Total: 9.5, UserId: 4}
+
+	down, err := OrderV2ToV1(order)
+	require.NoError(t, err)
+	assert.Equal(t, v1_0_0.Order{OrderId: 1, ProductIds: []int{2}, Total: 9.5, UserId: 4}, down)
+
+	up, err := OrderV1ToV2(down)
+	require.NoError(t, err)
+	order.Status = nil
+	assert.Equal(t, order, up)
+}
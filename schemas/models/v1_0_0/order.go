@@ -2,6 +2,10 @@
 
 package v1_0_0
 
+import "encoding/json"
+import "fmt"
+import yaml "gopkg.in/yaml.v3"
+
 // An order placed by a user
 type Order struct {
 	// OrderId corresponds to the JSON schema field "orderId".
@@ -16,3 +20,57 @@ type Order struct {
 	// UserId corresponds to the JSON schema field "userId".
 	UserId int `json:"userId" yaml:"userId" mapstructure:"userId"`
 }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Order) UnmarshalJSON(value []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(value, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["orderId"]; raw != nil && !ok {
+		return fmt.Errorf("field orderId in Order: required")
+	}
+	if _, ok := raw["productIds"]; raw != nil && !ok {
+		return fmt.Errorf("field productIds in Order: required")
+	}
+	if _, ok := raw["total"]; raw != nil && !ok {
+		return fmt.Errorf("field total in Order: required")
+	}
+	if _, ok := raw["userId"]; raw != nil && !ok {
+		return fmt.Errorf("field userId in Order: required")
+	}
+	type Plain Order
+	var plain Plain
+	if err := json.Unmarshal(value, &plain); err != nil {
+		return err
+	}
+	*j = Order(plain)
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (j *Order) UnmarshalYAML(value *yaml.Node) error {
+	var raw map[string]interface{}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	if _, ok := raw["orderId"]; raw != nil && !ok {
+		return fmt.Errorf("field orderId in Order: required")
+	}
+	if _, ok := raw["productIds"]; raw != nil && !ok {
+		return fmt.Errorf("field productIds in Order: required")
+	}
+	if _, ok := raw["total"]; raw != nil && !ok {
+		return fmt.Errorf("field total in Order: required")
+	}
+	if _, ok := raw["userId"]; raw != nil && !ok {
+		return fmt.Errorf("field userId in Order: required")
+	}
+	type Plain Order
+	var plain Plain
+	if err := value.Decode(&plain); err != nil {
+		return err
+	}
+	*j = Order(plain)
+	return nil
+}
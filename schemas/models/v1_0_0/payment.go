@@ -2,6 +2,10 @@
 
 package v1_0_0
 
+import "encoding/json"
+import "fmt"
+import yaml "gopkg.in/yaml.v3"
+
 // Payment information for an order
 type Payment struct {
 	// Amount corresponds to the JSON schema field "amount".
@@ -16,3 +20,57 @@ type Payment struct {
 	// PaymentId corresponds to the JSON schema field "paymentId".
 	PaymentId int `json:"paymentId" yaml:"paymentId" mapstructure:"paymentId"`
 }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Payment) UnmarshalJSON(value []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(value, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["amount"]; raw != nil && !ok {
+		return fmt.Errorf("field amount in Payment: required")
+	}
+	if _, ok := raw["method"]; raw != nil && !ok {
+		return fmt.Errorf("field method in Payment: required")
+	}
+	if _, ok := raw["orderId"]; raw != nil && !ok {
+		return fmt.Errorf("field orderId in Payment: required")
+	}
+	if _, ok := raw["paymentId"]; raw != nil && !ok {
+		return fmt.Errorf("field paymentId in Payment: required")
+	}
+	type Plain Payment
+	var plain Plain
+	if err := json.Unmarshal(value, &plain); err != nil {
+		return err
+	}
+	*j = Payment(plain)
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (j *Payment) UnmarshalYAML(value *yaml.Node) error {
+	var raw map[string]interface{}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	if _, ok := raw["amount"]; raw != nil && !ok {
+		return fmt.Errorf("field amount in Payment: required")
+	}
+	if _, ok := raw["method"]; raw != nil && !ok {
+		return fmt.Errorf("field method in Payment: required")
+	}
+	if _, ok := raw["orderId"]; raw != nil && !ok {
+		return fmt.Errorf("field orderId in Payment: required")
+	}
+	if _, ok := raw["paymentId"]; raw != nil && !ok {
+		return fmt.Errorf("field paymentId in Payment: required")
+	}
+	type Plain Payment
+	var plain Plain
+	if err := value.Decode(&plain); err != nil {
+		return err
+	}
+	*j = Payment(plain)
+	return nil
+}
@@ -2,6 +2,10 @@
 
 package v2_0_0
 
+import "encoding/json"
+import "fmt"
+import yaml "gopkg.in/yaml.v3"
+
 // A user's address (v2)
 type Address struct {
 	// City corresponds to the JSON schema field "city".
@@ -16,3 +20,51 @@ type Address struct {
 	// Street corresponds to the JSON schema field "street".
 	Street string `json:"street" yaml:"street" mapstructure:"street"`
 }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Address) UnmarshalJSON(value []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(value, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["city"]; raw != nil && !ok {
+		return fmt.Errorf("field city in Address: required")
+	}
+	if _, ok := raw["postalCode"]; raw != nil && !ok {
+		return fmt.Errorf("field postalCode in Address: required")
+	}
+	if _, ok := raw["street"]; raw != nil && !ok {
+		return fmt.Errorf("field street in Address: required")
+	}
+	type Plain Address
+	var plain Plain
+	if err := json.Unmarshal(value, &plain); err != nil {
+		return err
+	}
+	*j = Address(plain)
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (j *Address) UnmarshalYAML(value *yaml.Node) error {
+	var raw map[string]interface{}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	if _, ok := raw["city"]; raw != nil && !ok {
+		return fmt.Errorf("field city in Address: required")
+	}
+	if _, ok := raw["postalCode"]; raw != nil && !ok {
+		return fmt.Errorf("field postalCode in Address: required")
+	}
+	if _, ok := raw["street"]; raw != nil && !ok {
+		return fmt.Errorf("field street in Address: required")
+	}
+	type Plain Address
+	var plain Plain
+	if err := value.Decode(&plain); err != nil {
+		return err
+	}
+	*j = Address(plain)
+	return nil
+}
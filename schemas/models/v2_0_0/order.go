@@ -2,6 +2,11 @@
 
 package v2_0_0
 
+import "encoding/json"
+import "fmt"
+import yaml "gopkg.in/yaml.v3"
+import "reflect"
+
 // An order placed by a user (v2)
 type Order struct {
 	// OrderId corresponds to the JSON schema field "orderId".
@@ -26,3 +31,104 @@ const OrderStatusCancelled OrderStatus = "cancelled"
 const OrderStatusDelivered OrderStatus = "delivered"
 const OrderStatusPending OrderStatus = "pending"
 const OrderStatusShipped OrderStatus = "shipped"
+
+var enumValues_OrderStatus = []interface{}{
+	"pending",
+	"shipped",
+	"delivered",
+	"cancelled",
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *OrderStatus) UnmarshalJSON(value []byte) error {
+	var v string
+	if err := json.Unmarshal(value, &v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_OrderStatus {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_OrderStatus, v)
+	}
+	*j = OrderStatus(v)
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (j *OrderStatus) UnmarshalYAML(value *yaml.Node) error {
+	var v string
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_OrderStatus {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_OrderStatus, v)
+	}
+	*j = OrderStatus(v)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Order) UnmarshalJSON(value []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(value, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["orderId"]; raw != nil && !ok {
+		return fmt.Errorf("field orderId in Order: required")
+	}
+	if _, ok := raw["productIds"]; raw != nil && !ok {
+		return fmt.Errorf("field productIds in Order: required")
+	}
+	if _, ok := raw["total"]; raw != nil && !ok {
+		return fmt.Errorf("field total in Order: required")
+	}
+	if _, ok := raw["userId"]; raw != nil && !ok {
+		return fmt.Errorf("field userId in Order: required")
+	}
+	type Plain Order
+	var plain Plain
+	if err := json.Unmarshal(value, &plain); err != nil {
+		return err
+	}
+	*j = Order(plain)
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (j *Order) UnmarshalYAML(value *yaml.Node) error {
+	var raw map[string]interface{}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	if _, ok := raw["orderId"]; raw != nil && !ok {
+		return fmt.Errorf("field orderId in Order: required")
+	}
+	if _, ok := raw["productIds"]; raw != nil && !ok {
+		return fmt.Errorf("field productIds in Order: required")
+	}
+	if _, ok := raw["total"]; raw != nil && !ok {
+		return fmt.Errorf("field total in Order: required")
+	}
+	if _, ok := raw["userId"]; raw != nil && !ok {
+		return fmt.Errorf("field userId in Order: required")
+	}
+	type Plain Order
+	var plain Plain
+	if err := value.Decode(&plain); err != nil {
+		return err
+	}
+	*j = Order(plain)
+	return nil
+}
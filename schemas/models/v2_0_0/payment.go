@@ -2,6 +2,11 @@
 
 package v2_0_0
 
+import "encoding/json"
+import "fmt"
+import yaml "gopkg.in/yaml.v3"
+import "reflect"
+
 // Payment information for an order (v2)
 type Payment struct {
 	// Amount corresponds to the JSON schema field "amount".
@@ -25,3 +30,103 @@ type PaymentStatus string
 const PaymentStatusCompleted PaymentStatus = "completed"
 const PaymentStatusFailed PaymentStatus = "failed"
 const PaymentStatusPending PaymentStatus = "pending"
+
+var enumValues_PaymentStatus = []interface{}{
+	"pending",
+	"completed",
+	"failed",
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *PaymentStatus) UnmarshalJSON(value []byte) error {
+	var v string
+	if err := json.Unmarshal(value, &v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_PaymentStatus {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_PaymentStatus, v)
+	}
+	*j = PaymentStatus(v)
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (j *PaymentStatus) UnmarshalYAML(value *yaml.Node) error {
+	var v string
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_PaymentStatus {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_PaymentStatus, v)
+	}
+	*j = PaymentStatus(v)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Payment) UnmarshalJSON(value []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(value, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["amount"]; raw != nil && !ok {
+		return fmt.Errorf("field amount in Payment: required")
+	}
+	if _, ok := raw["method"]; raw != nil && !ok {
+		return fmt.Errorf("field method in Payment: required")
+	}
+	if _, ok := raw["orderId"]; raw != nil && !ok {
+		return fmt.Errorf("field orderId in Payment: required")
+	}
+	if _, ok := raw["paymentId"]; raw != nil && !ok {
+		return fmt.Errorf("field paymentId in Payment: required")
+	}
+	type Plain Payment
+	var plain Plain
+	if err := json.Unmarshal(value, &plain); err != nil {
+		return err
+	}
+	*j = Payment(plain)
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (j *Payment) UnmarshalYAML(value *yaml.Node) error {
+	var raw map[string]interface{}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	if _, ok := raw["amount"]; raw != nil && !ok {
+		return fmt.Errorf("field amount in Payment: required")
+	}
+	if _, ok := raw["method"]; raw != nil && !ok {
+		return fmt.Errorf("field method in Payment: required")
+	}
+	if _, ok := raw["orderId"]; raw != nil && !ok {
+		return fmt.Errorf("field orderId in Payment: required")
+	}
+	if _, ok := raw["paymentId"]; raw != nil && !ok {
+		return fmt.Errorf("field paymentId in Payment: required")
+	}
+	type Plain Payment
+	var plain Plain
+	if err := value.Decode(&plain); err != nil {
+		return err
+	}
+	*j = Payment(plain)
+	return nil
+}
@@ -0,0 +1,90 @@
+// Package schemas maps the schemas of the module to their generated models, so payloads are decoded into the model of
+// their schema URI. The registry of the models, Types, is generated along with them by generate.go.
+package schemas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// ErrUnknownSchema is returned for schema URIs without a generated model
+var ErrUnknownSchema = errors.New("no model generated for schema")
+
+// byPath indexes Types by the /schemas/<name>/<version> path of their ID, so schema URIs of another host resolve too
+var byPath = func() map[string]reflect.Type {
+	m := make(map[string]reflect.Type, len(Types))
+	for id, t := range Types {
+		m[schemaPath(id)] = t
+	}
+	return m
+}()
+
+func schemaPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	if i := strings.LastIndex(u.Path, "/schemas/"); i >= 0 {
+		return u.Path[i:]
+	}
+	return ""
+}
+
+// Type returns the type of the model of a schema URI, matched by its ID, or by its /schemas/<name>/<version> path
+func Type(schemaURI string) (reflect.Type, error) {
+	if t, ok := Types[schemaURI]; ok {
+		return t, nil
+	}
+	if t, ok := byPath[schemaPath(schemaURI)]; ok && t != nil {
+		return t, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownSchema, schemaURI)
+}
+
+// ID returns the ID of the schema of a schema URI, matched as Type matches it
+func ID(schemaURI string) (string, error) {
+	t, err := Type(schemaURI)
+	if err != nil {
+		return "", err
+	}
+	return SchemaID(reflect.New(t).Interface())
+}
+
+// New returns a pointer to a new model of a schema URI, such as *v2_0_0.Order
+func New(schemaURI string) (any, error) {
+	t, err := Type(schemaURI)
+	if err != nil {
+		return nil, err
+	}
+	return reflect.New(t).Interface(), nil
+}
+
+// Decode unmarshals a JSON payload into a pointer to the model of its schema URI, such as the data of a stream message
+func Decode(schemaURI string, data []byte) (any, error) {
+	model, err := New(schemaURI)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, model); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", schemaURI, err)
+	}
+	return model, nil
+}
+
+// SchemaID returns the ID of the schema of a model, or of a pointer to a model
+func SchemaID(model any) (string, error) {
+	t := reflect.TypeOf(model)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for id, modelType := range Types {
+		if modelType == t {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %T isn't a model", ErrUnknownSchema, model)
+}
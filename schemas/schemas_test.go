@@ -0,0 +1,105 @@
+package schemas
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfelipe/go-feijoada/schemas/models/v1_0_0"
+	"github.com/mfelipe/go-feijoada/schemas/models/v2_0_0"
+)
+
+func TestDecode(t *testing.T) {
+	shipped := v2_0_0.OrderStatusShipped
+
+	tests := []struct {
+		name      string
+		schemaURI string
+		data      string
+		model     any
+		err       string
+	}{
+		{
+			name:      "by ID",
+			schemaURI: v2_0_0.OrderSchemaID,
+			data:      `{"orderId": 1, "productIds": [2, 3], "status": "shipped", "total": 9.5, "userId": 4}`,
+			model:     &v2_0_0.Order{OrderId: 1, ProductIds: []int{2, 3}, Status: &shipped, Total: 9.5, UserId: 4},
+		},
+		{
+			name:      "by path",
+			schemaURI: "http://localhost:8080/schemas/user/1.0.0",
+			data:      `{"id": 1, "name": "Ana", "email": "ana@example.com"}`,
+			model:     &v1_0_0.User{Id: 1, Name: "Ana", Email: "ana@example.com"},
+		},
+		{
+			name:      "missing required",
+			schemaURI: v1_0_0.UserSchemaID,
+			data:      `{"id": 1, "name": "Ana"}`,
+			err:       "decoding http://schema-repository:8080/schemas/user/1.0.0: field email in User: required",
+		},
+		{
+			name:      "out of enum",
+			schemaURI: v2_0_0.PaymentSchemaID,
+			data:      `{"paymentId": 1, "orderId": 2, "amount": 3, "method": "pix", "status": "refunded"}`,
+			err:       `decoding http://schema-repository:8080/schemas/payment/2.0.0: invalid value (expected one of []interface {}{"pending", "completed", "failed"}): "refunded"`,
+		},
+		{name: "unknown schema", schemaURI: "http://schema-repository:8080/schemas/invoice/1.0.0", data: `{}`, err: "no model generated for schema: http://schema-repository:8080/schemas/invoice/1.0.0"},
+		{name: "not a schema", schemaURI: "order", data: `{}`, err: "no model generated for schema: order"},
+		{name: "malformed", schemaURI: v1_0_0.OrderSchemaID, data: `{"orderId": "1"`, err: "decoding http://schema-repository:8080/schemas/order/1.0.0: unexpected end of JSON input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := Decode(tt.schemaURI, []byte(tt.data))
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.model, model)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	refunded := v2_0_0.PaymentStatus("refunded")
+	assert.NoError(t, v2_0_0.Payment{PaymentId: 1, Method: "pix"}.Validate())
+	assert.EqualError(t, v2_0_0.Payment{PaymentId: 1, Method: "pix", Status: &refunded}.Validate(),
+		`invalid value (expected one of []interface {}{"pending", "completed", "failed"}): "refunded"`)
+
+	// Nil required arrays marshal to null, which the unmarshaler can't tell from a present property
+	assert.NoError(t, v2_0_0.Order{OrderId: 1, UserId: 1, ProductIds: []int{}}.Validate())
+	assert.EqualError(t, v2_0_0.Order{OrderId: 1, UserId: 1}.Validate(), "field productIds in Order: required")
+}
+
+func TestSchemaID(t *testing.T) {
+	id, err := SchemaID(&v2_0_0.Payment{})
+	require.NoError(t, err)
+	assert.Equal(t, "http://schema-repository:8080/schemas/payment/2.0.0", id)
+
+	_, err = SchemaID(v2_0_0.PaymentStatusFailed)
+	assert.ErrorIs(t, err, ErrUnknownSchema)
+}
+
+func TestID(t *testing.T) {
+	id, err := ID("http://localhost:8080/schemas/order/1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, v1_0_0.OrderSchemaID, id)
+
+	_, err = ID("http://localhost:8080/schemas/order/9.0.0")
+	assert.ErrorIs(t, err, ErrUnknownSchema)
+}
+
+func TestTypes(t *testing.T) {
+	for id, modelType := range Types {
+		model, err := New(id)
+		require.NoError(t, err)
+		assert.Equal(t, modelType, reflect.TypeOf(model).Elem())
+
+		got, err := SchemaID(model)
+		require.NoError(t, err)
+		assert.Equal(t, id, got)
+	}
+	assert.Len(t, Types, 9)
+}
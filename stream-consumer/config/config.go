@@ -30,6 +30,8 @@ type Config struct {
 type Consumer struct {
 	BatchSize int           `json:"batchSize" koanf:"batchSize,required,gt=5"`
 	Interval  time.Duration `json:"interval" koanf:"interval,required"`
+	// Upcast converts the messages to the latest version of their schema before persisting them
+	Upcast bool `json:"upcast" koanf:"upcast"`
 }
 
 type DynamoDB struct {
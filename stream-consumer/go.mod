@@ -3,6 +3,8 @@ module github.com/mfelipe/go-feijoada/stream-consumer
 go 1.24.3
 
 replace (
+	github.com/mfelipe/go-feijoada/schema-validator => ../schema-validator
+	github.com/mfelipe/go-feijoada/schemas => ../schemas
 	github.com/mfelipe/go-feijoada/stream-buffer => ../stream-buffer
 	github.com/mfelipe/go-feijoada/utils => ../utils
 )
@@ -11,6 +13,7 @@ require (
 	github.com/aws/aws-sdk-go-v2 v1.36.6
 	github.com/aws/aws-sdk-go-v2/config v1.29.18
 	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.44.1
+	github.com/mfelipe/go-feijoada/schemas v0.0.0-00010101000000-000000000000
 	github.com/mfelipe/go-feijoada/stream-buffer v0.0.0-00010101000000-000000000000
 	github.com/mfelipe/go-feijoada/utils v0.0.0-00010101000000-000000000000
 	github.com/rs/zerolog v1.34.0
@@ -2,6 +2,7 @@ package consumer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"time"
@@ -9,7 +10,10 @@ import (
 	"github.com/rs/zerolog"
 	zlog "github.com/rs/zerolog/log"
 
+	"github.com/mfelipe/go-feijoada/schemas"
+	"github.com/mfelipe/go-feijoada/schemas/models/convert"
 	"github.com/mfelipe/go-feijoada/stream-buffer"
+	"github.com/mfelipe/go-feijoada/stream-buffer/models"
 	"github.com/mfelipe/go-feijoada/stream-consumer/config"
 	"github.com/mfelipe/go-feijoada/stream-consumer/internal/dynamo"
 )
@@ -19,6 +23,7 @@ type Consumer struct {
 	dynamo    *dynamo.Client
 	batchSize int
 	interval  time.Duration
+	upcast    bool
 	done      chan struct{}
 }
 
@@ -34,6 +39,7 @@ func New(cfg *config.Config) (*Consumer, error) {
 		dynamo:    dynamoClient,
 		batchSize: cfg.Consumer.BatchSize,
 		interval:  cfg.Consumer.Interval,
+		upcast:    cfg.Consumer.Upcast,
 		done:      make(chan struct{}),
 	}, nil
 }
@@ -70,6 +76,10 @@ func (c *Consumer) processBatch(ctx context.Context) error {
 		return nil
 	}
 
+	if c.upcast {
+		upcast(messages)
+	}
+
 	// Write messages to DynamoDB
 	unpersisted, err := c.dynamo.BatchWrite(ctx, messages)
 
@@ -124,6 +134,26 @@ func (c *Consumer) processBatch(ctx context.Context) error {
 	return nil
 }
 
+// upcast converts the messages to the latest version of their schema. Messages of unknown schemas, or failing to
+// convert, are persisted as read
+func upcast(messages map[string]models.Message) {
+	for id, msg := range messages {
+		schemaID, data, err := convert.Upcast(msg.SchemaURI, msg.Data)
+		if errors.Is(err, schemas.ErrUnknownSchema) {
+			continue
+		}
+		if err != nil {
+			zlog.Warn().Err(err).Str("streamId", id).Str("schemaURI", msg.SchemaURI).Msg("failed to upcast message, persisting it as read")
+			continue
+		}
+		if schemaID != msg.SchemaURI {
+			zlog.Debug().Str("streamId", id).Str("from", msg.SchemaURI).Str("to", schemaID).Msg("message upcast")
+		}
+		msg.SchemaURI, msg.Data = schemaID, data
+		messages[id] = msg
+	}
+}
+
 func (c *Consumer) Close() {
 	close(c.done)
 }
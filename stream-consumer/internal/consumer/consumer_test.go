@@ -0,0 +1,42 @@
+package consumer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mfelipe/go-feijoada/schemas/models/v2_0_0"
+	"github.com/mfelipe/go-feijoada/stream-buffer/models"
+)
+
+func TestUpcast(t *testing.T) {
+	messages := map[string]models.Message{
+		"earlier": {
+			SchemaURI: "http://localhost:8080/schemas/order/1.0.0",
+			Data:      json.RawMessage(`{"orderId": 1, "productIds": [2], "total": 9.5, "userId": 4}`),
+		},
+		"latest": {
+			SchemaURI: v2_0_0.OrderSchemaID,
+			Data:      json.RawMessage(`{"orderId": 1, "productIds": [2], "status": "shipped", "total": 9.5, "userId": 4}`),
+		},
+		"unknown schema": {
+			SchemaURI: "http://localhost:8080/schemas/invoice/1.0.0",
+			Data:      json.RawMessage(`{"invoiceId": 1}`),
+		},
+		"invalid": {
+			SchemaURI: "http://localhost:8080/schemas/order/1.0.0",
+			Data:      json.RawMessage(`{"orderId": 1}`),
+		},
+	}
+
+	upcast(messages)
+
+	assert.Equal(t, v2_0_0.OrderSchemaID, messages["earlier"].SchemaURI)
+	assert.JSONEq(t, `{"orderId": 1, "productIds": [2], "total": 9.5, "userId": 4}`, string(messages["earlier"].Data))
+	assert.Equal(t, v2_0_0.OrderSchemaID, messages["latest"].SchemaURI)
+	assert.JSONEq(t, `{"orderId": 1, "productIds": [2], "status": "shipped", "total": 9.5, "userId": 4}`, string(messages["latest"].Data))
+	assert.Equal(t, "http://localhost:8080/schemas/invoice/1.0.0", messages["unknown schema"].SchemaURI)
+	assert.Equal(t, "http://localhost:8080/schemas/order/1.0.0", messages["invalid"].SchemaURI)
+	assert.JSONEq(t, `{"orderId": 1}`, string(messages["invalid"].Data))
+}
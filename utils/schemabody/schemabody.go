@@ -0,0 +1,32 @@
+// Package schemabody reads the {"type": ..., "schema": ...} bodies schema-repository serves schemas in, so every module
+// fetching schemas from it unwraps them the same way.
+package schemabody
+
+import "encoding/json"
+
+// TypeJSONSchema is the type of JSON Schemas, also assumed for bodies that aren't wrapped
+const TypeJSONSchema = "json-schema"
+
+// jsonTypes are the values of the type keyword of JSON Schemas
+var jsonTypes = map[string]bool{
+	"array": true, "boolean": true, "integer": true, "null": true, "number": true, "object": true, "string": true,
+}
+
+// Unwrap extracts the schema and its type of a schema-repository response body. Any other content is returned as a
+// JSON Schema, so plain schema servers keep working. Wrapped schemas keep their type even when unknown, for callers to
+// reject rather than read them as JSON Schemas.
+func Unwrap(body []byte) (string, json.RawMessage) {
+	var wrapped map[string]json.RawMessage
+	if json.Unmarshal(body, &wrapped) == nil && len(wrapped["schema"]) > 0 {
+		var schemaType string
+		rawType, typed := wrapped["type"]
+		switch {
+		case len(wrapped) == 1:
+			return TypeJSONSchema, wrapped["schema"]
+		// JSON Schemas may have a schema property besides their type keyword, such as {"type": "object", "schema": {}}
+		case len(wrapped) == 2 && typed && json.Unmarshal(rawType, &schemaType) == nil && !jsonTypes[schemaType]:
+			return schemaType, wrapped["schema"]
+		}
+	}
+	return TypeJSONSchema, body
+}
@@ -0,0 +1,33 @@
+package schemabody
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnwrap(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		schemaType string
+		schema     string
+	}{
+		{name: "schema-repository", body: `{"schema": {"type": "object"}}`, schemaType: TypeJSONSchema, schema: `{"type": "object"}`},
+		{name: "typed", body: `{"type": "avro", "schema": "string"}`, schemaType: "avro", schema: `"string"`},
+		{name: "typed JSON Schema", body: `{"type": "json-schema", "schema": {"type": "string"}}`, schemaType: TypeJSONSchema, schema: `{"type": "string"}`},
+		{name: "unknown type", body: `{"type": "xsd", "schema": "<xs:schema/>"}`, schemaType: "xsd", schema: `"<xs:schema/>"`},
+		{name: "plain schema", body: `{"type": "object"}`, schemaType: TypeJSONSchema, schema: `{"type": "object"}`},
+		{name: "plain schema with a schema property", body: `{"type": "object", "schema": {}}`, schemaType: TypeJSONSchema, schema: `{"type": "object", "schema": {}}`},
+		{name: "plain schema with several types", body: `{"type": ["object", "null"], "schema": {}}`, schemaType: TypeJSONSchema, schema: `{"type": ["object", "null"], "schema": {}}`},
+		{name: "boolean schema", body: `true`, schemaType: TypeJSONSchema, schema: `true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaType, schema := Unwrap([]byte(tt.body))
+			assert.Equal(t, tt.schemaType, schemaType)
+			assert.JSONEq(t, tt.schema, string(schema))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package testcontainers
+
+import (
+	"context"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+func StartPostgres(ctx context.Context) *tcpostgres.PostgresContainer {
+	postgresContainer, err := tcpostgres.Run(ctx,
+		"postgres:17-alpine",
+		tcpostgres.WithDatabase("feijoada"),
+		tcpostgres.WithUsername("feijoada"),
+		tcpostgres.WithPassword("feijoada"),
+		tcpostgres.BasicWaitStrategies(),
+		testcontainers.WithLogConsumers(NewStdoutLogConsumer("postgres")),
+	)
+	validateContainerStart(ctx, postgresContainer, err)
+	return postgresContainer
+}
@@ -0,0 +1,47 @@
+package vocabulary
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Built-in formats, known to every vocabulary
+const (
+	// FormatCurrency is an active ISO 4217 currency code, such as BRL
+	FormatCurrency = "iso4217"
+	// FormatPhone is an E.164 phone number: a plus sign and up to 15 digits, such as +5511912345678
+	FormatPhone = "e164"
+)
+
+var builtinFormats = map[string]Format{
+	FormatCurrency: currency,
+	FormatPhone:    phone,
+}
+
+// currencies are the active ISO 4217 codes
+var currencies = func() map[string]bool {
+	codes := make(map[string]bool)
+	for _, code := range strings.Fields(`
+		AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB BOV BRL BSD BTN BWP BYN BZD CAD CDF
+		CHE CHF CHW CLF CLP CNY COP COU CRC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GNF
+		GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR LRD
+		LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK PHP
+		PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP
+		TRY TTD TWD TZS UAH UGX USD USN UYI UYU UYW UZS VED VES VND VUV WST XAF XAG XAU XBA XBB XBC XBD XCD XCG XDR XOF
+		XPD XPF XPT XSU XTS XUA XXX YER ZAR ZMW ZWG`) {
+		codes[code] = true
+	}
+	return codes
+}()
+
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+func currency(value any) bool {
+	s, ok := value.(string)
+	return !ok || currencies[s]
+}
+
+func phone(value any) bool {
+	s, ok := value.(string)
+	return !ok || e164.MatchString(s)
+}
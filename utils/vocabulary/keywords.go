@@ -0,0 +1,69 @@
+package vocabulary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Built-in keywords, known to every vocabulary
+const (
+	// KeywordMaxDecimals limits the decimal places of numbers, such as amounts of a currency
+	KeywordMaxDecimals = "maxDecimals"
+)
+
+var builtinKeywords = map[string]Keyword{
+	KeywordMaxDecimals: maxDecimals,
+}
+
+func maxDecimals(value any) (Check, error) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return nil, errors.New("must be a non-negative integer")
+	}
+	places, err := n.Int64()
+	if err != nil || places < 0 {
+		return nil, errors.New("must be a non-negative integer")
+	}
+
+	return func(value any) error {
+		n, ok := value.(json.Number)
+		if !ok {
+			return nil
+		}
+		if decimals, ok := decimalPlaces(n.String()); ok && decimals > places {
+			return fmt.Errorf("%s has more than %d decimal places", n, places)
+		}
+		return nil
+	}, nil
+}
+
+// decimalPlaces counts the decimal places of a JSON number from its text, instead of scaling it, so neither large
+// limits nor large exponents are costly
+func decimalPlaces(n string) (int64, bool) {
+	mantissa, exponent, _ := strings.Cut(strings.ToLower(n), "e")
+	var exp int64
+	if exponent != "" {
+		var err error
+		if exp, err = strconv.ParseInt(exponent, 10, 64); errors.Is(err, strconv.ErrRange) {
+			// The exponent saturates, which is enough to compare with any limit
+			err = nil
+		}
+		if err != nil {
+			return 0, false
+		}
+	}
+
+	integer, fraction, _ := strings.Cut(mantissa, ".")
+	fraction = strings.TrimRight(fraction, "0")
+	if strings.Trim(integer, "+-0") == "" && fraction == "" {
+		return 0, true
+	}
+	if exp <= int64(len(fraction))-math.MaxInt64 {
+		return math.MaxInt64, true
+	}
+	return max(int64(len(fraction))-exp, 0), true
+}
@@ -0,0 +1,108 @@
+// Package vocabulary defines the formats and keywords schemas may use beyond the JSON Schema specification. It is shared
+// by schema-validator and schema-repository, so a schema registered in the repository is validated the same way.
+package vocabulary
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"sync"
+)
+
+// Config selects how formats are checked, and adds formats defined by a regular expression
+type Config struct {
+	// AssertFormat fails values not matching the format of their schema, formats being only annotations otherwise.
+	// Schemas using unknown formats are rejected when set
+	AssertFormat bool `json:"assertFormat" koanf:"assertFormat"`
+	// Formats are formats matching strings with a regular expression, by name, such as internal ids
+	Formats map[string]string `json:"formats" koanf:"formats"`
+}
+
+// Format reports whether a value matches the format. Values of a type the format doesn't apply to must match
+type Format func(value any) bool
+
+// Keyword compiles the value of a custom keyword into the check of the values of its schema. An error rejects the
+// schema. Numbers of keyword values and of checked values are json.Number
+type Keyword func(value any) (Check, error)
+
+// Check validates a value against a compiled keyword, the error message being reported as the validation failure
+type Check func(value any) error
+
+// Vocabulary holds the formats and keywords known to a validator, and whether formats are asserted
+type Vocabulary struct {
+	mu           sync.RWMutex
+	assertFormat bool
+	formats      map[string]Format
+	keywords     map[string]Keyword
+}
+
+// New returns a vocabulary with the built-in formats and keywords, and the formats of the configuration
+func New(cfg Config) (*Vocabulary, error) {
+	v := &Vocabulary{
+		assertFormat: cfg.AssertFormat,
+		formats:      maps.Clone(builtinFormats),
+		keywords:     maps.Clone(builtinKeywords),
+	}
+	for name, pattern := range cfg.Formats {
+		format, err := PatternFormat(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("format %s: %w", name, err)
+		}
+		v.formats[name] = format
+	}
+	return v, nil
+}
+
+// RegisterFormat adds a format, replacing any other with the same name
+func (v *Vocabulary) RegisterFormat(name string, format Format) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.formats[name] = format
+}
+
+// RegisterKeyword adds a keyword, replacing any other with the same name. Keywords of the JSON Schema specification
+// are still evaluated, so their names must not be used
+func (v *Vocabulary) RegisterKeyword(name string, keyword Keyword) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keywords[name] = keyword
+}
+
+// SetAssertFormat toggles format assertion
+func (v *Vocabulary) SetAssertFormat(assert bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.assertFormat = assert
+}
+
+func (v *Vocabulary) AssertFormat() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.assertFormat
+}
+
+// Formats returns a copy of the registered formats
+func (v *Vocabulary) Formats() map[string]Format {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return maps.Clone(v.formats)
+}
+
+// Keywords returns a copy of the registered keywords
+func (v *Vocabulary) Keywords() map[string]Keyword {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return maps.Clone(v.keywords)
+}
+
+// PatternFormat returns a format matching strings with the regular expression. Other values match
+func PatternFormat(pattern string) (Format, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return func(value any) bool {
+		s, ok := value.(string)
+		return !ok || re.MatchString(s)
+	}, nil
+}
@@ -0,0 +1,104 @@
+package vocabulary
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	v, err := New(Config{AssertFormat: true, Formats: map[string]string{"order-id": `^ord_[0-9a-z]{8}$`}})
+	require.NoError(t, err)
+	assert.True(t, v.AssertFormat())
+
+	formats := v.Formats()
+	tests := []struct {
+		format string
+		value  any
+		want   bool
+	}{
+		{format: FormatCurrency, value: "BRL", want: true},
+		{format: FormatCurrency, value: "brl", want: false},
+		{format: FormatCurrency, value: "ABC", want: false},
+		{format: FormatPhone, value: "+5511912345678", want: true},
+		{format: FormatPhone, value: "5511912345678", want: false},
+		{format: FormatPhone, value: "+0123", want: false},
+		{format: "order-id", value: "ord_1a2b3c4d", want: true},
+		{format: "order-id", value: "ord_1A2B3C4D", want: false},
+		// Formats only apply to strings
+		{format: FormatCurrency, value: 42, want: true},
+		{format: "order-id", value: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			require.Contains(t, formats, tt.format)
+			assert.Equal(t, tt.want, formats[tt.format](tt.value), "%v", tt.value)
+		})
+	}
+
+	_, err = New(Config{Formats: map[string]string{"broken": `[`}})
+	assert.ErrorContains(t, err, "format broken")
+}
+
+func TestVocabulary_Register(t *testing.T) {
+	v, err := New(Config{})
+	require.NoError(t, err)
+	assert.False(t, v.AssertFormat())
+	assert.Contains(t, v.Keywords(), KeywordMaxDecimals)
+
+	v.SetAssertFormat(true)
+	v.RegisterFormat("even", func(value any) bool { return len(value.(string))%2 == 0 })
+	v.RegisterKeyword("forbidden", func(value any) (Check, error) {
+		return func(any) error { return errors.New("forbidden") }, nil
+	})
+
+	assert.True(t, v.AssertFormat())
+	assert.Contains(t, v.Formats(), "even")
+	assert.Contains(t, v.Keywords(), "forbidden")
+
+	// Returned maps are copies
+	delete(v.Formats(), "even")
+	assert.Contains(t, v.Formats(), "even")
+}
+
+func TestMaxDecimals(t *testing.T) {
+	_, err := maxDecimals(json.Number("-1"))
+	assert.Error(t, err)
+	_, err = maxDecimals("2")
+	assert.Error(t, err)
+
+	// Large limits are accepted without being expanded
+	huge, err := maxDecimals(json.Number("9223372036854775807"))
+	require.NoError(t, err)
+	assert.NoError(t, huge(json.Number("1.5")))
+
+	check, err := maxDecimals(json.Number("2"))
+	require.NoError(t, err)
+	tests := []struct {
+		value any
+		valid bool
+	}{
+		{value: json.Number("10"), valid: true},
+		{value: json.Number("10.25"), valid: true},
+		{value: json.Number("10.250"), valid: true},
+		{value: json.Number("1e-2"), valid: true},
+		{value: json.Number("10.255"), valid: false},
+		{value: json.Number("1e-3"), valid: false},
+		{value: json.Number("0.000"), valid: true},
+		{value: json.Number("1.255e1"), valid: true},
+		{value: json.Number("1E-2"), valid: true},
+		{value: json.Number("-10.255"), valid: false},
+		{value: json.Number("1e1000000000"), valid: true},
+		{value: json.Number("1e-1000000000"), valid: false},
+		{value: json.Number("1e-99999999999999999999"), valid: false},
+		// Only numbers are checked
+		{value: "10.255", valid: true},
+	}
+	for _, tt := range tests {
+		err := check(tt.value)
+		assert.Equal(t, tt.valid, err == nil, "%v: %v", tt.value, err)
+	}
+}